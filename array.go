@@ -0,0 +1,309 @@
+package goSci
+
+import (
+	"bytes"
+	"fmt"
+)
+
+/*
+ The element types an Array can hold
+*/
+type Element interface {
+	float32 | float64 | int64 | complex128 | bool
+}
+
+/*
+ An n dimensional array of elements of type T, held in row major order. It
+ complements GsArray, which is fixed to float64 and carries the linear
+ algebra: Array[float32] halves the memory of image data and Array[bool]
+ holds masks. AsType converts between element types and ToGsArray and
+ GsArray.ToArray to and from GsArray.
+*/
+type Array[T Element] struct {
+	data  []T
+	shape []int
+}
+
+/*
+ Creates an Array with shape defined by shape initialized to zero, or false
+ e.g.
+ ZerosOf[float32](480, 640)
+*/
+func ZerosOf[T Element](shape ...int) *Array[T] {
+	return &Array[T]{data: make([]T, shapeSize(shape)), shape: append([]int(nil), shape...)}
+}
+
+/*
+ Creates an Array with shape defined by shape initialized to one, or true
+*/
+func OnesOf[T Element](shape ...int) *Array[T] {
+	array := ZerosOf[T](shape...)
+	one := fromFloat[T](1)
+	for i := range array.data {
+		array.data[i] = one
+	}
+	return array
+}
+
+/*
+ Creates a one dimensional Array with the range 0 to size - 1
+*/
+func ArangeOf[T Element](size int) *Array[T] {
+	array := ZerosOf[T](size)
+	for i := range array.data {
+		array.data[i] = fromFloat[T](float64(i))
+	}
+	return array
+}
+
+/*
+ Creates an identity matrix of size size
+*/
+func EyeOf[T Element](size int) *Array[T] {
+	array := ZerosOf[T](size, size)
+	one := fromFloat[T](1)
+	for i := 0; i < size; i++ {
+		array.data[i*size+i] = one
+	}
+	return array
+}
+
+/*
+ Creates an Array with shape defined by shape that holds data in row major
+ order. The array shares data rather than copying it.
+*/
+func ArrayFromSlice[T Element](data []T, shape ...int) *Array[T] {
+	return must(TryArrayFromSlice(data, shape...))
+}
+
+/*
+ Same as ArrayFromSlice but returns a ShapeMismatchError instead of panicking
+ when the length of data is not the product of shape
+*/
+func TryArrayFromSlice[T Element](data []T, shape ...int) (*Array[T], error) {
+	if shapeSize(shape) != len(data) {
+		return nil, shapeError("ArrayFromSlice", []int{len(data)}, shape)
+	}
+	return &Array[T]{data: data, shape: append([]int(nil), shape...)}, nil
+}
+
+/*
+ Returns a copy of x with elements converted to type U. Numbers convert as
+ in Go, dropping the imaginary part of complex numbers; true becomes one and
+ false zero, and a number becomes true when it is nonzero.
+ e.g.
+ mask := AsType[bool](ArangeOf[int64](4))
+*/
+func AsType[U, T Element](x *Array[T]) *Array[U] {
+	result := &Array[U]{data: make([]U, len(x.data)), shape: append([]int(nil), x.shape...)}
+	if same, ok := any(x.data).([]U); ok {
+		copy(result.data, same)
+		return result
+	}
+	for i, val := range x.data {
+		result.data[i] = fromComplex[U](toComplex(val))
+	}
+	return result
+}
+
+/*
+ Returns a copy of x as an Array[float64]
+*/
+func (x *GsArray) ToArray() *Array[float64] {
+	return &Array[float64]{data: x.Data(), shape: x.Shape()}
+}
+
+/*
+ Returns a copy of x as a GsArray, converting the elements as AsType does
+*/
+func ToGsArray[T Element](x *Array[T]) *GsArray {
+	converted := AsType[float64](x)
+	return &GsArray{data: converted.data, shape: converted.shape}
+}
+
+/*
+ Returns a copy of the elements of x in row major order
+*/
+func (x *Array[T]) Data() []T {
+	return append([]T(nil), x.data...)
+}
+
+func (x *Array[T]) Shape() []int {
+	return append([]int(nil), x.shape...)
+}
+
+/*
+ Returns the number of elements in x
+*/
+func (x *Array[T]) Size() int {
+	return len(x.data)
+}
+
+/*
+ Returns a copy of x that does not share data with it
+*/
+func (x *Array[T]) Copy() *Array[T] {
+	return &Array[T]{data: x.Data(), shape: x.Shape()}
+}
+
+/*
+ Reshapes x to shape. The product of shape must be the size of x.
+*/
+func (x *Array[T]) Reshape(shape ...int) {
+	if err := x.TryReshape(shape...); err != nil {
+		panic(err)
+	}
+}
+
+/*
+ Same as Reshape but returns a ShapeMismatchError instead of panicking
+*/
+func (x *Array[T]) TryReshape(shape ...int) error {
+	if shapeSize(shape) != len(x.data) {
+		return shapeError("Reshape", x.shape, shape)
+	}
+	x.shape = append([]int(nil), shape...)
+	return nil
+}
+
+/*
+ Puts val into the position given by pos
+*/
+func (x *Array[T]) Put(val T, pos ...int) {
+	if err := x.TryPut(val, pos...); err != nil {
+		panic(err)
+	}
+}
+
+/*
+ Same as Put but returns an IndexOutOfRangeError instead of panicking
+*/
+func (x *Array[T]) TryPut(val T, pos ...int) error {
+	idx, err := packedIndex(x.shape, pos)
+	if err != nil {
+		return err
+	}
+	x.data[idx] = val
+	return nil
+}
+
+/*
+ Returns the element at position pos
+*/
+func (x *Array[T]) Get(pos ...int) T {
+	return must(x.TryGet(pos...))
+}
+
+/*
+ Same as Get but returns an IndexOutOfRangeError instead of panicking
+*/
+func (x *Array[T]) TryGet(pos ...int) (T, error) {
+	idx, err := packedIndex(x.shape, pos)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return x.data[idx], nil
+}
+
+/*
+ Prints arrays of dimension two or less, a row to a line
+*/
+func (x *Array[T]) String() string {
+	if len(x.shape) > 2 {
+		return "I only print arrays with dimension less than 3."
+	}
+	buff := bytes.NewBufferString("")
+	for i, val := range x.data {
+		if len(x.shape) == 2 && i%x.shape[1] == 0 && i != 0 {
+			fmt.Fprint(buff, "\n")
+		}
+		fmt.Fprintf(buff, "%v ", val)
+	}
+	return buff.String()
+}
+
+/*
+ Returns the strides needed to read x as if it had shape shape, as
+ broadcastStrides does for a GsArray
+*/
+func arrayStrides[T Element](x *Array[T], shape []int) []int {
+	return broadcastStrides(&GsArray{shape: x.shape}, shape)
+}
+
+/*
+ Returns the index in row major order of the element at position pos of an
+ array of shape shape
+*/
+func packedIndex(shape, pos []int) (int, error) {
+	if len(shape) != len(pos) {
+		return 0, &IndexOutOfRangeError{Axis: -1, Index: len(pos), Size: len(shape)}
+	}
+	idx := 0
+	for i, p := range pos {
+		if p < 0 || p >= shape[i] {
+			return 0, &IndexOutOfRangeError{Axis: i, Index: p, Size: shape[i]}
+		}
+		idx = idx*shape[i] + p
+	}
+	return idx, nil
+}
+
+/*
+ Returns the number of elements of an array of shape shape
+*/
+func shapeSize(shape []int) int {
+	product := 1
+	for _, dim := range shape {
+		product *= dim
+	}
+	return product
+}
+
+/*
+ Returns val as a complex number, the widest of the element types
+*/
+func toComplex[T Element](val T) complex128 {
+	switch v := any(val).(type) {
+	case float32:
+		return complex(float64(v), 0)
+	case float64:
+		return complex(v, 0)
+	case int64:
+		return complex(float64(v), 0)
+	case complex128:
+		return v
+	case bool:
+		if v {
+			return 1
+		}
+	}
+	return 0
+}
+
+/*
+ Returns c converted to type T
+*/
+func fromComplex[T Element](c complex128) T {
+	var val T
+	switch p := any(&val).(type) {
+	case *float32:
+		*p = float32(real(c))
+	case *float64:
+		*p = real(c)
+	case *int64:
+		*p = int64(real(c))
+	case *complex128:
+		*p = c
+	case *bool:
+		*p = c != 0
+	}
+	return val
+}
+
+/*
+ Returns the real number f converted to type T
+*/
+func fromFloat[T Element](f float64) T {
+	return fromComplex[T](complex(f, 0))
+}
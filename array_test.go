@@ -0,0 +1,178 @@
+package goSci
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestArrayCreation(t *testing.T) {
+	z := ZerosOf[float32](2, 3)
+	if !reflect.DeepEqual(z.Shape(), []int{2, 3}) || !reflect.DeepEqual(z.Data(), make([]float32, 6)) {
+		t.Errorf("ZerosOf: got %v with shape %v", z.Data(), z.Shape())
+	}
+	if got := ZerosOf[bool](0, 4); got.Size() != 0 || len(got.Shape()) != 2 {
+		t.Errorf("ZerosOf with a zero length: got size %d and shape %v", got.Size(), got.Shape())
+	}
+	if got := OnesOf[bool](3).Data(); !reflect.DeepEqual(got, []bool{true, true, true}) {
+		t.Errorf("OnesOf[bool]: got %v", got)
+	}
+	if got := ArangeOf[int64](4).Data(); !reflect.DeepEqual(got, []int64{0, 1, 2, 3}) {
+		t.Errorf("ArangeOf[int64]: got %v", got)
+	}
+	if got := EyeOf[complex128](2).Data(); !reflect.DeepEqual(got, []complex128{1, 0, 0, 1}) {
+		t.Errorf("EyeOf[complex128]: got %v", got)
+	}
+	if got := EyeOf[bool](3).Data(); !reflect.DeepEqual(got, []bool{true, false, false, false, true, false, false, false, true}) {
+		t.Errorf("EyeOf[bool]: got %v", got)
+	}
+
+	// ArrayFromSlice shares its data, and the shape given is not aliased
+	data := []int64{1, 2, 3, 4, 5, 6}
+	shape := []int{3, 2}
+	x := ArrayFromSlice(data, shape...)
+	data[0] = 10
+	shape[0] = 7
+	if x.Get(0, 0) != 10 || !reflect.DeepEqual(x.Shape(), []int{3, 2}) {
+		t.Errorf("ArrayFromSlice: got %v with shape %v", x.Data(), x.Shape())
+	}
+	// Data, Shape and Copy return copies
+	x.Data()[1] = 20
+	x.Shape()[1] = 20
+	c := x.Copy()
+	c.Put(30, 2, 1)
+	if x.Get(0, 1) != 2 || x.Shape()[1] != 2 || x.Get(2, 1) != 6 {
+		t.Errorf("a copy shares data with the array: %v", x.Data())
+	}
+	if _, err := TryArrayFromSlice(data, 4, 2); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("TryArrayFromSlice: got %v", err)
+	}
+}
+
+func TestArrayAccess(t *testing.T) {
+	x := ArangeOf[float32](6)
+	if err := x.TryReshape(2, 3); err != nil {
+		t.Fatal(err)
+	}
+	if got := x.Get(1, 2); got != 5 {
+		t.Errorf("Get: got %v, want 5", got)
+	}
+	x.Put(-1, 0, 1)
+	if got := x.Data(); !reflect.DeepEqual(got, []float32{0, -1, 2, 3, 4, 5}) {
+		t.Errorf("Put: got %v", got)
+	}
+
+	var sm *ShapeMismatchError
+	if err := x.TryReshape(4, 2); !errors.As(err, &sm) || sm.Op != "Reshape" {
+		t.Errorf("TryReshape to the wrong size: got %v", err)
+	}
+	if !reflect.DeepEqual(x.Shape(), []int{2, 3}) {
+		t.Errorf("a failed TryReshape changed the shape to %v", x.Shape())
+	}
+
+	cases := []struct {
+		pos             []int
+		axis, idx, size int
+	}{
+		{[]int{2, 0}, 0, 2, 2},
+		{[]int{0, 3}, 1, 3, 3},
+		{[]int{0, -1}, 1, -1, 3},
+		// the wrong number of coordinates is reported on axis -1
+		{[]int{1}, -1, 1, 2},
+		{[]int{0, 0, 0}, -1, 3, 2},
+	}
+	for _, c := range cases {
+		_, err := x.TryGet(c.pos...)
+		var ie *IndexOutOfRangeError
+		if !errors.As(err, &ie) || ie.Axis != c.axis || ie.Index != c.idx || ie.Size != c.size {
+			t.Errorf("TryGet%v: got %v", c.pos, err)
+		}
+		if err := x.TryPut(0, c.pos...); !errors.As(err, new(*IndexOutOfRangeError)) {
+			t.Errorf("TryPut%v: got %v", c.pos, err)
+		}
+	}
+
+	defer func() {
+		if _, ok := recover().(*IndexOutOfRangeError); !ok {
+			t.Error("Get out of range did not panic with an IndexOutOfRangeError")
+		}
+	}()
+	x.Get(2, 2)
+}
+
+func TestAsType(t *testing.T) {
+	floats := ArrayFromSlice([]float64{0, 1.5, -2.7, 2.7, math.Copysign(0, -1), math.NaN()}, 6)
+	if got := AsType[bool](floats).Data(); !reflect.DeepEqual(got, []bool{false, true, true, true, false, true}) {
+		t.Errorf("float64 to bool: got %v", got)
+	}
+	// conversion to an integer truncates towards zero, as in Go
+	if got := AsType[int64](floats).Data()[:5]; !reflect.DeepEqual(got, []int64{0, 1, -2, 2, 0}) {
+		t.Errorf("float64 to int64: got %v", got)
+	}
+	if got := AsType[float32](floats).Data()[1]; got != 1.5 {
+		t.Errorf("float64 to float32: got %v", got)
+	}
+
+	bools := ArrayFromSlice([]bool{true, false, true}, 3)
+	if got := AsType[float64](bools).Data(); !reflect.DeepEqual(got, []float64{1, 0, 1}) {
+		t.Errorf("bool to float64: got %v", got)
+	}
+	if got := AsType[int64](bools).Data(); !reflect.DeepEqual(got, []int64{1, 0, 1}) {
+		t.Errorf("bool to int64: got %v", got)
+	}
+
+	// the imaginary part is dropped for numbers but counts towards true
+	complexes := ArrayFromSlice([]complex128{complex(1.5, 2), complex(0, 3), complex(-4, 0), 0}, 2, 2)
+	if got := AsType[float64](complexes).Data(); !reflect.DeepEqual(got, []float64{1.5, 0, -4, 0}) {
+		t.Errorf("complex128 to float64: got %v", got)
+	}
+	if got := AsType[int64](complexes).Data(); !reflect.DeepEqual(got, []int64{1, 0, -4, 0}) {
+		t.Errorf("complex128 to int64: got %v", got)
+	}
+	if got := AsType[bool](complexes).Data(); !reflect.DeepEqual(got, []bool{true, true, true, false}) {
+		t.Errorf("complex128 to bool: got %v", got)
+	}
+	if got := AsType[complex128](ArrayFromSlice([]int64{-3, 7}, 2)).Data(); !reflect.DeepEqual(got, []complex128{-3, 7}) {
+		t.Errorf("int64 to complex128: got %v", got)
+	}
+
+	// the same type is copied without a round trip through complex128, which
+	// would round integers beyond 2^53
+	big := ArrayFromSlice([]int64{1<<62 + 1}, 1)
+	same := AsType[int64](big)
+	if same.Get(0) != 1<<62+1 {
+		t.Errorf("int64 to int64: got %v", same.Get(0))
+	}
+	same.Put(0, 0)
+	if big.Get(0) == 0 {
+		t.Error("AsType to the same type shares data")
+	}
+	if !reflect.DeepEqual(AsType[float32](complexes).Shape(), []int{2, 2}) {
+		t.Error("AsType did not keep the shape")
+	}
+}
+
+func TestGsArrayConversions(t *testing.T) {
+	x := Arange(6)
+	x.Reshape(2, 3)
+	a := x.T().ToArray()
+	if !reflect.DeepEqual(a.Shape(), []int{3, 2}) || !reflect.DeepEqual(a.Data(), []float64{0, 3, 1, 4, 2, 5}) {
+		t.Errorf("ToArray of a transpose: got %v with shape %v", a.Data(), a.Shape())
+	}
+	a.Put(9, 0, 0)
+	if x.Get(0, 0) != 0 {
+		t.Error("ToArray shares data with the GsArray")
+	}
+
+	mask := ArrayFromSlice([]bool{true, false, false, true}, 2, 2)
+	assertArray(t, "ToGsArray of bools", ToGsArray(mask), []int{2, 2}, []float64{1, 0, 0, 1})
+	assertArray(t, "ToGsArray of float32", ToGsArray(ArangeOf[float32](3)), []int{3}, []float64{0, 1, 2})
+	f := ArangeOf[float64](3)
+	g := ToGsArray(f)
+	g.Put(5, 0)
+	if f.Get(0) != 0 {
+		t.Error("ToGsArray shares data with the Array")
+	}
+	assertArray(t, "round trip", ToGsArray(x.ToArray()), []int{2, 3}, x.Data())
+}
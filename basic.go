@@ -1,13 +1,8 @@
 package goSci
 
-
-/*
-  #cgo LDFLAGS: -lblas
-  #include <atlas/cblas.h>
-*/
-import 	"C"
-import(	
-         "unsafe"
+import (
+	"context"
+	"math"
 )
 
 /*
@@ -15,159 +10,259 @@ import(
  Panics if either of the arrays is not one dimensional or if the lengths are not equal
 */
 func Dot(x, y *GsArray) float64 {
+	result, err := TryDot(x, y)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+/*
+ Same as Dot but returns a ShapeMismatchError instead of panicking
+*/
+func TryDot(x, y *GsArray) (float64, error) {
 	if len(x.shape) > 2  || len(y.shape) > 2 {
-		panic("Invalid dimension for dot product!!")
+		return 0, shapeError("Dot", x.shape, y.shape)
 	}
 	if len(x.shape) == 2 && len(y.shape) == 2 {
 		if x.shape[0] != 1 && x.shape[1] != 1 {
 			if y.shape[0] != 1 && y.shape[1] != 1 {
-				panic("Invalid dimension for dot product!!")
+				return 0, shapeError("Dot", x.shape, y.shape)
 			}
 		}
 	}else if len(x.shape) == 1 && len(y.shape) == 2 {
 		if (y.shape[0] != 1) && (y.shape[1] != 1) {
-			panic("Invalid dimension for dot product!!")
+			return 0, shapeError("Dot", x.shape, y.shape)
 		}
 	} else if (len(x.shape) == 2 && len(y.shape) == 1) {
 		if (x.shape[0] != 1) && (x.shape[1] != 1) {
-			panic("Invalid dimension for dot product!!")
+			return 0, shapeError("Dot", x.shape, y.shape)
 		}
 	}
-	if len(x.data) != len(y.data){
-		panic("Vectors must be of the same lenght!!")
+	xData, yData := x.values(), y.values()
+	if len(xData) != len(yData){
+		return 0, shapeError("Dot", x.shape, y.shape)
 	}
-	c_N:= C.int(len(x.data))
-	c_x := (*C.double)(unsafe.Pointer(&x.data[0]))
-	c_incX := C.int(1)
-	c_y := (*C.double)(unsafe.Pointer(&y.data[0]))
-	c_incY := C.int(1)
-	
-	return float64(C.cblas_ddot(c_N, c_x, c_incX, c_y, c_incY))
+	return engine.Ddot(len(xData), xData, 1, yData, 1), nil
 }
 /*
  Returns the matrix multiplication of two GsArrays.
- Panics if the the dimensions of the arrays are invalid for matrix multiplication
+ Transposed views and sub-block slices are passed to BLAS as they are, without copying.
+ Panics if the the dimensions of the arrays are invalid for matrix multiply
 */
 func MatMult(x, y *GsArray) *GsArray {
-	if len(x.shape) != 2 || len(y.shape) != 2 {
-		panic("Arrays must have dimension 2 for matrix multiply")
-	}
-	if x.shape[1] != y.shape[0] {
-		panic("Invalid dimensions for matrix multiply")
-	}
-	c_M := C.int(x.shape[0])
-	c_N := C.int(y.shape[1])
-	c_K := C.int(x.shape[1])
-	c_Lda := c_K
-	c_Ldb := c_M
-	c_Ldc := c_N
-	c_alpha := C.double(1.0)
-	c_beta := C.double(1.0)
-	
-	c_x := (*C.double)(unsafe.Pointer(&x.data[0]))
-	c_y := (*C.double)(unsafe.Pointer(&y.data[0]))
+	return must(TryMatMult(x, y))
+}
+
+/*
+ Same as MatMult but returns a ShapeMismatchError instead of panicking
+*/
+func TryMatMult(x, y *GsArray) (*GsArray, error) {
+	if len(x.shape) != 2 || len(y.shape) != 2 || x.shape[1] != y.shape[0] {
+		return nil, shapeError("MatMult", x.shape, y.shape)
+	}
 	z := Zeros(x.shape[0],y.shape[1])
-	c_z := (*C.double)(unsafe.Pointer(&(z.data[0])))
-	
-	C.cblas_dgemm(101, 111, 111, c_M, c_N, c_K, c_alpha, c_x, c_Lda, c_y, c_Ldb, c_beta, c_z, c_Ldc)
-	
-	return z
+	return z, TryGemm(1, x, y, 0, z)
 }
+
 /*
- Returns the addtion of two matrices.
- Panics if the dimensions are incorrect
+ Computes c = alpha*a*b + beta*c in place of c, where a, b and c are matrices.
+ To multiply by a transpose pass a transposed view such as a.T(); like slices
+ of larger matrices these are handed to BLAS without copying.
+ Panics if the dimensions of the arrays are invalid
 */
-func Plus(x, y *GsArray) *GsArray {
-	if len(x.data) != len(y.data){
-		panic("Arrays must have the same shape!!")
+func Gemm(alpha float64, a, b *GsArray, beta float64, c *GsArray) {
+	mustDo(TryGemm(alpha, a, b, beta, c))
+}
+
+/*
+ Same as Gemm but returns a ShapeMismatchError instead of panicking
+*/
+func TryGemm(alpha float64, a, b *GsArray, beta float64, c *GsArray) error {
+	if len(a.shape) != 2 || len(b.shape) != 2 || a.shape[1] != b.shape[0] {
+		return shapeError("Gemm", a.shape, b.shape)
+	}
+	if len(c.shape) != 2 || c.shape[0] != a.shape[0] || c.shape[1] != b.shape[1] {
+		return shapeError("Gemm", c.shape, []int{a.shape[0], b.shape[1]})
+	}
+	if c.Size() == 0 {
+		return nil
+	}
+	// unlike an elementwise op a product cannot be written over its own operand
+	out := c
+	if !c.blasWritable() || c == a || c == b || overlaps(c, a) || overlaps(c, b) {
+		out = c.Copy()
+	}
+	aData, aTrans, lda := blasMatrix(a)
+	bData, bTrans, ldb := blasMatrix(b)
+	cData, _, ldc := blasMatrix(out)
+	m, n, k := a.shape[0], b.shape[1], a.shape[1]
+	// Dgemv returns at once when A has no columns, without applying beta
+	if n == 1 && !bTrans && k > 0 {
+		rows, cols := m, k
+		if aTrans {
+			rows, cols = cols, rows
+		}
+		engine.Dgemv(aTrans, rows, cols, alpha, aData, lda, bData, ldb, beta, cData, ldc)
+	} else {
+		engine.Dgemm(aTrans, bTrans, m, n, k, alpha, aData, lda, bData, ldb, beta, cData, ldc)
 	}
-	if len(x.shape) != len(y.shape){
-		panic("Arrays must have the same shape!!")
+	if out != c {
+		c.setValues(out.data)
 	}
-	for i := 0; i < len(x.shape); i++{
-		if x.shape[i] != y.shape[i]{
-			panic("Arrays must have the same shape!!")
-		}
+	return nil
+}
+
+/*
+ Computes y = alpha*a*x + beta*y in place of y, where a is a matrix and x and y
+ are vectors. Pass a.T() to multiply by the transpose of a.
+ Panics if the dimensions of the arrays are invalid
+*/
+func Gemv(alpha float64, a, x *GsArray, beta float64, y *GsArray) {
+	mustDo(TryGemv(alpha, a, x, beta, y))
+}
+
+/*
+ Same as Gemv but returns a ShapeMismatchError instead of panicking
+*/
+func TryGemv(alpha float64, a, x *GsArray, beta float64, y *GsArray) error {
+	if len(a.shape) != 2 || !isVector(x) || x.Size() != a.shape[1] {
+		return shapeError("Gemv", a.shape, x.shape)
+	}
+	if !isVector(y) || y.Size() != a.shape[0] {
+		return shapeError("Gemv", y.shape, []int{a.shape[0]})
+	}
+	if y.Size() == 0 {
+		return nil
+	}
+	out := y
+	if vectorInc(y) < 1 || y == x || overlaps(y, a) || overlaps(y, x) {
+		out = y.Copy()
+	}
+	aData, aTrans, lda := blasMatrix(a)
+	xData, incX := blasVector(x)
+	yData, incY := blasVector(out)
+	rows, cols := a.shape[0], a.shape[1]
+	if aTrans {
+		rows, cols = cols, rows
+	}
+	if a.shape[1] == 0 {
+		// Dgemv returns at once when A has no columns, without applying beta
+		scaleVector(y.Size(), beta, yData, incY)
+	} else {
+		engine.Dgemv(aTrans, rows, cols, alpha, aData, lda, xData, incX, beta, yData, incY)
 	}
-	result := Zeros(x.shape ...)
-	for i := 0; i < len(x.data) ; i++ {
-		result.data[i] = x.data[i] + y.data[i]
+	if out != y {
+		y.setValues(out.data)
 	}
-	return result
+	return nil
 }
 
 /*
- Accumulates the addtion of two matrices.
+ Computes y = alpha*x + y in place of y. x is broadcast to the shape of y.
  Panics if the dimensions are incorrect
 */
-func (x *GsArray) PlusEquals(y *GsArray) {
-	if len(x.data) != len(y.data){
-		panic("Arrays must have the same shape!!")
-	}
-	if len(x.shape) != len(y.shape){
-		panic("Arrays must have the same shape!!")
+func Axpy(alpha float64, x, y *GsArray) {
+	mustDo(TryAxpy(alpha, x, y))
+}
+
+/*
+ Same as Axpy but returns a ShapeMismatchError instead of panicking
+*/
+func TryAxpy(alpha float64, x, y *GsArray) error {
+	if sameShape(x.shape, y.shape) && isVector(y) && vectorInc(y) >= 1 && !overlaps(x, y) {
+		xData, incX := blasVector(x)
+		engine.Daxpy(y.Size(), alpha, xData, incX, y.data[y.offset:], vectorInc(y))
+		return nil
 	}
-	for i := 0; i < len(x.shape); i++{
-		if x.shape[i] != y.shape[i]{
-			panic("Arrays must have the same shape!!")
-		}
+	if sameShape(x.shape, y.shape) && x.strides == nil && y.strides == nil && !overlaps(x, y) {
+		engine.Daxpy(len(y.data), alpha, x.data, 1, y.data, 1)
+		return nil
 	}
-	for i := 0; i < len(x.data) ; i++ {
-		x.data[i] = x.data[i] + y.data[i]
+	return broadcastOpInPlace("Axpy", y, x, func(a, b float64) float64 { return a + alpha*b })
+}
+/*
+ Returns the addtion of two matrices.
+ The arrays are broadcast to a common shape, so a row, column or scalar
+ array can be added to a matrix.
+ Panics if the dimensions can not be broadcast together
+*/
+func Plus(x, y *GsArray) *GsArray {
+	return must(TryPlus(x, y))
+}
+
+/*
+ Same as Plus but returns a ShapeMismatchError instead of panicking
+*/
+func TryPlus(x, y *GsArray) (*GsArray, error) {
+	return broadcastOp("Plus", x, y, add)
+}
+
+/*
+ Accumulates the addtion of two matrices.
+ y is broadcast to the shape of x.
+ Panics if the dimensions are incorrect
+*/
+func (x *GsArray) PlusEquals(y *GsArray) {
+	mustDo(x.TryPlusEquals(y))
+}
+
+/*
+ Same as PlusEquals but returns a ShapeMismatchError instead of panicking
+*/
+func (x *GsArray) TryPlusEquals(y *GsArray) error {
+	if sameShape(x.shape, y.shape) && x.strides == nil && y.strides == nil && !overlaps(x, y) {
+		engine.Daxpy(len(x.data), 1, y.data, 1, x.data, 1)
+		return nil
 	}
+	return broadcastOpInPlace("PlusEquals", x, y, add)
 }
 /*
  Returns the x - y.
- Panics if the dimensions are incorrect
+ The arrays are broadcast to a common shape.
+ Panics if the dimensions can not be broadcast together
 */
 func Minus(x, y *GsArray) *GsArray {
-	if len(x.data) != len(y.data){
-		panic("Arrays must have the same shape!!")
-	}
-	if len(x.shape) != len(y.shape){
-		panic("Arrays must have the same shape!!")
-	}
-	for i := 0; i < len(x.shape); i++{
-		if x.shape[i] != y.shape[i]{
-			panic("Arrays must have the same shape!!")
-		}
-	}
-	result := Zeros(x.shape ...)
-	for i := 0; i < len(x.data) ; i++ {
-		result.data[i] = x.data[i] - y.data[i]
-	}
-	return result
+	return must(TryMinus(x, y))
+}
+
+/*
+ Same as Minus but returns a ShapeMismatchError instead of panicking
+*/
+func TryMinus(x, y *GsArray) (*GsArray, error) {
+	return broadcastOp("Minus", x, y, sub)
 }
 
 /*
  Accumulates the difference of two matricies.
+ y is broadcast to the shape of x.
  Panics if the dimensions are incorrect
 */
 func (x *GsArray) MinusEquals(y *GsArray) {
-	if len(x.data) != len(y.data){
-		panic("Arrays must have the same shape!!")
-	}
-	if len(x.shape) != len(y.shape){
-		panic("Arrays must have the same shape!!")
-	}
-	for i := 0; i < len(x.shape); i++{
-		if x.shape[i] != y.shape[i]{
-			panic("Arrays must have the same shape!!")
-		}
-	}
-	
-	for i := 0; i < len(x.data) ; i++ {
-		x.data[i] = x.data[i] - y.data[i]
+	mustDo(x.TryMinusEquals(y))
+}
+
+/*
+ Same as MinusEquals but returns a ShapeMismatchError instead of panicking
+*/
+func (x *GsArray) TryMinusEquals(y *GsArray) error {
+	if sameShape(x.shape, y.shape) && x.strides == nil && y.strides == nil && !overlaps(x, y) {
+		engine.Daxpy(len(x.data), -1, y.data, 1, x.data, 1)
+		return nil
 	}
+	return broadcastOpInPlace("MinusEquals", x, y, sub)
 }
 /*
  Returns a*x where a is a scalar and x is an array
 */
 func Times(x *GsArray, a float64) *GsArray {
 	result := Zeros(x.shape ...)
-	for i := 0; i < len(x.data) ; i++ {
-		result.data[i] = x.data[i]*a 
-	}
+	vals := x.values()
+	parallelFor(len(vals), len(vals), func(start, end int) {
+		for i := start; i < end; i++ {
+			result.data[i] = vals[i]*a
+		}
+	})
 	return result
 }
 
@@ -175,71 +270,135 @@ func Times(x *GsArray, a float64) *GsArray {
  Calculates a*x and stores the result in x where a is a scalar and x is an array
 */
 func (x *GsArray) TimesEquals(a float64) {
-	for i := 0; i < len(x.data) ; i++ {
+	if x.strides == nil {
+		engine.Dscal(len(x.data), a, x.data, 1)
+		return
+	}
+	x.walk(func(i int) {
 		x.data[i] = x.data[i]*a 
-	}	
+	})
 }
 /*
- Returns element wise multiplication of x and y
+ Returns element wise multiplication of x and y.
+ The arrays are broadcast to a common shape.
 */
 func ElemTimes(x, y *GsArray) *GsArray {
-	if len(x.data) != len(y.data){
-		panic("Arrays must have the same shape!!")
-	}
-	if len(x.shape) != len(y.shape){
-		panic("Arrays must have the same shape!!")
-	}
-	for i := 0; i < len(x.shape); i++{
-		if x.shape[i] != y.shape[i]{
-			panic("Arrays must have the same shape!!")
-		}
-	}
-	result := Zeros(x.shape ...)
-	for i := 0; i < len(x.data) ; i++ {
-		result.data[i] = x.data[i] * y.data[i]
-	}
-	return result
+	return must(TryElemTimes(x, y))
+}
+
+/*
+ Same as ElemTimes but returns a ShapeMismatchError instead of panicking
+*/
+func TryElemTimes(x, y *GsArray) (*GsArray, error) {
+	return broadcastOp("ElemTimes", x, y, mul)
+}
+
+/*
+ Accumulates the element wise multiplication of x and y in x.
+ y is broadcast to the shape of x.
+*/
+func (x *GsArray) ElemTimesEquals(y *GsArray) {
+	mustDo(x.TryElemTimesEquals(y))
+}
+
+/*
+ Same as ElemTimesEquals but returns a ShapeMismatchError instead of panicking
+*/
+func (x *GsArray) TryElemTimesEquals(y *GsArray) error {
+	return broadcastOpInPlace("ElemTimesEquals", x, y, mul)
+}
+/*
+ Returns element wise division of x by y.
+ The arrays are broadcast to a common shape.
+*/
+func Divide(x, y *GsArray) *GsArray {
+	return must(TryDivide(x, y))
 }
+
+/*
+ Same as Divide but returns a ShapeMismatchError instead of panicking
+*/
+func TryDivide(x, y *GsArray) (*GsArray, error) {
+	return broadcastOp("Divide", x, y, div)
+}
+
 /*
- Returns the sum of all of the elements in the array
+ Accumulates the element wise division of x by y in x.
+ y is broadcast to the shape of x.
+*/
+func (x *GsArray) DivideEquals(y *GsArray) {
+	mustDo(x.TryDivideEquals(y))
+}
+
+/*
+ Same as DivideEquals but returns a ShapeMismatchError instead of panicking
+*/
+func (x *GsArray) TryDivideEquals(y *GsArray) error {
+	return broadcastOpInPlace("DivideEquals", x, y, div)
+}
+/*
+ Returns x raised element wise to the power y.
+ The arrays are broadcast to a common shape.
+*/
+func Pow(x, y *GsArray) *GsArray {
+	return must(TryPow(x, y))
+}
+
+/*
+ Same as Pow but returns a ShapeMismatchError instead of panicking
+*/
+func TryPow(x, y *GsArray) (*GsArray, error) {
+	return broadcastOp("Pow", x, y, math.Pow)
+}
+/*
+ Returns the sum of all of the elements in the array with goSci.ALL, of each
+ column with goSci.COLS or of each row with goSci.ROWS.
+ Use SumAxis to sum over any axes of arrays with more than two dimensions.
 */
 func Sum(x *GsArray, sumType uint) *GsArray{
-	if sumType == ALL{
-		sum := Zeros(1,1)
-		for _,val := range x.data {
-			sum.data[0] += val
+	return must(TrySum(x, sumType))
+}
+
+/*
+ Same as Sum but returns an InvalidAxisError instead of panicking
+*/
+func TrySum(x *GsArray, sumType uint) (*GsArray, error) {
+	switch sumType {
+	case ALL:
+		sum, err := TrySumAxis(x, false)
+		if err != nil {
+			return nil, err
 		}
-		return sum
-	} else if sumType == COLS{
+		sum.shape = []int{1, 1}
+		return sum, nil
+	case COLS:
 		if len(x.shape) < 2 {
-			panic("There are no columns use goSci.ALL.")
-		}
-		sum := Zeros(1, x.shape[1])
-		for i, val := range x.data {
-			sum.data[i%x.shape[1]] += val
+			return nil, &InvalidAxisError{Axis: COLS, NDim: len(x.shape)}
 		}
-		return sum
-	} else if sumType == ROWS{
+		return TrySumAxis(x, true, 0)
+	case ROWS:
 		if len(x.shape) < 2 {
-			return Sum(x, ALL)
-		}
-		sum := Zeros(x.shape[0],1)
-		for i, val := range x.data {
-			sum.data[i/x.shape[1]] += val
+			return TrySum(x, ALL)
 		}
-		return sum
-	} else {
-		panic ("Invalid sum type")
+		return TrySumAxis(x, true, 1)
 	}
-	return Zeros(x.shape ...)
+	return nil, &InvalidAxisError{Axis: int(sumType), NDim: len(x.shape)}
 }
 /*
-Returns a rep1Xrep2 tiling of x,  x must be a vector or matrix
+Returns a rep1Xrep2 tiling of x,  x must be a vector or matrix.
+ Tile handles any rank.
 */
 func Repmat(x *GsArray, rep1, rep2 int) *GsArray {
+	return must(TryRepmat(x, rep1, rep2))
+}
+
+/*
+ Same as Repmat but returns a ShapeMismatchError instead of panicking
+*/
+func TryRepmat(x *GsArray, rep1, rep2 int) (*GsArray, error) {
 	var dim1, dim2, x1, x2 int
-	if len(x.shape) > 2 {
-		panic("Shape of input array is invalid for Repmat")
+	if len(x.shape) > 2 || len(x.shape) == 0 || rep1 < 0 || rep2 < 0 {
+		return nil, shapeError("Repmat", x.shape, []int{rep1, rep2})
 	}
 	x1 = x.shape[0]
 	dim1 = x1 * rep1
@@ -251,23 +410,48 @@ func Repmat(x *GsArray, rep1, rep2 int) *GsArray {
 		dim2 = rep2
 	}
 	returnArray := Zeros(dim1, dim2)
+	xData := x.values()
 	for i := 0; i < dim1; i++ {
 		origIdx := i%x1
 		for j := 0; j < dim2; j++ {
-			returnArray.Put(x.Get(origIdx, j%x2), i,j)
+			returnArray.data[i*dim2 + j] = xData[origIdx*x2 + j%x2]
 		}
 	}
-	return returnArray
+	return returnArray, nil
 }
 /*
- Applies someFunc to every element of an array and returns the new array
+ Applies someFunc to every element of an array and returns the new array.
+ Large arrays are split across the worker pool, so someFunc may be called
+ concurrently and must be safe for that.
 */
 func ArrayFun(x *GsArray, someFunc func(float64) float64) *GsArray {
+	returnArray, _ := ArrayFunContext(context.Background(), x, someFunc)
+	return returnArray
+}
+
+/*
+ Same as ArrayFun but stops early when ctx is done, returning ctx.Err(), for
+ functions slow enough that a caller may give up on them
+*/
+func ArrayFunContext(ctx context.Context, x *GsArray, someFunc func(float64) float64) (*GsArray, error) {
 	returnArray := Zeros(x.shape ...)
-	for i, val := range x.data {
-		returnArray.data[i] = someFunc(val)
+	vals := x.values()
+	body := func(start, end int) {
+		for i := start; i < end; i++ {
+			returnArray.data[i] = someFunc(vals[i])
+		}
 	}
-	return returnArray
+	if len(vals) < parallelThreshold {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		body(0, len(vals))
+		return returnArray, nil
+	}
+	if err := ParallelFor(ctx, len(vals), body); err != nil {
+		return nil, err
+	}
+	return returnArray, nil
 }
 
 /*
@@ -284,13 +468,23 @@ and
                              7, 8
                              6, 5
                              2, 3]
+ Note that ROWS joins along the columns and COLS along the rows.
+ Concatenate joins any number of arrays of any rank along a numbered axis.
 */
 func Cat(x,y *GsArray, catType uint) *GsArray {
-	if len(x.shape) > 2 || len(y.shape) > 2 {
-		panic("Both arrays must have dimension less than 3 to concatenate.")
+	return must(TryCat(x, y, catType))
+}
+
+/*
+ Same as Cat but returns a ShapeMismatchError or InvalidAxisError instead of panicking
+*/
+func TryCat(x,y *GsArray, catType uint) (*GsArray, error) {
+	if len(x.shape) > 2 || len(y.shape) > 2 || len(x.shape) == 0 || len(y.shape) == 0 {
+		return nil, shapeError("Cat", x.shape, y.shape)
 	}
 	var colsx, colsy int
 	var returnArray *GsArray
+	xData, yData := x.values(), y.values()
 	rowsx := x.shape[0]
 	rowsy := y.shape[0]
 	if len(x.shape) == 1 {
@@ -306,28 +500,28 @@ func Cat(x,y *GsArray, catType uint) *GsArray {
 	switch catType {
 	case COLS:
 		if colsx != colsy {
-			panic("Arrays must have the same number of columns.")
+			return nil, shapeError("Cat", x.shape, y.shape)
 		}
 		returnArray = Zeros(rowsx + rowsy, colsx)
-		copy(returnArray.data[0:len(x.data)], x.data)
-		copy(returnArray.data[len(x.data):], y.data)
+		copy(returnArray.data[0:len(xData)], xData)
+		copy(returnArray.data[len(xData):], yData)
 	case ROWS:
 		if rowsx != rowsy {
-			panic("Arrays must have the same number of rows.")
+			return nil, shapeError("Cat", x.shape, y.shape)
 		}
 		returnArray = Zeros(rowsx, colsx + colsy)
 		xpos, ypos := 0, 0
 		for i := 0; i < len(returnArray.data); i++ {
 			if i%(colsx + colsy) < colsx {
-				returnArray.data[i] = x.data[xpos]
+				returnArray.data[i] = xData[xpos]
 				xpos++
 			} else {
-				returnArray.data[i] = y.data[ypos]
+				returnArray.data[i] = yData[ypos]
 				ypos++
 			}
 		}
 	default:
-		panic("Invalid cat type use goSci.ROWS or goSci.COLS.")
+		return nil, &InvalidAxisError{Axis: int(catType), NDim: 2}
 	}
-	return returnArray
-}
\ No newline at end of file
+	return returnArray, nil
+}
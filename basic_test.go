@@ -0,0 +1,133 @@
+package goSci
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestGemm(t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+	a, b := randomArray(r, 5, 6), randomArray(r, 6, 7)
+
+	cases := []struct {
+		name string
+		a, b *GsArray
+	}{
+		{"packed", a, b},
+		{"transposed", b.T(), a.T()},
+		{"sub-blocks", a.Slice(Span(1, 4, 1), Span(0, 5, 1)), b.Slice(Span(0, 5, 1), Span(1, 7, 2))},
+		{"column", a, b.Slice(All, Span(3, 4, 1))},
+		{"transposed column", b.T(), a.T().Slice(All, Span(2, 3, 1))},
+	}
+	for _, c := range cases {
+		m, n := c.a.shape[0], c.b.shape[1]
+		for _, ab := range [][2]float64{{1, 0}, {2, 0.5}, {0, -1}} {
+			alpha, beta := ab[0], ab[1]
+			// C is a strided view into a larger matrix
+			big := randomArray(r, m+1, 2*n)
+			out := big.Slice(Span(1, m+1, 1), Span(0, 2*n, 2))
+			want := Plus(Times(naiveProduct(c.a, c.b), alpha), Times(out.Copy(), beta))
+			Gemm(alpha, c.a, c.b, beta, out)
+			assertClose(t, c.name, out, want, 1e-12)
+		}
+	}
+
+	// with no inner dimension the product is zero and C is only scaled, for
+	// a single column as for several
+	for _, n := range []int{1, 2} {
+		c := Ones(2, n)
+		Gemm(1, Zeros(2, 0), Zeros(0, n), 0.5, c)
+		assertClose(t, "inner dimension of zero", c, Full(0.5, 2, n), 0)
+	}
+
+	// C may overlap an operand
+	x := randomArray(r, 4, 4)
+	want := naiveProduct(x, x)
+	Gemm(1, x, x.Copy(), 0, x)
+	assertClose(t, "C overlapping A", x, want, 1e-12)
+
+	if err := TryGemm(1, a, a, 0, Zeros(5, 6)); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("Gemm with mismatched operands: got %v", err)
+	}
+	if err := TryGemm(1, a, b, 0, Zeros(5, 6)); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("Gemm into the wrong shape: got %v", err)
+	}
+}
+
+func TestGemv(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	a := randomArray(r, 5, 6)
+	m := randomArray(r, 6, 6)
+
+	cases := []struct {
+		name string
+		a, x *GsArray
+	}{
+		{"packed", a, randomArray(r, 6)},
+		{"transposed", a.T(), randomArray(r, 5)},
+		{"column of a matrix", a, m.Slice(All, At(2))},
+		{"reversed", a.T(), m.Slice(Span(4, Omit, -1), At(0))},
+		{"row vector", a.Slice(Span(0, 5, 2)), randomArray(r, 1, 6)},
+	}
+	for _, c := range cases {
+		rows := c.a.shape[0]
+		for _, ab := range [][2]float64{{1, 0}, {-2, 0.5}, {0, 3}} {
+			alpha, beta := ab[0], ab[1]
+			big := randomArray(r, 3*rows)
+			y := big.Slice(Span(0, 3*rows, 3))
+			x := reshaped(c.x.Copy(), []int{c.x.Size(), 1})
+			want := Plus(Times(reshaped(naiveProduct(c.a, x), []int{rows}), alpha), Times(y.Copy(), beta))
+			Gemv(alpha, c.a, c.x, beta, y)
+			assertClose(t, c.name, y, want, 1e-12)
+		}
+	}
+
+	v := randomArray(r, 6)
+	want := reshaped(naiveProduct(m, reshaped(v.Copy(), []int{6, 1})), []int{6})
+	Gemv(1, m, v, 0, v)
+	assertClose(t, "y the same as x", v, want, 1e-12)
+
+	y := Ones(2)
+	Gemv(1, Zeros(2, 0), Zeros(0), 0.5, y)
+	assertArray(t, "Gemv with no columns", y, []int{2}, []float64{0.5, 0.5})
+
+	if err := TryGemv(1, a, Zeros(5), 0, Zeros(5)); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("Gemv with the wrong length of x: got %v", err)
+	}
+	if err := TryGemv(1, a, Zeros(6), 0, Zeros(6)); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("Gemv with the wrong length of y: got %v", err)
+	}
+}
+
+func TestAxpy(t *testing.T) {
+	r := rand.New(rand.NewSource(8))
+	m := randomArray(r, 4, 6)
+
+	y := randomArray(r, 6)
+	want := Plus(y, Times(m.Slice(At(1)), 2))
+	Axpy(2, m.Slice(At(1)), y)
+	assertClose(t, "row of a matrix", y, want, 1e-12)
+
+	// a strided column of m updated from a reversed vector
+	col := m.Slice(All, At(3))
+	x := randomArray(r, 4).Slice(Span(Omit, Omit, -1))
+	want = Plus(col.Copy(), Times(x, -1))
+	Axpy(-1, x, col)
+	assertClose(t, "strided column", m.Slice(All, At(3)), want, 1e-12)
+
+	// x broadcast along the rows of y
+	row := randomArray(r, 6)
+	want = Plus(m, Times(row, 0.5))
+	Axpy(0.5, row, m)
+	assertClose(t, "broadcast", m, want, 1e-12)
+
+	// x the same as y
+	v := FromSlice([]float64{1, 2, 3}, 3)
+	Axpy(1, v, v)
+	assertArray(t, "x aliasing y", v, []int{3}, []float64{2, 4, 6})
+
+	if err := TryAxpy(1, Zeros(4), Zeros(3)); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("Axpy with mismatched shapes: got %v", err)
+	}
+}
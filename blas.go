@@ -0,0 +1,138 @@
+package goSci
+
+/*
+ Which triangle of a triangular or symmetric matrix is referenced
+*/
+type Uplo int
+
+const (
+	Upper Uplo = iota
+	Lower
+)
+
+/*
+ Whether a triangular matrix has an implicit unit diagonal
+*/
+type Diag int
+
+const (
+	NonUnit Diag = iota
+	Unit
+)
+
+/*
+ Which side of the product a symmetric or triangular matrix is on
+*/
+type Side int
+
+const (
+	Left Side = iota
+	Right
+)
+
+/*
+ How a complex matrix enters a product: as it is, transposed or conjugate
+ transposed
+*/
+type Trans int
+
+const (
+	NoTrans Trans = iota
+	Transposed
+	ConjTrans
+)
+
+/*
+ The double precision BLAS routines used by goSci. Matrices are row major
+ with the given leading dimension and a trans argument of true selects the
+ transpose of that operand. As in the reference BLAS a vector with a negative
+ increment starts at the end of its slice, except that Dscal, Dnrm2, Dasum
+ and Idamax take it to be empty. The complex routines take complex128
+ elements.
+ GoBlas is a pure go implementation, CBlas (built with -tags atlas) calls the
+ ATLAS cblas library and other backends can be installed with SetBlas.
+*/
+type Blas interface {
+	// x.y
+	Ddot(n int, x []float64, incX int, y []float64, incY int) float64
+	// y = alpha*x + y
+	Daxpy(n int, alpha float64, x []float64, incX int, y []float64, incY int)
+	// x = alpha*x
+	Dscal(n int, alpha float64, x []float64, incX int)
+	// Euclidean norm of x
+	Dnrm2(n int, x []float64, incX int) float64
+	// Sum of the absolute values of x
+	Dasum(n int, x []float64, incX int) float64
+	// Index of the first element of x with the largest absolute value, -1 if n is 0
+	Idamax(n int, x []float64, incX int) int
+	// y = alpha*op(A)*x + beta*y where A is m by n
+	Dgemv(trans bool, m, n int, alpha float64, a []float64, lda int, x []float64, incX int, beta float64, y []float64, incY int)
+	// A = alpha*x*y^T + A where A is m by n
+	Dger(m, n int, alpha float64, x []float64, incX int, y []float64, incY int, a []float64, lda int)
+	// Solves op(A)*x = b in place of x = b where A is n by n triangular
+	Dtrsv(uplo Uplo, trans bool, diag Diag, n int, a []float64, lda int, x []float64, incX int)
+	// C = alpha*A*B + beta*C (Left) or alpha*B*A + beta*C (Right) where A is symmetric and C is m by n
+	Dsymm(side Side, uplo Uplo, m, n int, alpha float64, a []float64, lda int, b []float64, ldb int, beta float64, c []float64, ldc int)
+	// C = alpha*op(A)*op(A)^T + beta*C where C is n by n symmetric and op(A) is n by k
+	Dsyrk(uplo Uplo, trans bool, n, k int, alpha float64, a []float64, lda int, beta float64, c []float64, ldc int)
+	// Solves op(A)*X = alpha*B (Left) or X*op(A) = alpha*B (Right) in place of X = B where B is m by n
+	Dtrsm(side Side, uplo Uplo, trans bool, diag Diag, m, n int, alpha float64, a []float64, lda int, b []float64, ldb int)
+	// C = alpha*op(A)*op(B) + beta*C where op(A) is m by k and op(B) is k by n
+	Dgemm(transA, transB bool, m, n, k int, alpha float64, a []float64, lda int, b []float64, ldb int, beta float64, c []float64, ldc int)
+	// conj(x).y
+	Zdotc(n int, x []complex128, incX int, y []complex128, incY int) complex128
+	// C = alpha*op(A)*op(B) + beta*C where op(A) is m by k and op(B) is k by n
+	Zgemm(transA, transB Trans, m, n, k int, alpha complex128, a []complex128, lda int, b []complex128, ldb int, beta complex128, c []complex128, ldc int)
+}
+
+/*
+ The backend used by all of the linear algebra in the package
+*/
+var engine Blas = GoBlas{}
+
+/*
+ Installs b as the BLAS backend and returns the previous one
+*/
+func SetBlas(b Blas) Blas {
+	previous := engine
+	engine = b
+	return previous
+}
+
+/*
+ x = beta*x, setting x to zero rather than scaling when beta is zero so that
+ NaNs already in x are not propagated, as BLAS requires
+*/
+func scaleVector(n int, beta float64, x []float64, incX int) {
+	if beta == 1 {
+		return
+	}
+	kx := vectorStart(n, incX)
+	for i := 0; i < n; i++ {
+		if beta == 0 {
+			x[kx+i*incX] = 0
+		} else {
+			x[kx+i*incX] *= beta
+		}
+	}
+}
+
+/*
+ Returns the index in a slice of the first element of a vector of n elements
+ with increment inc, which comes last in the slice when inc is negative
+*/
+func vectorStart(n, inc int) int {
+	if inc < 0 {
+		return (1 - n) * inc
+	}
+	return 0
+}
+
+/*
+ C = beta*C for the m by n matrix C with leading dimension ldc
+*/
+func scaleMatrix(m, n int, beta float64, c []float64, ldc int) {
+	for i := 0; i < m; i++ {
+		scaleVector(n, beta, c[i*ldc:], 1)
+	}
+}
@@ -0,0 +1,203 @@
+//go:build cgo && atlas
+
+package goSci
+
+/*
+  #cgo LDFLAGS: -lblas
+  #include <atlas/cblas.h>
+*/
+import "C"
+import (
+	"unsafe"
+)
+
+/*
+ Implementation of Blas backed by the system's ATLAS cblas library. Building
+ with -tags atlas makes it the default backend.
+*/
+type CBlas struct{}
+
+func init() {
+	engine = CBlas{}
+}
+
+func cblasTrans(trans bool) C.enum_CBLAS_TRANSPOSE {
+	if trans {
+		return C.CblasTrans
+	}
+	return C.CblasNoTrans
+}
+
+func cblasComplexTrans(trans Trans) C.enum_CBLAS_TRANSPOSE {
+	switch trans {
+	case Transposed:
+		return C.CblasTrans
+	case ConjTrans:
+		return C.CblasConjTrans
+	}
+	return C.CblasNoTrans
+}
+
+func cblasUplo(uplo Uplo) C.enum_CBLAS_UPLO {
+	if uplo == Lower {
+		return C.CblasLower
+	}
+	return C.CblasUpper
+}
+
+func cblasDiag(diag Diag) C.enum_CBLAS_DIAG {
+	if diag == Unit {
+		return C.CblasUnit
+	}
+	return C.CblasNonUnit
+}
+
+func cblasSide(side Side) C.enum_CBLAS_SIDE {
+	if side == Right {
+		return C.CblasRight
+	}
+	return C.CblasLeft
+}
+
+func cptr(x []float64) *C.double {
+	return (*C.double)(unsafe.Pointer(&x[0]))
+}
+
+func zptr(x []complex128) unsafe.Pointer {
+	return unsafe.Pointer(&x[0])
+}
+
+func (CBlas) Ddot(n int, x []float64, incX int, y []float64, incY int) float64 {
+	if n == 0 {
+		return 0
+	}
+	return float64(C.cblas_ddot(C.int(n), cptr(x), C.int(incX), cptr(y), C.int(incY)))
+}
+
+func (CBlas) Daxpy(n int, alpha float64, x []float64, incX int, y []float64, incY int) {
+	if n == 0 {
+		return
+	}
+	C.cblas_daxpy(C.int(n), C.double(alpha), cptr(x), C.int(incX), cptr(y), C.int(incY))
+}
+
+func (CBlas) Dscal(n int, alpha float64, x []float64, incX int) {
+	if n == 0 {
+		return
+	}
+	C.cblas_dscal(C.int(n), C.double(alpha), cptr(x), C.int(incX))
+}
+
+func (CBlas) Dnrm2(n int, x []float64, incX int) float64 {
+	if n == 0 {
+		return 0
+	}
+	return float64(C.cblas_dnrm2(C.int(n), cptr(x), C.int(incX)))
+}
+
+func (CBlas) Dasum(n int, x []float64, incX int) float64 {
+	if n == 0 {
+		return 0
+	}
+	return float64(C.cblas_dasum(C.int(n), cptr(x), C.int(incX)))
+}
+
+func (CBlas) Idamax(n int, x []float64, incX int) int {
+	if n == 0 {
+		return -1
+	}
+	return int(C.cblas_idamax(C.int(n), cptr(x), C.int(incX)))
+}
+
+func (CBlas) Dgemv(trans bool, m, n int, alpha float64, a []float64, lda int, x []float64, incX int, beta float64, y []float64, incY int) {
+	if m == 0 || n == 0 {
+		return
+	}
+	C.cblas_dgemv(C.CblasRowMajor, cblasTrans(trans), C.int(m), C.int(n), C.double(alpha),
+		cptr(a), C.int(lda), cptr(x), C.int(incX), C.double(beta), cptr(y), C.int(incY))
+}
+
+func (CBlas) Dger(m, n int, alpha float64, x []float64, incX int, y []float64, incY int, a []float64, lda int) {
+	if m == 0 || n == 0 {
+		return
+	}
+	C.cblas_dger(C.CblasRowMajor, C.int(m), C.int(n), C.double(alpha),
+		cptr(x), C.int(incX), cptr(y), C.int(incY), cptr(a), C.int(lda))
+}
+
+func (CBlas) Dtrsv(uplo Uplo, trans bool, diag Diag, n int, a []float64, lda int, x []float64, incX int) {
+	if n == 0 {
+		return
+	}
+	C.cblas_dtrsv(C.CblasRowMajor, cblasUplo(uplo), cblasTrans(trans), cblasDiag(diag), C.int(n),
+		cptr(a), C.int(lda), cptr(x), C.int(incX))
+}
+
+func (CBlas) Dsymm(side Side, uplo Uplo, m, n int, alpha float64, a []float64, lda int, b []float64, ldb int, beta float64, c []float64, ldc int) {
+	if m == 0 || n == 0 {
+		return
+	}
+	C.cblas_dsymm(C.CblasRowMajor, cblasSide(side), cblasUplo(uplo), C.int(m), C.int(n), C.double(alpha),
+		cptr(a), C.int(lda), cptr(b), C.int(ldb), C.double(beta), cptr(c), C.int(ldc))
+}
+
+func (CBlas) Dsyrk(uplo Uplo, trans bool, n, k int, alpha float64, a []float64, lda int, beta float64, c []float64, ldc int) {
+	if n == 0 {
+		return
+	}
+	if k == 0 {
+		GoBlas{}.Dsyrk(uplo, trans, n, k, alpha, a, lda, beta, c, ldc)
+		return
+	}
+	C.cblas_dsyrk(C.CblasRowMajor, cblasUplo(uplo), cblasTrans(trans), C.int(n), C.int(k), C.double(alpha),
+		cptr(a), C.int(lda), C.double(beta), cptr(c), C.int(ldc))
+}
+
+func (CBlas) Dtrsm(side Side, uplo Uplo, trans bool, diag Diag, m, n int, alpha float64, a []float64, lda int, b []float64, ldb int) {
+	if m == 0 || n == 0 {
+		return
+	}
+	C.cblas_dtrsm(C.CblasRowMajor, cblasSide(side), cblasUplo(uplo), cblasTrans(trans), cblasDiag(diag),
+		C.int(m), C.int(n), C.double(alpha), cptr(a), C.int(lda), cptr(b), C.int(ldb))
+}
+
+func (CBlas) Dgemm(transA, transB bool, m, n, k int, alpha float64, a []float64, lda int, b []float64, ldb int, beta float64, c []float64, ldc int) {
+	if m == 0 || n == 0 {
+		return
+	}
+	if k == 0 {
+		scaleMatrix(m, n, beta, c, ldc)
+		return
+	}
+	C.cblas_dgemm(C.CblasRowMajor, cblasTrans(transA), cblasTrans(transB), C.int(m), C.int(n), C.int(k),
+		C.double(alpha), cptr(a), C.int(lda), cptr(b), C.int(ldb), C.double(beta), cptr(c), C.int(ldc))
+}
+
+func (CBlas) Zdotc(n int, x []complex128, incX int, y []complex128, incY int) complex128 {
+	var dot complex128
+	if n == 0 {
+		return dot
+	}
+	C.cblas_zdotc_sub(C.int(n), zptr(x), C.int(incX), zptr(y), C.int(incY), unsafe.Pointer(&dot))
+	return dot
+}
+
+func (CBlas) Zgemm(transA, transB Trans, m, n, k int, alpha complex128, a []complex128, lda int, b []complex128, ldb int, beta complex128, c []complex128, ldc int) {
+	if m == 0 || n == 0 {
+		return
+	}
+	if k == 0 {
+		for i := 0; i < m; i++ {
+			for j := 0; j < n; j++ {
+				if beta == 0 {
+					c[i*ldc+j] = 0
+				} else {
+					c[i*ldc+j] *= beta
+				}
+			}
+		}
+		return
+	}
+	C.cblas_zgemm(C.CblasRowMajor, cblasComplexTrans(transA), cblasComplexTrans(transB), C.int(m), C.int(n), C.int(k),
+		unsafe.Pointer(&alpha), zptr(a), C.int(lda), zptr(b), C.int(ldb), unsafe.Pointer(&beta), zptr(c), C.int(ldc))
+}
@@ -0,0 +1,369 @@
+package goSci
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+/*
+ Pure go implementation of Blas, used unless the package is built with
+ -tags atlas, so that goSci builds without cgo or ATLAS.
+*/
+type GoBlas struct{}
+
+func (GoBlas) Ddot(n int, x []float64, incX int, y []float64, incY int) float64 {
+	sum := 0.0
+	if incX == 1 && incY == 1 {
+		y = y[:n]
+		for i, val := range x[:n] {
+			sum += val * y[i]
+		}
+		return sum
+	}
+	kx, ky := vectorStart(n, incX), vectorStart(n, incY)
+	for i := 0; i < n; i++ {
+		sum += x[kx+i*incX] * y[ky+i*incY]
+	}
+	return sum
+}
+
+func (GoBlas) Daxpy(n int, alpha float64, x []float64, incX int, y []float64, incY int) {
+	if alpha == 0 {
+		return
+	}
+	if incX == 1 && incY == 1 {
+		y = y[:n]
+		for i, val := range x[:n] {
+			y[i] += alpha * val
+		}
+		return
+	}
+	kx, ky := vectorStart(n, incX), vectorStart(n, incY)
+	for i := 0; i < n; i++ {
+		y[ky+i*incY] += alpha * x[kx+i*incX]
+	}
+}
+
+func (GoBlas) Dscal(n int, alpha float64, x []float64, incX int) {
+	if incX <= 0 {
+		return
+	}
+	for i := 0; i < n; i++ {
+		x[i*incX] *= alpha
+	}
+}
+
+/*
+ Accumulates a scaled sum of squares so that large or small elements do not
+ overflow or underflow
+*/
+func (GoBlas) Dnrm2(n int, x []float64, incX int) float64 {
+	if incX <= 0 {
+		return 0
+	}
+	scale, ssq := 0.0, 1.0
+	for i := 0; i < n; i++ {
+		val := math.Abs(x[i*incX])
+		if val == 0 {
+			continue
+		}
+		if math.IsInf(val, 1) {
+			return val
+		}
+		if scale < val {
+			ssq = 1 + ssq*(scale/val)*(scale/val)
+			scale = val
+		} else {
+			ssq += (val / scale) * (val / scale)
+		}
+	}
+	return scale * math.Sqrt(ssq)
+}
+
+func (GoBlas) Dasum(n int, x []float64, incX int) float64 {
+	if incX <= 0 {
+		return 0
+	}
+	sum := 0.0
+	for i := 0; i < n; i++ {
+		sum += math.Abs(x[i*incX])
+	}
+	return sum
+}
+
+func (GoBlas) Idamax(n int, x []float64, incX int) int {
+	if n == 0 || incX <= 0 {
+		return -1
+	}
+	idx, max := 0, math.Abs(x[0])
+	for i := 1; i < n; i++ {
+		if val := math.Abs(x[i*incX]); val > max {
+			idx, max = i, val
+		}
+	}
+	return idx
+}
+
+func (b GoBlas) Dgemv(trans bool, m, n int, alpha float64, a []float64, lda int, x []float64, incX int, beta float64, y []float64, incY int) {
+	if m == 0 || n == 0 {
+		return
+	}
+	lenY := m
+	if trans {
+		lenY = n
+	}
+	scaleVector(lenY, beta, y, incY)
+	if alpha == 0 {
+		return
+	}
+	if trans {
+		kx := vectorStart(m, incX)
+		for i := 0; i < m; i++ {
+			b.Daxpy(n, alpha*x[kx+i*incX], a[i*lda:], 1, y, incY)
+		}
+		return
+	}
+	ky := vectorStart(m, incY)
+	for i := 0; i < m; i++ {
+		y[ky+i*incY] += alpha * b.Ddot(n, a[i*lda:], 1, x, incX)
+	}
+}
+
+func (b GoBlas) Dger(m, n int, alpha float64, x []float64, incX int, y []float64, incY int, a []float64, lda int) {
+	kx := vectorStart(m, incX)
+	for i := 0; i < m; i++ {
+		b.Daxpy(n, alpha*x[kx+i*incX], y, incY, a[i*lda:], 1)
+	}
+}
+
+/*
+ Solves by substitution, running forwards or backwards through x depending on
+ whether op(A) is lower or upper triangular. The transposed cases walk A by
+ rows and update the remaining elements of x as each one is found.
+*/
+func (GoBlas) Dtrsv(uplo Uplo, trans bool, diag Diag, n int, a []float64, lda int, x []float64, incX int) {
+	kx := vectorStart(n, incX)
+	if !trans {
+		if uplo == Upper {
+			for i := n - 1; i >= 0; i-- {
+				sum := x[kx+i*incX]
+				for j := i + 1; j < n; j++ {
+					sum -= a[i*lda+j] * x[kx+j*incX]
+				}
+				if diag == NonUnit {
+					sum /= a[i*lda+i]
+				}
+				x[kx+i*incX] = sum
+			}
+			return
+		}
+		for i := 0; i < n; i++ {
+			sum := x[kx+i*incX]
+			for j := 0; j < i; j++ {
+				sum -= a[i*lda+j] * x[kx+j*incX]
+			}
+			if diag == NonUnit {
+				sum /= a[i*lda+i]
+			}
+			x[kx+i*incX] = sum
+		}
+		return
+	}
+	if uplo == Upper {
+		for i := 0; i < n; i++ {
+			if diag == NonUnit {
+				x[kx+i*incX] /= a[i*lda+i]
+			}
+			val := x[kx+i*incX]
+			for j := i + 1; j < n; j++ {
+				x[kx+j*incX] -= a[i*lda+j] * val
+			}
+		}
+		return
+	}
+	for i := n - 1; i >= 0; i-- {
+		if diag == NonUnit {
+			x[kx+i*incX] /= a[i*lda+i]
+		}
+		val := x[kx+i*incX]
+		for j := 0; j < i; j++ {
+			x[kx+j*incX] -= a[i*lda+j] * val
+		}
+	}
+}
+
+/*
+ Returns element i,j of a symmetric matrix stored in the uplo triangle of a
+*/
+func symAt(a []float64, lda int, uplo Uplo, i, j int) float64 {
+	if (uplo == Upper) == (i <= j) {
+		return a[i*lda+j]
+	}
+	return a[j*lda+i]
+}
+
+func (b GoBlas) Dsymm(side Side, uplo Uplo, m, n int, alpha float64, a []float64, lda int, bm []float64, ldb int, beta float64, c []float64, ldc int) {
+	scaleMatrix(m, n, beta, c, ldc)
+	if alpha == 0 {
+		return
+	}
+	for i := 0; i < m; i++ {
+		if side == Left {
+			for l := 0; l < m; l++ {
+				b.Daxpy(n, alpha*symAt(a, lda, uplo, i, l), bm[l*ldb:], 1, c[i*ldc:], 1)
+			}
+			continue
+		}
+		for l := 0; l < n; l++ {
+			s := alpha * bm[i*ldb+l]
+			for j := 0; j < n; j++ {
+				c[i*ldc+j] += s * symAt(a, lda, uplo, l, j)
+			}
+		}
+	}
+}
+
+func (b GoBlas) Dsyrk(uplo Uplo, trans bool, n, k int, alpha float64, a []float64, lda int, beta float64, c []float64, ldc int) {
+	for i := 0; i < n; i++ {
+		start, end := i, n
+		if uplo == Lower {
+			start, end = 0, i+1
+		}
+		scaleVector(end-start, beta, c[i*ldc+start:], 1)
+		if alpha == 0 {
+			continue
+		}
+		for j := start; j < end; j++ {
+			if trans {
+				c[i*ldc+j] += alpha * b.Ddot(k, a[i:], lda, a[j:], lda)
+			} else {
+				c[i*ldc+j] += alpha * b.Ddot(k, a[i*lda:], 1, a[j*lda:], 1)
+			}
+		}
+	}
+}
+
+/*
+ Solves one column of B at a time for the left side and one row at a time for
+ the right side, where X*op(A) = B is op(A)^T*X^T = B^T
+*/
+func (b GoBlas) Dtrsm(side Side, uplo Uplo, trans bool, diag Diag, m, n int, alpha float64, a []float64, lda int, bm []float64, ldb int) {
+	scaleMatrix(m, n, alpha, bm, ldb)
+	if alpha == 0 {
+		return
+	}
+	if side == Left {
+		for j := 0; j < n; j++ {
+			b.Dtrsv(uplo, trans, diag, m, a, lda, bm[j:], ldb)
+		}
+		return
+	}
+	for i := 0; i < m; i++ {
+		b.Dtrsv(uplo, !trans, diag, n, a, lda, bm[i*ldb:], 1)
+	}
+}
+
+/*
+ Size of the square tiles Dgemm works on, chosen so a tile of each operand fits in L1 cache
+*/
+const gemmBlock = 64
+
+/*
+ The product is accumulated tile by tile so each tile of B is reused from
+ cache for a whole block of rows of A.
+*/
+func (GoBlas) Dgemm(transA, transB bool, m, n, k int, alpha float64, a []float64, lda int, b []float64, ldb int, beta float64, c []float64, ldc int) {
+	if m == 0 || n == 0 {
+		return
+	}
+	scaleMatrix(m, n, beta, c, ldc)
+	if alpha == 0 || k == 0 {
+		return
+	}
+	for ii := 0; ii < m; ii += gemmBlock {
+		iEnd := min(ii+gemmBlock, m)
+		for ll := 0; ll < k; ll += gemmBlock {
+			lEnd := min(ll+gemmBlock, k)
+			for jj := 0; jj < n; jj += gemmBlock {
+				jEnd := min(jj+gemmBlock, n)
+				for i := ii; i < iEnd; i++ {
+					cRow := c[i*ldc+jj : i*ldc+jEnd]
+					for l := ll; l < lEnd; l++ {
+						var aVal float64
+						if transA {
+							aVal = alpha * a[l*lda+i]
+						} else {
+							aVal = alpha * a[i*lda+l]
+						}
+						if transB {
+							for j := range cRow {
+								cRow[j] += aVal * b[(jj+j)*ldb+l]
+							}
+						} else {
+							bRow := b[l*ldb+jj : l*ldb+jEnd]
+							for j, val := range bRow {
+								cRow[j] += aVal * val
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func (GoBlas) Zdotc(n int, x []complex128, incX int, y []complex128, incY int) complex128 {
+	var sum complex128
+	kx, ky := vectorStart(n, incX), vectorStart(n, incY)
+	for i := 0; i < n; i++ {
+		sum += cmplx.Conj(x[kx+i*incX]) * y[ky+i*incY]
+	}
+	return sum
+}
+
+func (GoBlas) Zgemm(transA, transB Trans, m, n, k int, alpha complex128, a []complex128, lda int, b []complex128, ldb int, beta complex128, c []complex128, ldc int) {
+	if m == 0 || n == 0 {
+		return
+	}
+	for i := 0; i < m; i++ {
+		cRow := c[i*ldc : i*ldc+n]
+		for j := range cRow {
+			if beta == 0 {
+				cRow[j] = 0
+			} else {
+				cRow[j] *= beta
+			}
+		}
+	}
+	if alpha == 0 || k == 0 {
+		return
+	}
+	for i := 0; i < m; i++ {
+		cRow := c[i*ldc : i*ldc+n]
+		for l := 0; l < k; l++ {
+			aVal := alpha * complexAt(transA, a, lda, i, l)
+			if transB == NoTrans {
+				for j, val := range b[l*ldb : l*ldb+n] {
+					cRow[j] += aVal * val
+				}
+			} else {
+				for j := range cRow {
+					cRow[j] += aVal * complexAt(transB, b, ldb, l, j)
+				}
+			}
+		}
+	}
+}
+
+/*
+ Returns element i, j of op(A) for the row major matrix A
+*/
+func complexAt(trans Trans, a []complex128, lda, i, j int) complex128 {
+	switch trans {
+	case Transposed:
+		return a[j*lda+i]
+	case ConjTrans:
+		return cmplx.Conj(a[j*lda+i])
+	}
+	return a[i*lda+j]
+}
@@ -0,0 +1,603 @@
+package goSci
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+/*
+ Vectors and matrices for the BLAS tests are laid out in slices padded with
+ NaN, so a routine that reads outside its operands gives NaN and one that
+ writes outside them changes the padding
+*/
+
+type blasVec struct {
+	buf    []float64
+	n, inc int
+}
+
+func newBlasVec(r *rand.Rand, n, inc int) blasVec {
+	v := blasVec{buf: make([]float64, 0), n: n, inc: inc}
+	if n > 0 {
+		v.buf = make([]float64, 1+(n-1)*max(inc, -inc))
+	}
+	for i := range v.buf {
+		v.buf[i] = math.NaN()
+	}
+	for i := 0; i < n; i++ {
+		v.buf[v.index(i)] = r.NormFloat64()
+	}
+	return v
+}
+
+func (v blasVec) index(i int) int {
+	return vectorStart(v.n, v.inc) + i*v.inc
+}
+
+func (v blasVec) vals() []float64 {
+	vals := make([]float64, v.n)
+	for i := range vals {
+		vals[i] = v.buf[v.index(i)]
+	}
+	return vals
+}
+
+func (v blasVec) padded() bool {
+	elems := make(map[int]bool)
+	for i := 0; i < v.n; i++ {
+		elems[v.index(i)] = true
+	}
+	for i, val := range v.buf {
+		if !elems[i] && !math.IsNaN(val) {
+			return false
+		}
+	}
+	return true
+}
+
+type blasMat struct {
+	buf            []float64
+	rows, cols, ld int
+}
+
+func newBlasMat(r *rand.Rand, rows, cols, ld int) blasMat {
+	a := blasMat{buf: make([]float64, rows*ld), rows: rows, cols: cols, ld: ld}
+	for i := range a.buf {
+		a.buf[i] = math.NaN()
+	}
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			a.buf[i*ld+j] = r.NormFloat64()
+		}
+	}
+	return a
+}
+
+func (a blasMat) at(i, j int) float64 {
+	return a.buf[i*a.ld+j]
+}
+
+/*
+ Returns element i, j of a, or of its transpose if trans is set
+*/
+func (a blasMat) op(trans bool, i, j int) float64 {
+	if trans {
+		return a.at(j, i)
+	}
+	return a.at(i, j)
+}
+
+func (a blasMat) padded() bool {
+	for i, val := range a.buf {
+		if i%a.ld >= a.cols && !math.IsNaN(val) {
+			return false
+		}
+	}
+	return true
+}
+
+func closeTo(got, want float64) bool {
+	return math.Abs(got-want) <= 1e-12*math.Max(1, math.Abs(want))
+}
+
+func TestGoBlasLevel1(t *testing.T) {
+	r := rand.New(rand.NewSource(51))
+	var b GoBlas
+	for _, n := range []int{0, 1, 7} {
+		for _, incs := range [][2]int{{1, 1}, {2, 3}, {-1, 1}, {3, -2}, {-2, -2}} {
+			x, y := newBlasVec(r, n, incs[0]), newBlasVec(r, n, incs[1])
+			xv, yv := x.vals(), y.vals()
+
+			dot := 0.0
+			for i := range xv {
+				dot += xv[i] * yv[i]
+			}
+			if got := b.Ddot(n, x.buf, x.inc, y.buf, y.inc); !closeTo(got, dot) {
+				t.Errorf("Ddot n %d incs %v: got %v, want %v", n, incs, got, dot)
+			}
+
+			for _, alpha := range []float64{0, -1.5} {
+				y := newBlasVec(r, n, incs[1])
+				want := y.vals()
+				for i := range want {
+					want[i] += alpha * xv[i]
+				}
+				b.Daxpy(n, alpha, x.buf, x.inc, y.buf, y.inc)
+				for i, got := range y.vals() {
+					if !closeTo(got, want[i]) {
+						t.Errorf("Daxpy n %d alpha %v incs %v: y[%d] = %v, want %v", n, alpha, incs, i, got, want[i])
+					}
+				}
+				if !y.padded() {
+					t.Errorf("Daxpy n %d incs %v wrote outside y", n, incs)
+				}
+			}
+
+			if x.inc < 0 {
+				// Dscal, Dnrm2, Dasum and Idamax take the vector to be empty
+				before := append([]float64(nil), x.buf...)
+				b.Dscal(n, 2, x.buf, x.inc)
+				for i := range before {
+					if math.Float64bits(before[i]) != math.Float64bits(x.buf[i]) {
+						t.Errorf("Dscal with inc %d changed x", x.inc)
+						break
+					}
+				}
+				if b.Dnrm2(n, x.buf, x.inc) != 0 || b.Dasum(n, x.buf, x.inc) != 0 || b.Idamax(n, x.buf, x.inc) != -1 {
+					t.Errorf("Dnrm2, Dasum or Idamax with inc %d did not see an empty vector", x.inc)
+				}
+				continue
+			}
+			norm, asum, imax := 0.0, 0.0, -1
+			for i, val := range xv {
+				norm += val * val
+				asum += math.Abs(val)
+				if imax < 0 || math.Abs(val) > math.Abs(xv[imax]) {
+					imax = i
+				}
+			}
+			if got := b.Dnrm2(n, x.buf, x.inc); !closeTo(got, math.Sqrt(norm)) {
+				t.Errorf("Dnrm2 n %d inc %d: got %v, want %v", n, x.inc, got, math.Sqrt(norm))
+			}
+			if got := b.Dasum(n, x.buf, x.inc); !closeTo(got, asum) {
+				t.Errorf("Dasum n %d inc %d: got %v, want %v", n, x.inc, got, asum)
+			}
+			if got := b.Idamax(n, x.buf, x.inc); got != imax {
+				t.Errorf("Idamax n %d inc %d: got %v, want %v", n, x.inc, got, imax)
+			}
+			b.Dscal(n, -3, x.buf, x.inc)
+			for i, got := range x.vals() {
+				if !closeTo(got, -3*xv[i]) {
+					t.Errorf("Dscal n %d inc %d: x[%d] = %v, want %v", n, x.inc, i, got, -3*xv[i])
+				}
+			}
+			if !x.padded() {
+				t.Errorf("Dscal n %d inc %d wrote outside x", n, x.inc)
+			}
+		}
+	}
+
+	// Dnrm2 scales so that neither huge nor tiny elements lose precision
+	big := []float64{3e200, 4e200}
+	if got := b.Dnrm2(2, big, 1); !closeTo(got/1e200, 5) {
+		t.Errorf("Dnrm2 of huge elements: got %v", got)
+	}
+	tiny := []float64{3e-200, 0, 4e-200}
+	if got := b.Dnrm2(2, tiny, 2); !closeTo(got/1e-200, 5) {
+		t.Errorf("Dnrm2 of tiny elements: got %v", got)
+	}
+}
+
+func TestGoBlasDgemvDger(t *testing.T) {
+	r := rand.New(rand.NewSource(52))
+	var b GoBlas
+	for _, size := range [][2]int{{3, 4}, {1, 5}, {4, 1}} {
+		m, n := size[0], size[1]
+		for _, trans := range []bool{false, true} {
+			for _, incs := range [][2]int{{1, 1}, {2, -3}, {-1, 2}} {
+				for _, ab := range [][2]float64{{1.5, 0.5}, {0, 2}, {-1, 0}} {
+					alpha, beta := ab[0], ab[1]
+					a := newBlasMat(r, m, n, n+2)
+					lenX, lenY := n, m
+					if trans {
+						lenX, lenY = m, n
+					}
+					x, y := newBlasVec(r, lenX, incs[0]), newBlasVec(r, lenY, incs[1])
+					if beta == 0 {
+						// y is not read when beta is zero
+						for i := 0; i < lenY; i++ {
+							y.buf[y.index(i)] = math.NaN()
+						}
+					}
+					if alpha == 0 {
+						// nor are A and x when alpha is zero
+						for i := range a.buf {
+							a.buf[i] = math.NaN()
+						}
+					}
+					xv, yv := x.vals(), y.vals()
+					want := make([]float64, lenY)
+					for i := range want {
+						sum := 0.0
+						if alpha != 0 {
+							for j := range xv {
+								if trans {
+									sum += a.at(j, i) * xv[j]
+								} else {
+									sum += a.at(i, j) * xv[j]
+								}
+							}
+						}
+						want[i] = alpha * sum
+						if beta != 0 {
+							want[i] += beta * yv[i]
+						}
+					}
+					b.Dgemv(trans, m, n, alpha, a.buf, a.ld, x.buf, x.inc, beta, y.buf, y.inc)
+					for i, got := range y.vals() {
+						if !closeTo(got, want[i]) {
+							t.Errorf("Dgemv %dx%d trans %v incs %v alpha %v beta %v: y[%d] = %v, want %v",
+								m, n, trans, incs, alpha, beta, i, got, want[i])
+						}
+					}
+					if !y.padded() {
+						t.Errorf("Dgemv %dx%d trans %v incs %v wrote outside y", m, n, trans, incs)
+					}
+				}
+			}
+		}
+
+		for _, incs := range [][2]int{{1, 1}, {3, -1}, {-2, 2}} {
+			a := newBlasMat(r, m, n, n+1)
+			x, y := newBlasVec(r, m, incs[0]), newBlasVec(r, n, incs[1])
+			xv, yv := x.vals(), y.vals()
+			want := newBlasMat(r, m, n, n+1)
+			copy(want.buf, a.buf)
+			b.Dger(m, n, 0.5, x.buf, x.inc, y.buf, y.inc, a.buf, a.ld)
+			for i := 0; i < m; i++ {
+				for j := 0; j < n; j++ {
+					if w := want.at(i, j) + 0.5*xv[i]*yv[j]; !closeTo(a.at(i, j), w) {
+						t.Errorf("Dger %dx%d incs %v: a[%d, %d] = %v, want %v", m, n, incs, i, j, a.at(i, j), w)
+					}
+				}
+			}
+			if !a.padded() {
+				t.Errorf("Dger %dx%d wrote outside A", m, n)
+			}
+		}
+	}
+
+	// with no rows or columns Dgemv returns at once, leaving y as it is, as
+	// the reference BLAS does
+	y := []float64{1, 2}
+	b.Dgemv(false, 2, 0, 1, nil, 1, nil, 1, 0.5, y, 1)
+	if y[0] != 1 || y[1] != 2 {
+		t.Errorf("Dgemv with no columns: y = %v", y)
+	}
+}
+
+/*
+ Returns a random n by n triangle with a dominant diagonal and NaN in the
+ other triangle and the padding, and a diagonal of NaN when it is implicit
+*/
+func newBlasTriangle(r *rand.Rand, n, ld int, uplo Uplo, diag Diag) blasMat {
+	a := newBlasMat(r, n, n, ld)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			switch {
+			case i == j && diag == Unit:
+				a.buf[i*ld+j] = math.NaN()
+			case i == j:
+				a.buf[i*ld+j] = float64(n) + r.Float64()
+			case (i < j) != (uplo == Upper):
+				a.buf[i*ld+j] = math.NaN()
+			}
+		}
+	}
+	return a
+}
+
+/*
+ Returns element i, j of op(A) for the triangle a, with zeros outside it
+*/
+func triangleAt(a blasMat, uplo Uplo, trans bool, diag Diag, i, j int) float64 {
+	if trans {
+		i, j = j, i
+	}
+	switch {
+	case i == j && diag == Unit:
+		return 1
+	case i != j && (i < j) != (uplo == Upper):
+		return 0
+	}
+	return a.at(i, j)
+}
+
+func TestGoBlasTriangular(t *testing.T) {
+	r := rand.New(rand.NewSource(53))
+	var b GoBlas
+	for _, uplo := range []Uplo{Upper, Lower} {
+		for _, trans := range []bool{false, true} {
+			for _, diag := range []Diag{NonUnit, Unit} {
+				n := 5
+				a := newBlasTriangle(r, n, n+3, uplo, diag)
+				for _, inc := range []int{1, 2, -3} {
+					x := newBlasVec(r, n, inc)
+					rhs := x.vals()
+					b.Dtrsv(uplo, trans, diag, n, a.buf, a.ld, x.buf, x.inc)
+					sol := x.vals()
+					for i := 0; i < n; i++ {
+						sum := 0.0
+						for j := 0; j < n; j++ {
+							sum += triangleAt(a, uplo, trans, diag, i, j) * sol[j]
+						}
+						if !closeTo(sum, rhs[i]) {
+							t.Errorf("Dtrsv uplo %v trans %v diag %v inc %d: row %d of op(A)*x is %v, want %v",
+								uplo, trans, diag, inc, i, sum, rhs[i])
+						}
+					}
+					if !x.padded() {
+						t.Errorf("Dtrsv inc %d wrote outside x", inc)
+					}
+				}
+
+				for _, side := range []Side{Left, Right} {
+					m := 3
+					rows, cols := n, m
+					if side == Right {
+						rows, cols = m, n
+					}
+					bm := newBlasMat(r, rows, cols, cols+1)
+					rhs := append([]float64(nil), bm.buf...)
+					b.Dtrsm(side, uplo, trans, diag, rows, cols, 2, a.buf, a.ld, bm.buf, bm.ld)
+					for i := 0; i < rows; i++ {
+						for j := 0; j < cols; j++ {
+							sum := 0.0
+							for l := 0; l < n; l++ {
+								if side == Left {
+									sum += triangleAt(a, uplo, trans, diag, i, l) * bm.at(l, j)
+								} else {
+									sum += bm.at(i, l) * triangleAt(a, uplo, trans, diag, l, j)
+								}
+							}
+							if want := 2 * rhs[i*bm.ld+j]; !closeTo(sum, want) {
+								t.Errorf("Dtrsm side %v uplo %v trans %v diag %v: element %d, %d is %v, want %v",
+									side, uplo, trans, diag, i, j, sum, want)
+							}
+						}
+					}
+					if !bm.padded() {
+						t.Errorf("Dtrsm side %v wrote outside B", side)
+					}
+				}
+			}
+		}
+	}
+
+	// alpha of zero sets B to zero without reading A
+	bm := newBlasMat(r, 2, 2, 3)
+	b.Dtrsm(Left, Upper, false, NonUnit, 2, 2, 0, []float64{math.NaN(), math.NaN(), math.NaN(), math.NaN()}, 2, bm.buf, bm.ld)
+	if bm.at(0, 0) != 0 || bm.at(1, 1) != 0 || !bm.padded() {
+		t.Errorf("Dtrsm with alpha 0: %v", bm.buf)
+	}
+}
+
+func TestGoBlasSymmetric(t *testing.T) {
+	r := rand.New(rand.NewSource(54))
+	var b GoBlas
+	for _, uplo := range []Uplo{Upper, Lower} {
+		for _, ab := range [][2]float64{{1.5, 0.5}, {0, -1}, {2, 0}} {
+			alpha, beta := ab[0], ab[1]
+			for _, side := range []Side{Left, Right} {
+				m, n := 3, 4
+				k := m
+				if side == Right {
+					k = n
+				}
+				// the other triangle of A is NaN, so reading it shows
+				a := newBlasTriangle(r, k, k+1, uplo, NonUnit)
+				sym := func(i, j int) float64 {
+					if (uplo == Upper) == (i <= j) {
+						return a.at(i, j)
+					}
+					return a.at(j, i)
+				}
+				bm, c := newBlasMat(r, m, n, n+2), newBlasMat(r, m, n, n+1)
+				if beta == 0 {
+					for i := 0; i < m; i++ {
+						for j := 0; j < n; j++ {
+							c.buf[i*c.ld+j] = math.NaN()
+						}
+					}
+				}
+				old := append([]float64(nil), c.buf...)
+				b.Dsymm(side, uplo, m, n, alpha, a.buf, a.ld, bm.buf, bm.ld, beta, c.buf, c.ld)
+				for i := 0; i < m; i++ {
+					for j := 0; j < n; j++ {
+						sum := 0.0
+						for l := 0; l < k; l++ {
+							if side == Left {
+								sum += sym(i, l) * bm.at(l, j)
+							} else {
+								sum += bm.at(i, l) * sym(l, j)
+							}
+						}
+						want := alpha * sum
+						if beta != 0 {
+							want += beta * old[i*c.ld+j]
+						}
+						if !closeTo(c.at(i, j), want) {
+							t.Errorf("Dsymm side %v uplo %v alpha %v beta %v: c[%d, %d] = %v, want %v",
+								side, uplo, alpha, beta, i, j, c.at(i, j), want)
+						}
+					}
+				}
+				if !c.padded() {
+					t.Errorf("Dsymm side %v wrote outside C", side)
+				}
+			}
+
+			for _, trans := range []bool{false, true} {
+				n, k := 4, 3
+				rows, cols := n, k
+				if trans {
+					rows, cols = k, n
+				}
+				a := newBlasMat(r, rows, cols, cols+2)
+				c := newBlasMat(r, n, n, n+1)
+				if beta == 0 {
+					for i := range c.buf {
+						if i%c.ld < n {
+							c.buf[i] = math.NaN()
+						}
+					}
+				}
+				old := append([]float64(nil), c.buf...)
+				b.Dsyrk(uplo, trans, n, k, alpha, a.buf, a.ld, beta, c.buf, c.ld)
+				for i := 0; i < n; i++ {
+					for j := 0; j < n; j++ {
+						if i != j && (i < j) != (uplo == Upper) {
+							if math.Float64bits(c.at(i, j)) != math.Float64bits(old[i*c.ld+j]) {
+								t.Errorf("Dsyrk uplo %v changed c[%d, %d] outside the triangle", uplo, i, j)
+							}
+							continue
+						}
+						sum := 0.0
+						for l := 0; l < k; l++ {
+							sum += a.op(trans, i, l) * a.op(trans, j, l)
+						}
+						want := alpha * sum
+						if beta != 0 {
+							want += beta * old[i*c.ld+j]
+						}
+						if !closeTo(c.at(i, j), want) {
+							t.Errorf("Dsyrk uplo %v trans %v alpha %v beta %v: c[%d, %d] = %v, want %v",
+								uplo, trans, alpha, beta, i, j, c.at(i, j), want)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestGoBlasDgemm(t *testing.T) {
+	r := rand.New(rand.NewSource(55))
+	var b GoBlas
+	// 70 crosses a tile boundary of gemmBlock
+	for _, size := range [][3]int{{3, 4, 5}, {1, 1, 1}, {70, 3, 66}, {2, 3, 0}, {0, 3, 2}, {4, 0, 2}} {
+		m, n, k := size[0], size[1], size[2]
+		for _, transA := range []bool{false, true} {
+			for _, transB := range []bool{false, true} {
+				for _, ab := range [][2]float64{{1.5, 0.5}, {0, 2}, {-1, 0}} {
+					alpha, beta := ab[0], ab[1]
+					ar, ac := m, k
+					if transA {
+						ar, ac = k, m
+					}
+					br, bc := k, n
+					if transB {
+						br, bc = n, k
+					}
+					a, bm, c := newBlasMat(r, ar, ac, ac+1), newBlasMat(r, br, bc, bc+3), newBlasMat(r, m, n, n+2)
+					if alpha == 0 {
+						for i := range a.buf {
+							a.buf[i] = math.NaN()
+						}
+					}
+					if beta == 0 {
+						for i := range c.buf {
+							if i%c.ld < n {
+								c.buf[i] = math.NaN()
+							}
+						}
+					}
+					old := append([]float64(nil), c.buf...)
+					b.Dgemm(transA, transB, m, n, k, alpha, a.buf, max(a.ld, 1), bm.buf, max(bm.ld, 1), beta, c.buf, max(c.ld, 1))
+					for i := 0; i < m; i++ {
+						for j := 0; j < n; j++ {
+							sum := 0.0
+							if alpha != 0 {
+								for l := 0; l < k; l++ {
+									sum += a.op(transA, i, l) * bm.op(transB, l, j)
+								}
+							}
+							want := alpha * sum
+							if beta != 0 {
+								want += beta * old[i*c.ld+j]
+							}
+							if !closeTo(c.at(i, j), want) {
+								t.Fatalf("Dgemm %v transA %v transB %v alpha %v beta %v: c[%d, %d] = %v, want %v",
+									size, transA, transB, alpha, beta, i, j, c.at(i, j), want)
+							}
+						}
+					}
+					if !c.padded() {
+						t.Errorf("Dgemm %v wrote outside C", size)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestGoBlasComplex(t *testing.T) {
+	r := rand.New(rand.NewSource(56))
+	var b GoBlas
+	random := func(n int) []complex128 {
+		z := make([]complex128, n)
+		for i := range z {
+			z[i] = complex(r.NormFloat64(), r.NormFloat64())
+		}
+		return z
+	}
+
+	for _, incs := range [][2]int{{1, 1}, {2, -1}, {-3, 2}} {
+		n := 5
+		x := random(1 + (n-1)*max(incs[0], -incs[0]))
+		y := random(1 + (n-1)*max(incs[1], -incs[1]))
+		var want complex128
+		for i := 0; i < n; i++ {
+			want += cmplx.Conj(x[vectorStart(n, incs[0])+i*incs[0]]) * y[vectorStart(n, incs[1])+i*incs[1]]
+		}
+		if got := b.Zdotc(n, x, incs[0], y, incs[1]); cmplx.Abs(got-want) > 1e-12 {
+			t.Errorf("Zdotc incs %v: got %v, want %v", incs, got, want)
+		}
+	}
+
+	m, n, k := 3, 4, 2
+	for _, transA := range []Trans{NoTrans, Transposed, ConjTrans} {
+		for _, transB := range []Trans{NoTrans, Transposed, ConjTrans} {
+			lda, ldb, ldc := k+1, n+2, n+1
+			if transA != NoTrans {
+				lda = m + 1
+			}
+			if transB != NoTrans {
+				ldb = k + 2
+			}
+			a, bm, c := random(max(m, k)*lda), random(max(n, k)*ldb), random(m*ldc)
+			old := append([]complex128(nil), c...)
+			alpha, beta := complex(0.5, 1), complex(0, -1)
+			b.Zgemm(transA, transB, m, n, k, alpha, a, lda, bm, ldb, beta, c, ldc)
+			for i := 0; i < m; i++ {
+				for j := 0; j < n; j++ {
+					var sum complex128
+					for l := 0; l < k; l++ {
+						sum += complexAt(transA, a, lda, i, l) * complexAt(transB, bm, ldb, l, j)
+					}
+					want := alpha*sum + beta*old[i*ldc+j]
+					if cmplx.Abs(c[i*ldc+j]-want) > 1e-12 {
+						t.Errorf("Zgemm transA %v transB %v: c[%d, %d] = %v, want %v", transA, transB, i, j, c[i*ldc+j], want)
+					}
+				}
+				for j := n; j < ldc; j++ {
+					if c[i*ldc+j] != old[i*ldc+j] {
+						t.Errorf("Zgemm wrote outside C at row %d", i)
+					}
+				}
+			}
+		}
+	}
+}
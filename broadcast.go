@@ -0,0 +1,179 @@
+package goSci
+
+/*
+ Returns the shape that arrays with shapes x and y broadcast to, and false if
+ they are incompatible. Shapes are aligned on their trailing dimensions and each
+ pair of dimensions must either be equal or one of them must be 1; a missing
+ leading dimension is treated as 1.
+*/
+func broadcastShapes(x, y []int) ([]int, bool) {
+	n := len(x)
+	if len(y) > n {
+		n = len(y)
+	}
+	shape := make([]int, n)
+	for i := 1; i <= n; i++ {
+		dx, dy := 1, 1
+		if i <= len(x) {
+			dx = x[len(x)-i]
+		}
+		if i <= len(y) {
+			dy = y[len(y)-i]
+		}
+		switch {
+		case dx == dy || dy == 1:
+			shape[n-i] = dx
+		case dx == 1:
+			shape[n-i] = dy
+		default:
+			return nil, false
+		}
+	}
+	return shape, true
+}
+
+/*
+ Returns the row major strides of an array with the given shape
+*/
+func contiguousStrides(shape []int) []int {
+	strides := make([]int, len(shape))
+	step := 1
+	for i := len(shape) - 1; i >= 0; i-- {
+		strides[i] = step
+		step *= shape[i]
+	}
+	return strides
+}
+
+/*
+ Returns the strides needed to read x as if it had shape shape. Broadcast
+ dimensions get a stride of zero so the same element is visited repeatedly.
+*/
+func broadcastStrides(x *GsArray, shape []int) []int {
+	own := x.getStrides()
+	strides := make([]int, len(shape))
+	lead := len(shape) - len(x.shape)
+	for i := range x.shape {
+		if x.shape[i] != 1 || shape[lead+i] == 1 {
+			strides[lead+i] = own[i]
+		}
+	}
+	return strides
+}
+
+/*
+ Walks every position of shape in row major order. pos holds one flat index
+ per operand, starting at offsets and advanced by the matching strides, and is
+ passed to visit at each position.
+*/
+func walkStrided(shape []int, offsets []int, strides [][]int, visit func(pos []int)) {
+	for _, dim := range shape {
+		if dim == 0 {
+			return
+		}
+	}
+	pos := make([]int, len(offsets))
+	copy(pos, offsets)
+	counter := make([]int, len(shape))
+	for {
+		visit(pos)
+		d := len(shape) - 1
+		for ; d >= 0; d-- {
+			counter[d]++
+			for k := range pos {
+				pos[k] += strides[k][d]
+			}
+			if counter[d] < shape[d] {
+				break
+			}
+			for k := range pos {
+				pos[k] -= strides[k][d] * shape[d]
+			}
+			counter[d] = 0
+		}
+		if d < 0 {
+			return
+		}
+	}
+}
+
+func sameShape(x, y []int) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+ Returns op applied elementwise to x and y after broadcasting them to a
+ common shape. name is the operation reported in a ShapeMismatchError.
+*/
+func broadcastOp(name string, x, y *GsArray, op func(a, b float64) float64) (*GsArray, error) {
+	if sameShape(x.shape, y.shape) && x.strides == nil && y.strides == nil {
+		result := Zeros(x.shape...)
+		parallelFor(len(result.data), len(result.data), func(start, end int) {
+			for i := start; i < end; i++ {
+				result.data[i] = op(x.data[i], y.data[i])
+			}
+		})
+		return result, nil
+	}
+	shape, ok := broadcastShapes(x.shape, y.shape)
+	if !ok {
+		return nil, shapeError(name, x.shape, y.shape)
+	}
+	result := Zeros(shape...)
+	strides := [][]int{contiguousStrides(shape), broadcastStrides(x, shape), broadcastStrides(y, shape)}
+	parallelWalk(shape, splitAxis(shape), []int{0, x.offset, y.offset}, strides, func(pos []int) {
+		result.data[pos[0]] = op(x.data[pos[1]], y.data[pos[2]])
+	})
+	return result, nil
+}
+
+/*
+ Stores op(x, y) elementwise in x, broadcasting y to the shape of x.
+ name is the operation reported in a ShapeMismatchError.
+*/
+func broadcastOpInPlace(name string, x, y *GsArray, op func(a, b float64) float64) error {
+	if overlaps(x, y) {
+		y = y.Copy()
+	}
+	if sameShape(x.shape, y.shape) && x.strides == nil && y.strides == nil {
+		parallelFor(len(x.data), len(x.data), func(start, end int) {
+			for i := start; i < end; i++ {
+				x.data[i] = op(x.data[i], y.data[i])
+			}
+		})
+		return nil
+	}
+	shape, ok := broadcastShapes(x.shape, y.shape)
+	if !ok || !sameShape(shape, x.shape) {
+		return shapeError(name, x.shape, y.shape)
+	}
+	strides := [][]int{x.getStrides(), broadcastStrides(y, shape)}
+	parallelWalk(shape, splitAxis(shape), []int{x.offset, y.offset}, strides, func(pos []int) {
+		x.data[pos[0]] = op(x.data[pos[0]], y.data[pos[1]])
+	})
+	return nil
+}
+
+/*
+ Reports whether x and y are views of the same data, in which case writing
+ to x while reading y may observe partially updated values.
+*/
+func overlaps(x, y *GsArray) bool {
+	if x == y || len(x.data) == 0 || len(y.data) == 0 {
+		return false
+	}
+	return &x.data[:cap(x.data)][cap(x.data)-1] == &y.data[:cap(y.data)][cap(y.data)-1]
+}
+
+func add(a, b float64) float64 { return a + b }
+func sub(a, b float64) float64 { return a - b }
+func mul(a, b float64) float64 { return a * b }
+func div(a, b float64) float64 { return a / b }
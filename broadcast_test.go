@@ -0,0 +1,95 @@
+package goSci
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestBroadcastArithmetic(t *testing.T) {
+	x := FromSlice([]float64{0, 1, 2, 3, 4, 5}, 2, 3)
+	row := FromSlice([]float64{10, 20, 30}, 3)
+	col := FromSlice([]float64{100, 200}, 2, 1)
+
+	assertArray(t, "Plus row", Plus(x, row), []int{2, 3}, []float64{10, 21, 32, 13, 24, 35})
+	assertArray(t, "Plus col", Plus(x, col), []int{2, 3}, []float64{100, 101, 102, 203, 204, 205})
+	assertArray(t, "Minus col row", Minus(col, row), []int{2, 3}, []float64{90, 80, 70, 190, 180, 170})
+	assertArray(t, "ElemTimes", ElemTimes(row, x), []int{2, 3}, []float64{0, 20, 60, 30, 80, 150})
+	assertArray(t, "Divide", Divide(x, col), []int{2, 3}, []float64{0, 0.01, 0.02, 0.015, 0.02, 0.025})
+	assertArray(t, "Pow", Pow(x, FromSlice([]float64{2}, 1)), []int{2, 3}, []float64{0, 1, 4, 9, 16, 25})
+
+	x.PlusEquals(row)
+	assertArray(t, "PlusEquals", x, []int{2, 3}, []float64{10, 21, 32, 13, 24, 35})
+}
+
+func TestBroadcastShapeMismatch(t *testing.T) {
+	x := Zeros(2, 3)
+	cases := []struct {
+		op  string
+		err error
+	}{
+		{"Plus", second(TryPlus(x, Zeros(2)))},
+		{"Minus", second(TryMinus(x, Zeros(4, 3)))},
+		{"ElemTimes", second(TryElemTimes(x, Zeros(3, 2)))},
+		{"Divide", second(TryDivide(x, Zeros(2, 2)))},
+		// broadcasting may not grow the array updated in place
+		{"PlusEquals", Zeros(1, 3).TryPlusEquals(x)},
+	}
+	for _, c := range cases {
+		var sm *ShapeMismatchError
+		if !errors.As(c.err, &sm) {
+			t.Fatalf("%s: got %v, want a ShapeMismatchError", c.op, c.err)
+		}
+		if sm.Op != c.op || sm.Shape1 == nil || sm.Shape2 == nil {
+			t.Errorf("%s: got %+v", c.op, sm)
+		}
+	}
+	_, err := TryPlus(x, Zeros(2))
+	var sm *ShapeMismatchError
+	errors.As(err, &sm)
+	if !reflect.DeepEqual(sm.Shape1, []int{2, 3}) || !reflect.DeepEqual(sm.Shape2, []int{2}) {
+		t.Errorf("Plus payload: got %v and %v", sm.Shape1, sm.Shape2)
+	}
+}
+
+func second[T any](_ T, err error) error {
+	return err
+}
+
+/*
+ Helpers shared by the tests of the package
+*/
+
+func randomArray(r *rand.Rand, shape ...int) *GsArray {
+	x := Zeros(shape...)
+	for i := range x.data {
+		x.data[i] = r.NormFloat64()
+	}
+	return x
+}
+
+func maxAbsDiff(x, y *GsArray) float64 {
+	diff := 0.0
+	xv, yv := x.values(), y.values()
+	for i := range xv {
+		diff = math.Max(diff, math.Abs(xv[i]-yv[i]))
+	}
+	return diff
+}
+
+func assertArray(t *testing.T, name string, got *GsArray, shape []int, want []float64) {
+	t.Helper()
+	assertClose(t, name, got, FromSlice(want, shape...), 1e-12)
+}
+
+func assertClose(t *testing.T, name string, got, want *GsArray, tol float64) {
+	t.Helper()
+	if !sameShape(got.shape, want.shape) {
+		t.Fatalf("%s: shape %v, want %v", name, got.shape, want.shape)
+	}
+	if diff := maxAbsDiff(got, want); !(diff <= tol) {
+		t.Errorf("%s: off by %g\ngot  %v\nwant %v", name, diff, got.Data(), want.Data())
+	}
+}
@@ -0,0 +1,119 @@
+package goSci
+
+import "math"
+
+/*
+ Returns the lower triangular factor L of the Cholesky decomposition
+ a = L*L^T of the symmetric positive definite matrix a. Only the lower
+ triangle of a is used. Returns ErrNotPositiveDefinite if a is not positive
+ definite.
+*/
+func Cholesky(a *GsArray) (*GsArray, error) {
+	if len(a.shape) != 2 || a.shape[0] != a.shape[1] {
+		return nil, shapeError("Cholesky", a.shape, nil)
+	}
+	n := a.shape[0]
+	l := a.Copy()
+	if !lapackEngine.Dpotrf(Lower, n, l.data, max(n, 1)) {
+		return nil, ErrNotPositiveDefinite
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			l.data[i*n+j] = 0
+		}
+	}
+	return l, nil
+}
+
+/*
+ Returns x solving L*L^T*x = b given the Cholesky factor l from Cholesky, so
+ the factorization can be reused for many right hand sides. b may be a vector
+ or a matrix whose columns are separate right hand sides, and x has the same
+ shape as b.
+*/
+func CholSolve(l, b *GsArray) (*GsArray, error) {
+	if len(l.shape) != 2 || l.shape[0] != l.shape[1] {
+		return nil, shapeError("CholSolve", l.shape, nil)
+	}
+	n := l.shape[0]
+	if len(b.shape) == 0 || len(b.shape) > 2 || b.shape[0] != n {
+		return nil, shapeError("CholSolve", l.shape, b.shape)
+	}
+	for i := 0; i < n; i++ {
+		if l.Get(i, i) == 0 {
+			return nil, ErrSingular
+		}
+	}
+	x := b.Copy()
+	nrhs := 1
+	if len(x.shape) == 2 {
+		nrhs = x.shape[1]
+	}
+	lapackEngine.Dpotrs(Lower, n, nrhs, l.values(), max(n, 1), x.data, max(nrhs, 1))
+	return x, nil
+}
+
+/*
+ Returns the natural logarithm of the determinant of L*L^T given the Cholesky
+ factor l, which avoids the overflow and underflow of the determinant itself
+*/
+func CholLogDet(l *GsArray) (float64, error) {
+	if len(l.shape) != 2 || l.shape[0] != l.shape[1] {
+		return 0, shapeError("CholLogDet", l.shape, nil)
+	}
+	logDet := 0.0
+	for i := 0; i < l.shape[0]; i++ {
+		logDet += math.Log(math.Abs(l.Get(i, i)))
+	}
+	return 2 * logDet, nil
+}
+
+/*
+ Updates the Cholesky factor l of A in place to the factor of A + x*x^T for
+ the vector x, in O(n^2) operations rather than the O(n^3) of refactoring
+*/
+func CholUpdate(l, x *GsArray) error {
+	return cholRankOne("CholUpdate", l, x, 1)
+}
+
+/*
+ Updates the Cholesky factor l of A in place to the factor of A - x*x^T for
+ the vector x. Returns ErrNotPositiveDefinite, leaving l unchanged, if
+ A - x*x^T is not positive definite.
+*/
+func CholDowndate(l, x *GsArray) error {
+	return cholRankOne("CholDowndate", l, x, -1)
+}
+
+/*
+ Applies the rank one modification L*L^T + sign*x*x^T to l as a sequence of
+ plane rotations, hyperbolic ones for a downdate
+*/
+func cholRankOne(op string, l, x *GsArray, sign float64) error {
+	if len(l.shape) != 2 || l.shape[0] != l.shape[1] {
+		return shapeError(op, l.shape, nil)
+	}
+	n := l.shape[0]
+	if len(x.shape) != 1 || x.shape[0] != n {
+		return shapeError(op, l.shape, x.shape)
+	}
+	// work on copies so a failed downdate leaves l as it was
+	lv := l.Copy().data
+	xv := x.Copy().data
+	for k := 0; k < n; k++ {
+		lkk := lv[k*n+k]
+		r2 := lkk*lkk + sign*xv[k]*xv[k]
+		if !(r2 > 0) {
+			return ErrNotPositiveDefinite
+		}
+		r := math.Sqrt(r2)
+		c, s := r/lkk, xv[k]/lkk
+		lv[k*n+k] = r
+		for i := k + 1; i < n; i++ {
+			lv[i*n+k] = (lv[i*n+k] + sign*s*xv[i]) / c
+			xv[i] = c*xv[i] - s*lv[i*n+k]
+		}
+	}
+	l.setValues(lv)
+	return nil
+}
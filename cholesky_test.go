@@ -0,0 +1,133 @@
+package goSci
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+/*
+ Returns a random n by n symmetric positive definite matrix
+*/
+func randomSPD(r *rand.Rand, n int) *GsArray {
+	b := randomArray(r, n, n)
+	a := MatMult(b, b.T())
+	a.PlusEquals(Eye(n))
+	return a
+}
+
+func TestCholesky(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for _, n := range []int{1, 3, 8} {
+		a := randomSPD(r, n)
+		l, err := Cholesky(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertClose(t, "L*L^T", MatMult(l, l.T()), a, 1e-12)
+		for i := 0; i < n; i++ {
+			if l.Get(i, i) <= 0 {
+				t.Errorf("L has %v on its diagonal", l.Get(i, i))
+			}
+			for j := i + 1; j < n; j++ {
+				if l.Get(i, j) != 0 {
+					t.Fatalf("L is not lower triangular at %d, %d", i, j)
+				}
+			}
+		}
+
+		logDet, err := CholLogDet(l)
+		if err != nil {
+			t.Fatal(err)
+		}
+		det, _ := Det(a)
+		if math.Abs(logDet-math.Log(det)) > 1e-10 {
+			t.Errorf("CholLogDet: got %v, want %v", logDet, math.Log(det))
+		}
+
+		b := randomArray(r, n, 2)
+		x, err := CholSolve(l, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertClose(t, "A*X", MatMult(a, x), b, 1e-10)
+	}
+
+	// only the lower triangle is read
+	a := randomSPD(r, 4)
+	upper := a.Copy()
+	for i := 0; i < 4; i++ {
+		for j := i + 1; j < 4; j++ {
+			upper.Put(math.NaN(), i, j)
+		}
+	}
+	l, _ := Cholesky(a)
+	lu, err := Cholesky(upper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "Cholesky of the lower triangle", lu, l, 0)
+}
+
+func TestCholRankOne(t *testing.T) {
+	r := rand.New(rand.NewSource(12))
+	n := 6
+	a := randomSPD(r, n)
+	v := randomArray(r, n)
+	vvT := MatMult(reshaped(v, []int{n, 1}), reshaped(v, []int{1, n}))
+
+	l, _ := Cholesky(a)
+	if err := CholUpdate(l, v); err != nil {
+		t.Fatal(err)
+	}
+	updated := Plus(a, vvT)
+	assertClose(t, "L*L^T after CholUpdate", MatMult(l, l.T()), updated, 1e-10)
+	want, _ := Cholesky(updated)
+	assertClose(t, "L after CholUpdate", l, want, 1e-10)
+
+	// downdating by the same vector gives back the factor of a
+	if err := CholDowndate(l, v); err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "L*L^T after CholDowndate", MatMult(l, l.T()), a, 1e-10)
+
+	// x may be a strided view
+	x := randomArray(r, 2*n).Slice(Span(0, 2*n, 2))
+	l, _ = Cholesky(a)
+	CholUpdate(l, x)
+	xxT := MatMult(reshaped(x.Copy(), []int{n, 1}), reshaped(x.Copy(), []int{1, n}))
+	assertClose(t, "CholUpdate with a strided x", MatMult(l, l.T()), Plus(a, xxT), 1e-10)
+}
+
+func TestCholeskyErrors(t *testing.T) {
+	// symmetric with eigenvalues 3 and -1
+	indefinite := FromSlice([]float64{1, 2, 2, 1}, 2, 2)
+	if _, err := Cholesky(indefinite); !errors.Is(err, ErrNotPositiveDefinite) {
+		t.Errorf("Cholesky of an indefinite matrix: got %v", err)
+	}
+
+	// a - v*v^T is singular, and removing any more loses definiteness
+	a := FromSlice([]float64{4, 2, 2, 3}, 2, 2)
+	l, _ := Cholesky(a)
+	before := l.Copy()
+	if err := CholDowndate(l, FromSlice([]float64{2, 1}, 2)); !errors.Is(err, ErrNotPositiveDefinite) {
+		t.Errorf("CholDowndate to a singular matrix: got %v", err)
+	}
+	if err := CholDowndate(l, FromSlice([]float64{0, 3}, 2)); !errors.Is(err, ErrNotPositiveDefinite) {
+		t.Errorf("CholDowndate to an indefinite matrix: got %v", err)
+	}
+	assertClose(t, "L after a failed CholDowndate", l, before, 0)
+
+	for name, err := range map[string]error{
+		"Cholesky":     second(Cholesky(Zeros(2, 3))),
+		"CholSolve":    second(CholSolve(Eye(3), Zeros(2))),
+		"CholLogDet":   second(CholLogDet(Zeros(3))),
+		"CholUpdate":   CholUpdate(Eye(3), Zeros(2)),
+		"CholDowndate": CholDowndate(Eye(3), Zeros(3, 1)),
+	} {
+		if !errors.As(err, new(*ShapeMismatchError)) {
+			t.Errorf("%s with mismatched shapes: got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,412 @@
+package goSci
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+/*
+ Returns the complex array re + i*im. re and im must have the same shape.
+*/
+func Complex(re, im *GsArray) *Array[complex128] {
+	return must(TryComplex(re, im))
+}
+
+/*
+ Same as Complex but returns a ShapeMismatchError instead of panicking
+*/
+func TryComplex(re, im *GsArray) (*Array[complex128], error) {
+	if !sameShape(re.shape, im.shape) {
+		return nil, shapeError("Complex", re.shape, im.shape)
+	}
+	reVals, imVals := re.values(), im.values()
+	result := ZerosOf[complex128](re.shape...)
+	for i := range result.data {
+		result.data[i] = complex(reVals[i], imVals[i])
+	}
+	return result, nil
+}
+
+/*
+ Returns the complex conjugate of x
+*/
+func Conj(x *Array[complex128]) *Array[complex128] {
+	result := x.Copy()
+	for i, val := range result.data {
+		result.data[i] = cmplx.Conj(val)
+	}
+	return result
+}
+
+/*
+ Returns the real parts of x
+*/
+func Real(x *Array[complex128]) *GsArray {
+	return complexPart(x, func(c complex128) float64 { return real(c) })
+}
+
+/*
+ Returns the imaginary parts of x
+*/
+func Imag(x *Array[complex128]) *GsArray {
+	return complexPart(x, func(c complex128) float64 { return imag(c) })
+}
+
+/*
+ Returns the magnitudes of the elements of x
+*/
+func CAbs(x *Array[complex128]) *GsArray {
+	return complexPart(x, cmplx.Abs)
+}
+
+/*
+ Returns the arguments of the elements of x, in radians in [-pi, pi]
+*/
+func Angle(x *Array[complex128]) *GsArray {
+	return complexPart(x, cmplx.Phase)
+}
+
+func complexPart(x *Array[complex128], part func(complex128) float64) *GsArray {
+	result := Zeros(x.Shape()...)
+	for i, val := range x.data {
+		result.data[i] = part(val)
+	}
+	return result
+}
+
+/*
+ Returns the conjugate transpose, or Hermitian transpose, of the matrix x
+*/
+func ConjTranspose(x *Array[complex128]) *Array[complex128] {
+	return must(TryConjTranspose(x))
+}
+
+/*
+ Same as ConjTranspose but returns a ShapeMismatchError instead of panicking
+ when x is not a matrix
+*/
+func TryConjTranspose(x *Array[complex128]) (*Array[complex128], error) {
+	if len(x.shape) != 2 {
+		return nil, shapeError("ConjTranspose", x.shape, nil)
+	}
+	rows, cols := x.shape[0], x.shape[1]
+	result := ZerosOf[complex128](cols, rows)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			result.data[j*rows+i] = cmplx.Conj(x.data[i*cols+j])
+		}
+	}
+	return result, nil
+}
+
+/*
+ Returns the inner product of the vectors x and y, conjugating x:
+ sum(conj(x[i])*y[i])
+*/
+func CDot(x, y *Array[complex128]) complex128 {
+	return must(TryCDot(x, y))
+}
+
+/*
+ Same as CDot but returns a ShapeMismatchError instead of panicking
+*/
+func TryCDot(x, y *Array[complex128]) (complex128, error) {
+	if len(x.shape) != 1 || !sameShape(x.shape, y.shape) {
+		return 0, shapeError("CDot", x.shape, y.shape)
+	}
+	return engine.Zdotc(len(x.data), x.data, 1, y.data, 1), nil
+}
+
+/*
+ Returns the matrix product of the complex matrices x and y
+*/
+func CMatMult(x, y *Array[complex128]) *Array[complex128] {
+	return must(TryCMatMult(x, y))
+}
+
+/*
+ Same as CMatMult but returns a ShapeMismatchError instead of panicking
+*/
+func TryCMatMult(x, y *Array[complex128]) (*Array[complex128], error) {
+	if len(x.shape) != 2 || len(y.shape) != 2 || x.shape[1] != y.shape[0] {
+		return nil, shapeError("CMatMult", x.shape, y.shape)
+	}
+	m, k, n := x.shape[0], x.shape[1], y.shape[1]
+	result := ZerosOf[complex128](m, n)
+	engine.Zgemm(NoTrans, NoTrans, m, n, k, 1, x.data, max(k, 1), y.data, max(n, 1), 0, result.data, max(n, 1))
+	return result, nil
+}
+
+/*
+ Returns x solving a*x = b for the square complex matrix a by LU
+ decomposition with partial pivoting. b may be a vector or a matrix whose
+ columns are separate right hand sides, and x has the same shape as b.
+ Returns ErrSingular if a is singular.
+*/
+func CSolve(a, b *Array[complex128]) (*Array[complex128], error) {
+	if len(a.shape) != 2 || a.shape[0] != a.shape[1] {
+		return nil, shapeError("CSolve", a.shape, nil)
+	}
+	n := a.shape[0]
+	if len(b.shape) == 0 || len(b.shape) > 2 || b.shape[0] != n {
+		return nil, shapeError("CSolve", a.shape, b.shape)
+	}
+	nrhs := 1
+	if len(b.shape) == 2 {
+		nrhs = b.shape[1]
+	}
+	lu, x := a.Copy().data, b.Copy()
+	for k := 0; k < n; k++ {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if cmplx.Abs(lu[i*n+k]) > cmplx.Abs(lu[p*n+k]) {
+				p = i
+			}
+		}
+		if lu[p*n+k] == 0 {
+			return nil, ErrSingular
+		}
+		if p != k {
+			for j := 0; j < n; j++ {
+				lu[k*n+j], lu[p*n+j] = lu[p*n+j], lu[k*n+j]
+			}
+			for j := 0; j < nrhs; j++ {
+				x.data[k*nrhs+j], x.data[p*nrhs+j] = x.data[p*nrhs+j], x.data[k*nrhs+j]
+			}
+		}
+		for i := k + 1; i < n; i++ {
+			l := lu[i*n+k] / lu[k*n+k]
+			for j := k + 1; j < n; j++ {
+				lu[i*n+j] -= l * lu[k*n+j]
+			}
+			for j := 0; j < nrhs; j++ {
+				x.data[i*nrhs+j] -= l * x.data[k*nrhs+j]
+			}
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := 0; j < nrhs; j++ {
+			sum := x.data[i*nrhs+j]
+			for k := i + 1; k < n; k++ {
+				sum -= lu[i*n+k] * x.data[k*nrhs+j]
+			}
+			x.data[i*nrhs+j] = sum / lu[i*n+i]
+		}
+	}
+	return x, nil
+}
+
+/*
+ Returns the eigenvalues and eigenvectors of the square complex matrix a.
+ vectors[j] is the eigenvector belonging to values[j], scaled to unit 2-norm.
+ The matrix is reduced to Hessenberg form with Householder reflections, then
+ to complex Schur form with the single shift QR algorithm, and the
+ eigenvectors are found by back substitution in the triangular factor.
+*/
+func CEig(a *Array[complex128]) (values []complex128, vectors [][]complex128, err error) {
+	if len(a.shape) != 2 || a.shape[0] != a.shape[1] {
+		return nil, nil, shapeError("CEig", a.shape, nil)
+	}
+	n := a.shape[0]
+	t := a.Copy().data
+	q := EyeOf[complex128](n).data
+	complexHessenberg(n, t, q)
+	if !complexSchur(n, t, q) {
+		return nil, nil, ErrNoConvergence
+	}
+	norm := 0.0
+	for _, val := range t {
+		norm = math.Max(norm, cmplx.Abs(val))
+	}
+	// smallest divisor allowed in the back substitution
+	small := math.Max(norm*2.220446049250313e-16, math.SmallestNonzeroFloat64)
+	values = make([]complex128, n)
+	vectors = make([][]complex128, n)
+	y := make([]complex128, n)
+	for k := 0; k < n; k++ {
+		values[k] = t[k*n+k]
+		// solve (T - values[k]*I)*y = 0 with y[k] = 1 and y zero below k
+		y[k] = 1
+		for i := k - 1; i >= 0; i-- {
+			var sum complex128
+			for j := i + 1; j <= k; j++ {
+				sum += t[i*n+j] * y[j]
+			}
+			denom := t[i*n+i] - values[k]
+			if cmplx.Abs(denom) < small {
+				denom = complex(small, 0)
+			}
+			y[i] = -sum / denom
+		}
+		vec := make([]complex128, n)
+		for i := range vec {
+			for j := 0; j <= k; j++ {
+				vec[i] += q[i*n+j] * y[j]
+			}
+		}
+		scale := 0.0
+		for _, val := range vec {
+			scale = math.Hypot(scale, cmplx.Abs(val))
+		}
+		for i := range vec {
+			vec[i] /= complex(scale, 0)
+		}
+		vectors[k] = vec
+	}
+	return values, vectors, nil
+}
+
+/*
+ Reduces the n by n complex matrix h to upper Hessenberg form by unitary
+ similarity transformations, accumulated into q
+*/
+func complexHessenberg(n int, h, q []complex128) {
+	v := make([]complex128, n)
+	for k := 0; k < n-2; k++ {
+		// reflect h[k+1:n, k] onto a multiple of the first unit vector
+		norm := 0.0
+		for i := k + 1; i < n; i++ {
+			norm = math.Hypot(norm, cmplx.Abs(h[i*n+k]))
+		}
+		if norm == 0 {
+			continue
+		}
+		x0 := h[(k+1)*n+k]
+		alpha := complex(-norm, 0)
+		if x0 != 0 {
+			alpha *= x0 / complex(cmplx.Abs(x0), 0)
+		}
+		for i := k + 1; i < n; i++ {
+			v[i] = h[i*n+k]
+		}
+		v[k+1] -= alpha
+		vnorm := 0.0
+		for i := k + 1; i < n; i++ {
+			vnorm = math.Hypot(vnorm, cmplx.Abs(v[i]))
+		}
+		for i := k + 1; i < n; i++ {
+			v[i] /= complex(vnorm, 0)
+		}
+		// h = (I - 2*v*v^H)*h*(I - 2*v*v^H) and q = q*(I - 2*v*v^H)
+		for j := 0; j < n; j++ {
+			var dot complex128
+			for i := k + 1; i < n; i++ {
+				dot += cmplx.Conj(v[i]) * h[i*n+j]
+			}
+			for i := k + 1; i < n; i++ {
+				h[i*n+j] -= 2 * v[i] * dot
+			}
+		}
+		for _, m := range [][]complex128{h, q} {
+			for i := 0; i < n; i++ {
+				var dot complex128
+				for j := k + 1; j < n; j++ {
+					dot += m[i*n+j] * v[j]
+				}
+				for j := k + 1; j < n; j++ {
+					m[i*n+j] -= 2 * dot * cmplx.Conj(v[j])
+				}
+			}
+		}
+		for i := k + 2; i < n; i++ {
+			h[i*n+k] = 0
+		}
+	}
+}
+
+/*
+ Reduces the upper Hessenberg matrix h to upper triangular complex Schur form
+ with the single shift QR algorithm, chasing the bulge with Givens rotations
+ and accumulating them into q. Returns false if an eigenvalue does not
+ converge.
+*/
+func complexSchur(n int, h, q []complex128) bool {
+	const eps = 2.220446049250313e-16
+	iter := 0
+	for hi := n - 1; hi > 0; {
+		// look for a negligible subdiagonal element splitting off the active block
+		l := hi
+		for ; l > 0; l-- {
+			scale := cmplx.Abs(h[(l-1)*n+l-1]) + cmplx.Abs(h[l*n+l])
+			if cmplx.Abs(h[l*n+l-1]) <= eps*scale || h[l*n+l-1] == 0 {
+				h[l*n+l-1] = 0
+				break
+			}
+		}
+		if l == hi {
+			hi--
+			iter = 0
+			continue
+		}
+		iter++
+		if iter > eigenIterations*max(n, 2) {
+			return false
+		}
+		// Wilkinson shift, the eigenvalue of the trailing 2 by 2 block closer
+		// to its last diagonal element, with an exceptional shift now and then
+		a, b := h[(hi-1)*n+hi-1], h[(hi-1)*n+hi]
+		c, d := h[hi*n+hi-1], h[hi*n+hi]
+		var mu complex128
+		half := (a - d) / 2
+		disc := cmplx.Sqrt(half*half + b*c)
+		switch {
+		case iter%10 == 0:
+			mu = d + complex(cmplx.Abs(c), 0)
+		case cmplx.Abs(half-disc) > cmplx.Abs(half+disc):
+			mu = d - b*c/(half-disc)
+		case half+disc != 0:
+			mu = d - b*c/(half+disc)
+		default:
+			mu = d
+		}
+		for k := l; k < hi; k++ {
+			var x, y complex128
+			if k == l {
+				x, y = h[l*n+l]-mu, h[(l+1)*n+l]
+			} else {
+				x, y = h[k*n+k-1], h[(k+1)*n+k-1]
+			}
+			cs, sn := complexGivens(x, y)
+			// rows k and k+1 from the left, then columns k and k+1 from the right
+			for j := max(k-1, 0); j < n; j++ {
+				h1, h2 := h[k*n+j], h[(k+1)*n+j]
+				h[k*n+j] = cs*h1 + sn*h2
+				h[(k+1)*n+j] = -cmplx.Conj(sn)*h1 + cs*h2
+			}
+			if k > l {
+				h[(k+1)*n+k-1] = 0
+			}
+			rotateColumns(n, min(k+3, hi+1), k, cs, sn, h)
+			rotateColumns(n, n, k, cs, sn, q)
+		}
+	}
+	return true
+}
+
+/*
+ Applies the conjugate transpose of the rotation [c s; -conj(s) c] to columns
+ k and k+1 of the first rows rows of the n column matrix m, from the right
+*/
+func rotateColumns(n, rows, k int, c, s complex128, m []complex128) {
+	for i := 0; i < rows; i++ {
+		m1, m2 := m[i*n+k], m[i*n+k+1]
+		m[i*n+k] = m1*c + m2*cmplx.Conj(s)
+		m[i*n+k+1] = -m1*s + m2*c
+	}
+}
+
+/*
+ Returns the real c and complex s of the rotation [c s; -conj(s) c] taking
+ (x, y) to (r, 0)
+*/
+func complexGivens(x, y complex128) (c, s complex128) {
+	if y == 0 {
+		return 1, 0
+	}
+	if x == 0 {
+		return 0, 1
+	}
+	absX := cmplx.Abs(x)
+	norm := math.Hypot(absX, cmplx.Abs(y))
+	return complex(absX/norm, 0), x / complex(absX, 0) * cmplx.Conj(y) / complex(norm, 0)
+}
@@ -0,0 +1,114 @@
+package goSci
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+func randomComplex(r *rand.Rand, shape ...int) *Array[complex128] {
+	x := ZerosOf[complex128](shape...)
+	for i := range x.data {
+		x.data[i] = complex(r.NormFloat64(), r.NormFloat64())
+	}
+	return x
+}
+
+/*
+ Returns the largest of |A*v - w*v| over the eigenpairs, relative to the
+ largest magnitude in a
+*/
+func ceigResidual(a *Array[complex128], values []complex128, vectors [][]complex128) float64 {
+	n := a.shape[0]
+	norm, res := 0.0, 0.0
+	for _, val := range a.data {
+		norm = math.Max(norm, cmplx.Abs(val))
+	}
+	for k, w := range values {
+		for i := 0; i < n; i++ {
+			var sum complex128
+			for j := 0; j < n; j++ {
+				sum += a.data[i*n+j] * vectors[k][j]
+			}
+			res = math.Max(res, cmplx.Abs(sum-w*vectors[k][i]))
+		}
+	}
+	return res / math.Max(norm, 1)
+}
+
+func TestCEigResidual(t *testing.T) {
+	r := rand.New(rand.NewSource(17))
+	for _, n := range []int{1, 2, 5, 12, 30} {
+		a := randomComplex(r, n, n)
+		values, vectors, err := CEig(a)
+		if err != nil {
+			t.Fatalf("CEig of a %d by %d matrix: %v", n, n, err)
+		}
+		if res := ceigResidual(a, values, vectors); res > 1e-12*float64(n) {
+			t.Errorf("CEig of a %d by %d matrix: residual %g", n, n, res)
+		}
+	}
+}
+
+func TestCEigHard(t *testing.T) {
+	r := rand.New(rand.NewSource(18))
+	n := 12
+
+	// a Jordan block, whose eigenvalue is only found to about eps^(1/n)
+	jordan := ZerosOf[complex128](n, n)
+	for i := 0; i < n; i++ {
+		jordan.Put(1+2i, i, i)
+		if i > 0 {
+			jordan.Put(1, i-1, i)
+		}
+	}
+
+	// the cyclic shift, whose eigenvalues are the n-th roots of unity, all
+	// of the same magnitude
+	cyclic := ZerosOf[complex128](n, n)
+	for i := 0; i < n; i++ {
+		cyclic.Put(1, i, (i+1)%n)
+	}
+
+	// the companion matrix of (x - 0.5)^4, a single defective eigenvalue that
+	// takes the shifted QR iteration more than 30 sweeps
+	companion := ArrayFromSlice([]complex128{
+		2, -1.5, 0.5, -0.0625,
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+	}, 4, 4)
+
+	// a similarity transform of a diagonal with tight clusters
+	d := ZerosOf[complex128](n, n)
+	for i := 0; i < n; i++ {
+		d.Put(complex(float64(i%3), 1e-9*float64(i)), i, i)
+	}
+	s := randomComplex(r, n, n)
+	sInv, err := CSolve(s, EyeOf[complex128](n))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clustered := CMatMult(CMatMult(s, d), sInv)
+
+	for _, c := range []struct {
+		name string
+		a    *Array[complex128]
+		tol  float64
+	}{
+		{"Jordan block", jordan, 1e-12},
+		{"cyclic shift", cyclic, 1e-12},
+		{"companion matrix", companion, 1e-12},
+		{"clustered spectrum", clustered, 1e-10},
+	} {
+		values, vectors, err := CEig(c.a)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if res := ceigResidual(c.a, values, vectors); res > c.tol {
+			t.Errorf("%s: residual %g", c.name, res)
+		}
+	}
+}
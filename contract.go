@@ -0,0 +1,579 @@
+package goSci
+
+import (
+	"sort"
+	"strings"
+)
+
+/*
+ Returns the outer product of x and y flattened: element (i, j) of the m by n
+ result is x[i]*y[j]
+*/
+func Outer(x, y *GsArray) *GsArray {
+	xv, yv := x.values(), y.values()
+	result := Zeros(len(xv), len(yv))
+	if result.Size() > 0 {
+		engine.Dger(len(xv), len(yv), 1, xv, 1, yv, 1, result.data, len(yv))
+	}
+	return result
+}
+
+/*
+ Returns the Kronecker product of a and b, the block array with a block
+ a[i]*b for every element of a. The shape is the elementwise product of the
+ shapes, with the shorter one padded with leading ones.
+ e.g.
+ Kron(Eye(2), x)
+ is the block diagonal matrix with two copies of x
+*/
+func Kron(a, b *GsArray) *GsArray {
+	n := max(len(a.shape), len(b.shape))
+	aShape, bShape := padShape(a.shape, n), padShape(b.shape, n)
+	outer := Outer(a, b)
+	outer.shape = append(append([]int(nil), aShape...), bShape...)
+	// interleave the axes of a and b so that each axis of a is followed by
+	// the matching axis of b
+	perm := make([]int, 0, 2*n)
+	shape := make([]int, n)
+	for i := 0; i < n; i++ {
+		perm = append(perm, i, n+i)
+		shape[i] = aShape[i] * bShape[i]
+	}
+	return reshaped(outer.Transpose(perm...), shape)
+}
+
+/*
+ Returns the matrix product of x and y with numpy's matmul rules: arrays of
+ more than two dimensions are stacks of matrices in their last two axes,
+ with the leading batch axes broadcast, and a vector is taken as a row on
+ the left or a column on the right, its axis dropped from the result. Each
+ product runs through Gemm, and the batches are split across the worker
+ pool.
+ e.g.
+ MatMul(x, y)
+ of 10 by 3 by 4 and 4 by 5 arrays is 10 by 3 by 5
+*/
+func MatMul(x, y *GsArray) *GsArray {
+	return must(TryMatMul(x, y))
+}
+
+/*
+ Same as MatMul but returns a ShapeMismatchError instead of panicking
+*/
+func TryMatMul(x, y *GsArray) (*GsArray, error) {
+	if len(x.shape) == 0 || len(y.shape) == 0 {
+		return nil, shapeError("MatMul", x.shape, y.shape)
+	}
+	xs, ys := x, y
+	if len(x.shape) == 1 {
+		xs = expandAxis(x, 0)
+	}
+	if len(y.shape) == 1 {
+		ys = expandAxis(y, 1)
+	}
+	nx, ny := len(xs.shape), len(ys.shape)
+	m, k, n := xs.shape[nx-2], xs.shape[nx-1], ys.shape[ny-1]
+	if ys.shape[ny-2] != k {
+		return nil, shapeError("MatMul", x.shape, y.shape)
+	}
+	batch, ok := broadcastShapes(xs.shape[:nx-2], ys.shape[:ny-2])
+	if !ok {
+		return nil, shapeError("MatMul", x.shape, y.shape)
+	}
+	result := Zeros(append(append([]int(nil), batch...), m, n)...)
+	// the offsets of the matrices of x and y for each batch, in row major order
+	xStrides := broadcastStrides(xs, append(append([]int(nil), batch...), m, k))[:len(batch)]
+	yStrides := broadcastStrides(ys, append(append([]int(nil), batch...), k, n))[:len(batch)]
+	var offsets [][2]int
+	walkStrided(batch, []int{xs.offset, ys.offset}, [][]int{xStrides, yStrides}, func(pos []int) {
+		offsets = append(offsets, [2]int{pos[0], pos[1]})
+	})
+	xMat, yMat := xs.getStrides()[nx-2:], ys.getStrides()[ny-2:]
+	parallelFor(len(offsets), len(offsets)*m*n*k, func(start, end int) {
+		for b := start; b < end; b++ {
+			a := &GsArray{data: xs.data, shape: []int{m, k}, strides: xMat, offset: offsets[b][0]}
+			c := &GsArray{data: ys.data, shape: []int{k, n}, strides: yMat, offset: offsets[b][1]}
+			z := &GsArray{data: result.data[b*m*n : (b+1)*m*n], shape: []int{m, n}}
+			Gemm(1, a, c, 0, z)
+		}
+	})
+	// drop the axes added for vectors
+	shape := append([]int(nil), batch...)
+	if len(x.shape) > 1 {
+		shape = append(shape, m)
+	}
+	if len(y.shape) > 1 {
+		shape = append(shape, n)
+	}
+	result.shape = shape
+	return result, nil
+}
+
+/*
+ Returns the sum of the products of a and b over the pairs of axes axesA[i]
+ of a and axesB[i] of b, which must have the same lengths. The result has the
+ remaining axes of a followed by the remaining axes of b. The contraction is
+ a single matrix product through Gemm.
+ e.g.
+ Tensordot(a, b, []int{1, 2}, []int{0, 1})
+ of 3 by 4 by 5 and 4 by 5 by 6 arrays is 3 by 6
+*/
+func Tensordot(a, b *GsArray, axesA, axesB []int) *GsArray {
+	return must(TryTensordot(a, b, axesA, axesB))
+}
+
+/*
+ Same as Tensordot but returns a ShapeMismatchError or InvalidAxisError
+ instead of panicking
+*/
+func TryTensordot(a, b *GsArray, axesA, axesB []int) (*GsArray, error) {
+	if len(axesA) != len(axesB) {
+		return nil, shapeError("Tensordot", axesA, axesB)
+	}
+	summedA, freeA, err := contractionAxes(a, axesA)
+	if err != nil {
+		return nil, err
+	}
+	summedB, freeB, err := contractionAxes(b, axesB)
+	if err != nil {
+		return nil, err
+	}
+	k := 1
+	for i := range summedA {
+		if a.shape[summedA[i]] != b.shape[summedB[i]] {
+			return nil, shapeError("Tensordot", a.shape, b.shape)
+		}
+		k *= a.shape[summedA[i]]
+	}
+	var shape []int
+	m, n := 1, 1
+	for _, axis := range freeA {
+		shape = append(shape, a.shape[axis])
+		m *= a.shape[axis]
+	}
+	for _, axis := range freeB {
+		shape = append(shape, b.shape[axis])
+		n *= b.shape[axis]
+	}
+	am := reshaped(a.Transpose(append(append([]int(nil), freeA...), summedA...)...), []int{m, k})
+	bm := reshaped(b.Transpose(append(append([]int(nil), summedB...), freeB...)...), []int{k, n})
+	result, err := TryMatMult(am, bm)
+	if err != nil {
+		return nil, err
+	}
+	result.shape = shape
+	return result, nil
+}
+
+/*
+ Returns axes with negative axes counted from the end and the other axes of
+ x in order, or an InvalidAxisError if axes holds an axis out of range or
+ twice
+*/
+func contractionAxes(x *GsArray, axes []int) ([]int, []int, error) {
+	used := make([]bool, len(x.shape))
+	summed := make([]int, len(axes))
+	for k, axis := range axes {
+		i, err := checkAxis(axis, len(x.shape))
+		if err != nil {
+			return nil, nil, err
+		}
+		if used[i] {
+			return nil, nil, &InvalidAxisError{Axis: axis, NDim: len(x.shape)}
+		}
+		used[i], summed[k] = true, i
+	}
+	var free []int
+	for i := range used {
+		if !used[i] {
+			free = append(free, i)
+		}
+	}
+	return summed, free, nil
+}
+
+/*
+ Evaluates the Einstein summation given by subscripts over operands, as
+ numpy's einsum does. Each operand is labelled with one letter per axis,
+ separated by commas, and the output labels follow "->". The result is the
+ sum of the products of the operands over every label not in the output.
+ Without "->" the output is the labels used exactly once, in alphabetical
+ order. A label repeated within an operand takes its diagonal, and "..."
+ stands for the axes not labelled, which broadcast across operands and come
+ first in an implicit output.
+ e.g.
+ Einsum("ij,jk->ik", a, b) is MatMul(a, b)
+ Einsum("bij,bjk->bik", a, b) multiplies stacks of matrices
+ Einsum("ii", a) is the trace of a and Einsum("ii->i", a) its diagonal
+ Einsum("i,i", x, y) is Dot(x, y) and Einsum("i,j", x, y) is Outer(x, y)
+ Einsum("...ij->...ji", a) transposes the last two axes
+
+ With more than two operands the pairs are contracted greedily, cheapest
+ first, and every contraction of a pair is a batched matrix product through
+ MatMul.
+*/
+func Einsum(subscripts string, operands ...*GsArray) *GsArray {
+	return must(TryEinsum(subscripts, operands...))
+}
+
+/*
+ Same as Einsum but returns ErrInvalidSubscripts or a ShapeMismatchError
+ instead of panicking
+*/
+func TryEinsum(subscripts string, operands ...*GsArray) (*GsArray, error) {
+	inputs, output, err := parseEinsum(subscripts, operands)
+	if err != nil {
+		return nil, err
+	}
+	sizes := make(map[rune]int)
+	for i, labels := range inputs {
+		for d, label := range labels {
+			dim := operands[i].shape[d]
+			size, seen := sizes[label]
+			switch {
+			case !seen || isEllipsisLabel(label) && size == 1:
+				sizes[label] = dim
+			case dim != size && !(isEllipsisLabel(label) && dim == 1):
+				return nil, shapeError("Einsum", operands[i].shape, nil)
+			}
+		}
+	}
+	count := make(map[rune]int)
+	for _, labels := range inputs {
+		for _, label := range labels {
+			count[label]++
+		}
+	}
+	inOutput := make(map[rune]bool)
+	for _, label := range output {
+		inOutput[label] = true
+	}
+	terms := make([]einsumTerm, len(operands))
+	for i, x := range operands {
+		terms[i] = newEinsumTerm(x, inputs[i], sizes, func(label rune) bool {
+			return inOutput[label] || count[label] > countIn(inputs[i], label)
+		})
+	}
+	for len(terms) > 1 {
+		i, j := cheapestPair(terms, inOutput, sizes)
+		keep := func(label rune) bool {
+			if inOutput[label] {
+				return true
+			}
+			for k, t := range terms {
+				if k != i && k != j && t.has(label) {
+					return true
+				}
+			}
+			return false
+		}
+		terms[i] = contractTerms(terms[i], terms[j], keep, sizes)
+		terms = append(terms[:j], terms[j+1:]...)
+	}
+	t := terms[0]
+	perm := make([]int, len(output))
+	for i, label := range output {
+		perm[i] = t.index(label)
+	}
+	result := t.x.Transpose(perm...)
+	if !t.owned || result.strides != nil {
+		result = result.Copy()
+	}
+	return result, nil
+}
+
+/*
+ An operand of an Einsum with a label for each of its axes. owned is set
+ when x is a temporary that does not share the data of an operand.
+*/
+type einsumTerm struct {
+	x      *GsArray
+	labels []rune
+	owned  bool
+}
+
+func (t einsumTerm) index(label rune) int {
+	for i, l := range t.labels {
+		if l == label {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t einsumTerm) has(label rune) bool {
+	return t.index(label) >= 0
+}
+
+/*
+ Returns x as a term with one axis per distinct label: repeated labels are
+ replaced by the view of their diagonal, axes of length one broadcast against
+ a longer label are dropped and labels for which keep is false are summed
+ out
+*/
+func newEinsumTerm(x *GsArray, labels []rune, sizes map[rune]int, keep func(label rune) bool) einsumTerm {
+	strides := x.getStrides()
+	view := &GsArray{data: x.data, offset: x.offset, strides: []int{}}
+	t := einsumTerm{x: view}
+	for d, label := range labels {
+		if x.shape[d] == 1 && sizes[label] != 1 {
+			continue
+		}
+		if i := t.index(label); i >= 0 {
+			view.strides[i] += strides[d]
+			continue
+		}
+		t.labels = append(t.labels, label)
+		view.shape = append(view.shape, x.shape[d])
+		view.strides = append(view.strides, strides[d])
+	}
+	view.pack()
+	var summed []int
+	kept := t.labels[:0:0]
+	for i, label := range t.labels {
+		if keep(label) {
+			kept = append(kept, label)
+		} else {
+			summed = append(summed, i)
+		}
+	}
+	if len(summed) > 0 {
+		t = einsumTerm{x: SumAxis(view, false, summed...), labels: kept, owned: true}
+	}
+	return t
+}
+
+/*
+ Returns the indices i < j of the pair of terms that is cheapest to contract,
+ counting the multiplications it takes and then the size of its result
+*/
+func cheapestPair(terms []einsumTerm, inOutput map[rune]bool, sizes map[rune]int) (int, int) {
+	bestI, bestJ := 0, 1
+	bestCost, bestSize := -1.0, -1.0
+	for i := range terms {
+		for j := i + 1; j < len(terms); j++ {
+			cost, size := 1.0, 1.0
+			for _, label := range unionLabels(terms[i].labels, terms[j].labels) {
+				cost *= float64(sizes[label])
+				kept := inOutput[label]
+				for k, t := range terms {
+					kept = kept || k != i && k != j && t.has(label)
+				}
+				if kept {
+					size *= float64(sizes[label])
+				}
+			}
+			if bestCost < 0 || cost < bestCost || cost == bestCost && size < bestSize {
+				bestI, bestJ, bestCost, bestSize = i, j, cost, size
+			}
+		}
+	}
+	return bestI, bestJ
+}
+
+/*
+ Returns the contraction of the terms a and b, summing over the labels they
+ share for which keep is false. The shared labels that are kept become batch
+ axes, so the contraction is the batched matrix product of a, with its axes
+ ordered as batch, its own and contracted labels, and b, ordered as batch,
+ contracted and its own labels.
+*/
+func contractTerms(a, b einsumTerm, keep func(label rune) bool, sizes map[rune]int) einsumTerm {
+	var batch, contracted, freeA, freeB []rune
+	for _, label := range a.labels {
+		switch {
+		case !b.has(label):
+			freeA = append(freeA, label)
+		case keep(label):
+			batch = append(batch, label)
+		default:
+			contracted = append(contracted, label)
+		}
+	}
+	for _, label := range b.labels {
+		if !a.has(label) {
+			freeB = append(freeB, label)
+		}
+	}
+	arrange := func(t einsumTerm, groups ...[]rune) *GsArray {
+		var perm []int
+		shape := make([]int, len(groups))
+		for g, group := range groups {
+			shape[g] = 1
+			for _, label := range group {
+				perm = append(perm, t.index(label))
+				shape[g] *= sizes[label]
+			}
+		}
+		return reshaped(t.x.Transpose(perm...), shape)
+	}
+	product := MatMul(arrange(a, batch, freeA, contracted), arrange(b, batch, contracted, freeB))
+	labels := append(append(append([]rune(nil), batch...), freeA...), freeB...)
+	shape := make([]int, len(labels))
+	for i, label := range labels {
+		shape[i] = sizes[label]
+	}
+	product.shape = shape
+	return einsumTerm{x: product, labels: labels, owned: true}
+}
+
+/*
+ Splits subscripts into the labels of each operand and of the output, with
+ the axes covered by an ellipsis given labels of their own that line up from
+ the right across operands
+*/
+func parseEinsum(subscripts string, operands []*GsArray) ([][]rune, []rune, error) {
+	spec := strings.ReplaceAll(subscripts, " ", "")
+	lhs, rhs, explicit := strings.Cut(spec, "->")
+	terms := strings.Split(lhs, ",")
+	if len(terms) != len(operands) {
+		return nil, nil, ErrInvalidSubscripts
+	}
+	type parsed struct {
+		before, after []rune
+		ellipsis      bool
+	}
+	parts := make([]parsed, len(terms))
+	ellipsisDims := 0
+	for i, term := range terms {
+		before, after, ellipsis, ok := splitEllipsis(term)
+		if !ok {
+			return nil, nil, ErrInvalidSubscripts
+		}
+		extra := len(operands[i].shape) - len(before) - len(after)
+		if extra < 0 || extra > 0 && !ellipsis {
+			return nil, nil, shapeError("Einsum", operands[i].shape, nil)
+		}
+		parts[i] = parsed{before, after, ellipsis}
+		ellipsisDims = max(ellipsisDims, extra)
+	}
+	inputs := make([][]rune, len(terms))
+	for i, p := range parts {
+		extra := len(operands[i].shape) - len(p.before) - len(p.after)
+		labels := append([]rune(nil), p.before...)
+		for j := ellipsisDims - extra; j < ellipsisDims; j++ {
+			labels = append(labels, ellipsisLabel(j))
+		}
+		inputs[i] = append(labels, p.after...)
+	}
+	var broadcast []rune
+	for j := 0; j < ellipsisDims; j++ {
+		broadcast = append(broadcast, ellipsisLabel(j))
+	}
+	if !explicit {
+		count := make(map[rune]int)
+		for _, labels := range inputs {
+			for _, label := range labels {
+				count[label]++
+			}
+		}
+		var single []rune
+		for label, n := range count {
+			if n == 1 && !isEllipsisLabel(label) {
+				single = append(single, label)
+			}
+		}
+		sort.Slice(single, func(i, j int) bool { return single[i] < single[j] })
+		return inputs, append(broadcast, single...), nil
+	}
+	before, after, ellipsis, ok := splitEllipsis(rhs)
+	if !ok {
+		return nil, nil, ErrInvalidSubscripts
+	}
+	output := append([]rune(nil), before...)
+	if ellipsis {
+		output = append(output, broadcast...)
+	}
+	output = append(output, after...)
+	seen := make(map[rune]bool)
+	for _, label := range output {
+		found := false
+		for _, labels := range inputs {
+			found = found || countIn(labels, label) > 0
+		}
+		if seen[label] || !found {
+			return nil, nil, ErrInvalidSubscripts
+		}
+		seen[label] = true
+	}
+	return inputs, output, nil
+}
+
+/*
+ Returns the letters of term before and after its ellipsis, whether it has
+ one, and false if it holds anything but letters and at most one ellipsis
+*/
+func splitEllipsis(term string) (before, after []rune, ellipsis, ok bool) {
+	head, tail, ellipsis := strings.Cut(term, "...")
+	if strings.Contains(tail, "...") {
+		return nil, nil, false, false
+	}
+	for _, part := range []string{head, tail} {
+		for _, r := range part {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+				return nil, nil, false, false
+			}
+		}
+	}
+	return []rune(head), []rune(tail), ellipsis, true
+}
+
+/*
+ Labels for the axes covered by an ellipsis, numbered from the left of the
+ broadcast shape, lie past the letters so they never clash with them
+*/
+func ellipsisLabel(j int) rune {
+	return rune(0x100 + j)
+}
+
+func isEllipsisLabel(label rune) bool {
+	return label >= 0x100
+}
+
+func countIn(labels []rune, label rune) int {
+	n := 0
+	for _, l := range labels {
+		if l == label {
+			n++
+		}
+	}
+	return n
+}
+
+/*
+ Returns the labels of a followed by those of b not in a
+*/
+func unionLabels(a, b []rune) []rune {
+	union := append([]rune(nil), a...)
+	for _, label := range b {
+		if countIn(a, label) == 0 {
+			union = append(union, label)
+		}
+	}
+	return union
+}
+
+/*
+ Returns shape padded with leading ones to n dimensions
+*/
+func padShape(shape []int, n int) []int {
+	padded := make([]int, n)
+	for i := range padded {
+		padded[i] = 1
+		if j := i - (n - len(shape)); j >= 0 {
+			padded[i] = shape[j]
+		}
+	}
+	return padded
+}
+
+/*
+ Returns x with shape shape, sharing data when x is packed and copying it
+ otherwise
+*/
+func reshaped(x *GsArray, shape []int) *GsArray {
+	if x.strides != nil {
+		x = x.Copy()
+	}
+	return &GsArray{data: x.data, shape: append([]int(nil), shape...)}
+}
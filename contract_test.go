@@ -0,0 +1,126 @@
+package goSci
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+/*
+ Returns the products of the matrices x[k] and y[k] along the first axis,
+ one MatMult at a time
+*/
+func batchProduct(x, y *GsArray) *GsArray {
+	parts := make([]*GsArray, x.shape[0])
+	for k := range parts {
+		parts[k] = MatMult(x.Slice(At(k)).Copy(), y.Slice(At(k % y.shape[0])).Copy())
+	}
+	return Stack(0, parts...)
+}
+
+func TestMatMul(t *testing.T) {
+	r := rand.New(rand.NewSource(25))
+	a, b := randomArray(r, 3, 4), randomArray(r, 4, 5)
+	assertClose(t, "MatMul of matrices", MatMul(a, b), MatMult(a, b), 1e-12)
+
+	x, y := randomArray(r, 6, 3, 4), randomArray(r, 6, 4, 2)
+	assertClose(t, "batched MatMul", MatMul(x, y), batchProduct(x, y), 1e-12)
+	y1 := randomArray(r, 1, 4, 2)
+	assertClose(t, "broadcast batch", MatMul(x, y1), batchProduct(x, y1), 1e-12)
+	assertClose(t, "broadcast matrix", MatMul(x, y1.Slice(At(0))), batchProduct(x, y1), 1e-12)
+	// enough batches to run on the worker pool
+	bx, by := randomArray(r, 200, 8, 8), randomArray(r, 200, 8, 8)
+	assertClose(t, "large batch", MatMul(bx, by), batchProduct(bx, by), 1e-12)
+
+	v, w := randomArray(r, 4), randomArray(r, 3)
+	assertClose(t, "matrix times vector", MatMul(a, v), reshaped(MatMult(a, reshaped(v, []int{4, 1})), []int{3}), 1e-12)
+	assertClose(t, "vector times matrix", MatMul(w, a), reshaped(MatMult(reshaped(w, []int{1, 3}), a), []int{4}), 1e-12)
+	assertArray(t, "vector times vector", MatMul(v, v), []int{}, []float64{Dot(v, v)})
+
+	if _, err := TryMatMul(a, a); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("MatMul of 3 by 4 matrices: got %v", err)
+	}
+}
+
+func TestOuterKron(t *testing.T) {
+	x := FromSlice([]float64{1, 2}, 2)
+	y := FromSlice([]float64{1, 10, 100}, 3)
+	assertArray(t, "Outer", Outer(x, y), []int{2, 3}, []float64{1, 10, 100, 2, 20, 200})
+	assertArray(t, "Kron of vectors", Kron(x, y), []int{6}, []float64{1, 10, 100, 2, 20, 200})
+	a := FromSlice([]float64{1, 2, 3, 4}, 2, 2)
+	assertArray(t, "Kron with the identity", Kron(Eye(2), a), []int{4, 4}, []float64{
+		1, 2, 0, 0,
+		3, 4, 0, 0,
+		0, 0, 1, 2,
+		0, 0, 3, 4,
+	})
+}
+
+func TestTensordot(t *testing.T) {
+	r := rand.New(rand.NewSource(26))
+	p, q := randomArray(r, 3, 4, 5), randomArray(r, 4, 5, 6)
+	want := MatMult(reshaped(p, []int{3, 20}), reshaped(q, []int{20, 6}))
+	assertClose(t, "Tensordot", Tensordot(p, q, []int{1, 2}, []int{0, 1}), want, 1e-12)
+	assertClose(t, "Tensordot with reordered axes", Tensordot(p, q, []int{-1, 1}, []int{1, 0}), want, 1e-12)
+	if _, err := TryTensordot(p, q, []int{0}, []int{0}); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("Tensordot of axes of different lengths: got %v", err)
+	}
+}
+
+func TestEinsum(t *testing.T) {
+	r := rand.New(rand.NewSource(27))
+	a, b, c := randomArray(r, 3, 4), randomArray(r, 4, 5), randomArray(r, 5, 2)
+	ab := MatMult(a, b)
+	assertClose(t, "ij,jk->ik", Einsum("ij,jk->ik", a, b), ab, 1e-12)
+	assertClose(t, "implicit output", Einsum("ij,jk", a, b), ab, 1e-12)
+	assertClose(t, "transposed output", Einsum("ij,jk->ki", a, b), ab.T(), 1e-12)
+	assertClose(t, "transposed operand", Einsum("ji,jk->ik", a.T(), b), ab, 1e-12)
+	assertClose(t, "three operands", Einsum("ij,jk,kl->il", a, b, c), MatMult(ab, c), 1e-12)
+	assertClose(t, "transpose", Einsum("ij->ji", a), a.T(), 0)
+
+	v, w := randomArray(r, 4), randomArray(r, 3)
+	assertClose(t, "outer product", Einsum("i,j", w, v), Outer(w, v), 0)
+	assertArray(t, "inner product", Einsum("i,i", v, v), []int{}, []float64{Dot(v, v)})
+
+	s := randomArray(r, 4, 4)
+	trace := 0.0
+	for i := 0; i < 4; i++ {
+		trace += s.Get(i, i)
+	}
+	assertArray(t, "trace", Einsum("ii", s), []int{}, []float64{trace})
+	assertClose(t, "diagonal", Einsum("ii->i", s), Diagonal(s, 0), 0)
+
+	p, q := randomArray(r, 3, 4, 5), randomArray(r, 4, 5, 6)
+	assertClose(t, "Tensordot", Einsum("ijk,jkl->il", p, q), Tensordot(p, q, []int{1, 2}, []int{0, 1}), 1e-12)
+
+	x, y := randomArray(r, 6, 3, 4), randomArray(r, 6, 4, 2)
+	batch := batchProduct(x, y)
+	assertClose(t, "batch label", Einsum("bij,bjk->bik", x, y), batch, 1e-12)
+	assertClose(t, "ellipsis", Einsum("...ij,...jk->...ik", x, y), batch, 1e-12)
+	assertClose(t, "summed batch", Einsum("bij,bjk->b", x, y), SumAxis(batch, false, 1, 2), 1e-12)
+	y1 := randomArray(r, 1, 4, 2)
+	assertClose(t, "broadcast ellipsis", Einsum("...ij,...jk->...ik", x, y1), MatMul(x, y1), 1e-12)
+	assertClose(t, "implicit ellipsis", Einsum("...ij,...jk", x, y1), MatMul(x, y1), 1e-12)
+
+	// the result never shares data with the operands
+	d := Einsum("ij->ij", a)
+	d.Put(42, 0, 0)
+	if a.Get(0, 0) == 42 {
+		t.Error("Einsum of a single operand shares data with it")
+	}
+}
+
+func TestEinsumErrors(t *testing.T) {
+	a, b := Zeros(3, 4), Zeros(4, 5)
+	for _, subscripts := range []string{"ij,jk", "ij->iz", "ij->ii", "i.j", "ij->i->j"} {
+		if _, err := TryEinsum(subscripts, a); !errors.Is(err, ErrInvalidSubscripts) {
+			t.Errorf("%q: got %v", subscripts, err)
+		}
+	}
+	if _, err := TryEinsum("ij,ij", a, b); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("labels of different lengths: got %v", err)
+	}
+	if _, err := TryEinsum("ijk", a); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("too many labels: got %v", err)
+	}
+}
@@ -0,0 +1,341 @@
+package goSci
+
+import "math"
+
+/*
+ Creates a one dimensional GsArray of the values start, start + step, ...
+ stopping before stop. step may be negative or fractional.
+ e.g.
+ ArangeStep(0, 1, 0.25)
+ is [0, 0.25, 0.5, 0.75]
+*/
+func ArangeStep(start, stop, step float64) *GsArray {
+	return must(TryArangeStep(start, stop, step))
+}
+
+/*
+ Same as ArangeStep but returns ErrDomain instead of panicking when step is
+ zero or the bounds are not finite
+*/
+func TryArangeStep(start, stop, step float64) (*GsArray, error) {
+	n := math.Ceil((stop - start) / step)
+	if step == 0 || math.IsNaN(n) || math.IsInf(n, 0) {
+		return nil, ErrDomain
+	}
+	array := Zeros(max(int(n), 0))
+	for i := range array.data {
+		array.data[i] = start + float64(i)*step
+	}
+	return array, nil
+}
+
+/*
+ Creates a one dimensional GsArray of num evenly spaced values from start to
+ stop, both included
+*/
+func Linspace(start, stop float64, num int) *GsArray {
+	return must(TryLinspace(start, stop, num))
+}
+
+/*
+ Same as Linspace but returns ErrDomain instead of panicking when num is
+ negative
+*/
+func TryLinspace(start, stop float64, num int) (*GsArray, error) {
+	if num < 0 {
+		return nil, ErrDomain
+	}
+	array := Zeros(num)
+	if num == 1 {
+		array.data[0] = start
+		return array, nil
+	}
+	step := (stop - start) / float64(num-1)
+	for i := range array.data {
+		array.data[i] = start + float64(i)*step
+	}
+	if num > 1 {
+		array.data[num-1] = stop
+	}
+	return array, nil
+}
+
+/*
+ Creates a one dimensional GsArray of num values from base^start to
+ base^stop, evenly spaced on a log scale
+*/
+func Logspace(start, stop float64, num int, base float64) *GsArray {
+	return must(TryLogspace(start, stop, num, base))
+}
+
+/*
+ Same as Logspace but returns ErrDomain instead of panicking when num is
+ negative
+*/
+func TryLogspace(start, stop float64, num int, base float64) (*GsArray, error) {
+	array, err := TryLinspace(start, stop, num)
+	if err != nil {
+		return nil, err
+	}
+	for i, val := range array.data {
+		array.data[i] = math.Pow(base, val)
+	}
+	return array, nil
+}
+
+/*
+ Creates a one dimensional GsArray of num values from start to stop, both
+ included, forming a geometric progression
+ e.g.
+ Geomspace(1, 1000, 4)
+ is [1, 10, 100, 1000]
+*/
+func Geomspace(start, stop float64, num int) *GsArray {
+	return must(TryGeomspace(start, stop, num))
+}
+
+/*
+ Same as Geomspace but returns ErrDomain instead of panicking when start and
+ stop are zero or of opposite signs, or num is negative
+*/
+func TryGeomspace(start, stop float64, num int) (*GsArray, error) {
+	if start == 0 || stop == 0 || (start < 0) != (stop < 0) || num < 0 {
+		return nil, ErrDomain
+	}
+	sign := 1.0
+	if start < 0 {
+		sign, start, stop = -1, -start, -stop
+	}
+	array := Linspace(math.Log(start), math.Log(stop), num)
+	for i, val := range array.data {
+		array.data[i] = sign * math.Exp(val)
+	}
+	if num > 0 {
+		array.data[0] = sign * start
+	}
+	if num > 1 {
+		array.data[num-1] = sign * stop
+	}
+	return array, nil
+}
+
+/*
+ Creates a GsArray with shape defined by shape with every element set to val
+*/
+func Full(val float64, shape ...int) *GsArray {
+	array := Zeros(shape...)
+	for i := range array.data {
+		array.data[i] = val
+	}
+	return array
+}
+
+/*
+ Creates a GsArray with the shape of x with every element set to val
+*/
+func FullLike(x *GsArray, val float64) *GsArray {
+	return Full(val, x.Shape()...)
+}
+
+/*
+ Creates a GsArray of zeros with the shape of x
+*/
+func ZerosLike(x *GsArray) *GsArray {
+	return Zeros(x.Shape()...)
+}
+
+/*
+ Creates a GsArray of ones with the shape of x
+*/
+func OnesLike(x *GsArray) *GsArray {
+	return Ones(x.Shape()...)
+}
+
+/*
+ Builds or extracts a diagonal, like numpy's diag (the name Diag is taken by
+ the BLAS unit diagonal flag). For a vector x returns the square matrix with
+ x on diagonal k and zeros elsewhere; for a matrix x returns a copy of its
+ diagonal k. k = 0 is the main diagonal, k > 0 above it and k < 0 below it.
+*/
+func Diagonal(x *GsArray, k int) *GsArray {
+	return must(TryDiagonal(x, k))
+}
+
+/*
+ Same as Diagonal but returns a ShapeMismatchError instead of panicking when
+ x is neither a vector nor a matrix
+*/
+func TryDiagonal(x *GsArray, k int) (*GsArray, error) {
+	// the diagonal starts at row r0 and column c0
+	r0, c0 := max(-k, 0), max(k, 0)
+	switch len(x.shape) {
+	case 1:
+		vals := x.values()
+		n := len(vals) + max(k, -k)
+		result := Zeros(n, n)
+		for i, val := range vals {
+			result.data[(r0+i)*n+c0+i] = val
+		}
+		return result, nil
+	case 2:
+		rows, cols := x.shape[0], x.shape[1]
+		result := Zeros(max(min(rows-r0, cols-c0), 0))
+		strides := x.getStrides()
+		for i := range result.data {
+			result.data[i] = x.data[x.offset+(r0+i)*strides[0]+(c0+i)*strides[1]]
+		}
+		return result, nil
+	}
+	return nil, shapeError("Diagonal", x.shape, nil)
+}
+
+/*
+ Returns a copy of x with the elements above diagonal k set to zero, see
+ Diagonal for the numbering of the diagonals. Arrays of more than two
+ dimensions are treated as stacks of matrices in their last two axes.
+*/
+func Tril(x *GsArray, k int) *GsArray {
+	return must(TryTril(x, k))
+}
+
+/*
+ Same as Tril but returns a ShapeMismatchError instead of panicking when x
+ has fewer than two dimensions
+*/
+func TryTril(x *GsArray, k int) (*GsArray, error) {
+	return triangle("Tril", x, func(i, j int) bool { return j-i > k })
+}
+
+/*
+ Returns a copy of x with the elements below diagonal k set to zero, treating
+ arrays as Tril does
+*/
+func Triu(x *GsArray, k int) *GsArray {
+	return must(TryTriu(x, k))
+}
+
+/*
+ Same as Triu but returns a ShapeMismatchError instead of panicking when x
+ has fewer than two dimensions
+*/
+func TryTriu(x *GsArray, k int) (*GsArray, error) {
+	return triangle("Triu", x, func(i, j int) bool { return j-i < k })
+}
+
+/*
+ Returns a copy of x with the elements at row i and column j of each matrix
+ in the last two axes set to zero where drop(i, j)
+*/
+func triangle(name string, x *GsArray, drop func(i, j int) bool) (*GsArray, error) {
+	if len(x.shape) < 2 {
+		return nil, shapeError(name, x.shape, nil)
+	}
+	result := x.Copy()
+	rows, cols := x.shape[len(x.shape)-2], x.shape[len(x.shape)-1]
+	for i := range result.data {
+		if drop(i/cols%rows, i%cols) {
+			result.data[i] = 0
+		}
+	}
+	return result, nil
+}
+
+/*
+ Returns the Vandermonde matrix of the vector x with n columns, the powers
+ x^(n-1), ..., x^1, x^0, or x^0, ..., x^(n-1) if increasing is set
+*/
+func Vander(x *GsArray, n int, increasing bool) *GsArray {
+	return must(TryVander(x, n, increasing))
+}
+
+/*
+ Same as Vander but returns a ShapeMismatchError instead of panicking when x
+ is not a vector
+*/
+func TryVander(x *GsArray, n int, increasing bool) (*GsArray, error) {
+	if len(x.shape) != 1 || n < 0 {
+		return nil, shapeError("Vander", x.shape, []int{n})
+	}
+	vals := x.values()
+	result := Zeros(len(vals), n)
+	for i, val := range vals {
+		power := 1.0
+		for j := 0; j < n; j++ {
+			col := n - 1 - j
+			if increasing {
+				col = j
+			}
+			result.data[i*n+col] = power
+			power *= val
+		}
+	}
+	return result, nil
+}
+
+/*
+ How Meshgrid lays out its grids: XY makes the first vector vary along the
+ columns and the second along the rows, as for plotting, and IJ makes vector
+ i vary along axis i, as for matrix indexing
+*/
+type Indexing int
+
+const (
+	XY Indexing = iota
+	IJ
+)
+
+/*
+ Returns coordinate grids from coordinate vectors: grid i holds the values of
+ vectors[i] broadcast over all the axes of the grid. With IJ indexing the
+ grids have shape (len(vectors[0]), len(vectors[1]), ...); XY indexing swaps
+ the first two axes.
+ e.g.
+ g := Meshgrid(XY, Linspace(0, 1, 5), Linspace(0, 1, 3))
+ gives two 3 by 5 grids with g[0].Get(i, j) = 0.25*j and g[1].Get(i, j) = 0.5*i
+*/
+func Meshgrid(indexing Indexing, vectors ...*GsArray) []*GsArray {
+	return must(TryMeshgrid(indexing, vectors...))
+}
+
+/*
+ Same as Meshgrid but returns a ShapeMismatchError instead of panicking when
+ one of vectors is not a vector
+*/
+func TryMeshgrid(indexing Indexing, vectors ...*GsArray) ([]*GsArray, error) {
+	n := len(vectors)
+	// axes[i] is the axis of the grids along which vectors[i] varies
+	axes := make([]int, n)
+	for i := range axes {
+		axes[i] = i
+	}
+	if indexing == XY && n > 1 {
+		axes[0], axes[1] = 1, 0
+	}
+	shape := make([]int, n)
+	for i, v := range vectors {
+		if len(v.shape) != 1 {
+			return nil, shapeError("Meshgrid", v.shape, nil)
+		}
+		shape[axes[i]] = v.shape[0]
+	}
+	grids := make([]*GsArray, n)
+	for i, v := range vectors {
+		strides := make([]int, n)
+		strides[axes[i]] = v.getStrides()[0]
+		grids[i] = gatherStrided(v, shape, shape, strides)
+	}
+	return grids, nil
+}
+
+/*
+ Returns the index grids of an array of shape shape: grid i holds at each
+ position its index along axis i. It is Meshgrid with IJ indexing of the
+ vectors Arange(shape[i]).
+*/
+func Mgrid(shape ...int) []*GsArray {
+	vectors := make([]*GsArray, len(shape))
+	for i, dim := range shape {
+		vectors[i] = Arange(dim)
+	}
+	return Meshgrid(IJ, vectors...)
+}
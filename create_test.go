@@ -0,0 +1,68 @@
+package goSci
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestRanges(t *testing.T) {
+	assertArray(t, "ArangeStep", ArangeStep(0, 1, 0.25), []int{4}, []float64{0, 0.25, 0.5, 0.75})
+	assertArray(t, "ArangeStep down", ArangeStep(5, 0, -2), []int{3}, []float64{5, 3, 1})
+	assertArray(t, "empty ArangeStep", ArangeStep(1, 0, 1), []int{0}, nil)
+	assertArray(t, "Linspace", Linspace(0, 1, 5), []int{5}, []float64{0, 0.25, 0.5, 0.75, 1})
+	assertArray(t, "Linspace of one", Linspace(3, 4, 1), []int{1}, []float64{3})
+	assertArray(t, "Linspace of none", Linspace(0, 1, 0), []int{0}, nil)
+	assertClose(t, "Logspace", Logspace(0, 3, 4, 10), FromSlice([]float64{1, 10, 100, 1000}, 4), 1e-12)
+	g := Geomspace(-1, -1000, 4)
+	assertClose(t, "Geomspace", g, FromSlice([]float64{-1, -10, -100, -1000}, 4), 1e-12)
+	if g.Get(3) != -1000 {
+		t.Errorf("Geomspace does not end exactly at stop: %v", g.Get(3))
+	}
+
+	for name, err := range map[string]error{
+		"ArangeStep with a zero step": second(TryArangeStep(0, 1, 0)),
+		"ArangeStep to infinity":      second(TryArangeStep(0, math.Inf(1), 1)),
+		"Linspace of -1":              second(TryLinspace(0, 1, -1)),
+		"Logspace of -1":              second(TryLogspace(0, 1, -1, 10)),
+		"Geomspace of -1":             second(TryGeomspace(1, 10, -1)),
+		"Geomspace across zero":       second(TryGeomspace(-1, 1, 3)),
+		"Geomspace from zero":         second(TryGeomspace(0, 1, 3)),
+	} {
+		if !errors.Is(err, ErrDomain) {
+			t.Errorf("%s: got %v", name, err)
+		}
+	}
+}
+
+func TestTriangles(t *testing.T) {
+	m := Arange(12)
+	m.Reshape(3, 4)
+	assertArray(t, "Diagonal", Diagonal(m, 1), []int{3}, []float64{1, 6, 11})
+	assertArray(t, "Diagonal below", Diagonal(m, -2), []int{1}, []float64{8})
+	assertArray(t, "Diagonal of a transpose", Diagonal(m.T(), -1), []int{3}, []float64{1, 6, 11})
+	assertArray(t, "Diagonal matrix", Diagonal(FromSlice([]float64{1, 2}, 2), -1), []int{3, 3},
+		[]float64{0, 0, 0, 1, 0, 0, 0, 2, 0})
+	assertArray(t, "Tril", Tril(m, 0), []int{3, 4}, []float64{0, 0, 0, 0, 4, 5, 0, 0, 8, 9, 10, 0})
+	assertArray(t, "Triu", Triu(m, 1), []int{3, 4}, []float64{0, 1, 2, 3, 0, 0, 6, 7, 0, 0, 0, 11})
+
+	// the last two axes of a stack of matrices
+	b := Arange(8)
+	b.Reshape(2, 2, 2)
+	assertArray(t, "Triu of a stack", Triu(b, 0), []int{2, 2, 2}, []float64{0, 1, 0, 3, 4, 5, 0, 7})
+}
+
+func TestGrids(t *testing.T) {
+	assertArray(t, "Vander", Vander(FromSlice([]float64{2, 3}, 2), 3, false), []int{2, 3},
+		[]float64{4, 2, 1, 9, 3, 1})
+	assertArray(t, "increasing Vander", Vander(FromSlice([]float64{2}, 1), 3, true), []int{1, 3},
+		[]float64{1, 2, 4})
+
+	xy := Meshgrid(XY, Arange(3), Arange(2))
+	assertArray(t, "Meshgrid XY x", xy[0], []int{2, 3}, []float64{0, 1, 2, 0, 1, 2})
+	assertArray(t, "Meshgrid XY y", xy[1], []int{2, 3}, []float64{0, 0, 0, 1, 1, 1})
+	ij := Meshgrid(IJ, Arange(2), Arange(3))
+	assertArray(t, "Meshgrid IJ", ij[1], []int{2, 3}, []float64{0, 1, 2, 0, 1, 2})
+	mg := Mgrid(2, 3)
+	assertClose(t, "Mgrid", mg[0], ij[0], 0)
+}
@@ -0,0 +1,264 @@
+package goSci
+
+import "math"
+
+/*
+ A distance between two observations of the same length, as used by Pdist
+ and Cdist
+*/
+type Metric interface {
+	Distance(u, v []float64) float64
+}
+
+/*
+ The Euclidean distance, sqrt(sum((u - v)^2))
+*/
+type Euclidean struct{}
+
+func (Euclidean) Distance(u, v []float64) float64 {
+	sum := 0.0
+	for i := range u {
+		d := u[i] - v[i]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+/*
+ The Manhattan or city block distance, sum(|u - v|)
+*/
+type Manhattan struct{}
+
+func (Manhattan) Distance(u, v []float64) float64 {
+	sum := 0.0
+	for i := range u {
+		sum += math.Abs(u[i] - v[i])
+	}
+	return sum
+}
+
+/*
+ The Chebyshev distance, max(|u - v|)
+*/
+type Chebyshev struct{}
+
+func (Chebyshev) Distance(u, v []float64) float64 {
+	dist := 0.0
+	for i := range u {
+		dist = math.Max(dist, math.Abs(u[i]-v[i]))
+	}
+	return dist
+}
+
+/*
+ The cosine distance, 1 - u.v/(|u||v|). It is NaN if either vector is zero.
+*/
+type Cosine struct{}
+
+func (Cosine) Distance(u, v []float64) float64 {
+	var uv, uu, vv float64
+	for i := range u {
+		uv += u[i] * v[i]
+		uu += u[i] * u[i]
+		vv += v[i] * v[i]
+	}
+	return 1 - uv/math.Sqrt(uu*vv)
+}
+
+/*
+ The Mahalanobis distance, sqrt((u - v)^T * VI * (u - v)) for the inverse
+ covariance matrix VI
+*/
+type Mahalanobis struct {
+	vi []float64
+	n  int
+}
+
+/*
+ Returns the Mahalanobis metric for the square inverse covariance matrix vi
+*/
+func NewMahalanobis(vi *GsArray) (*Mahalanobis, error) {
+	n, err := squareSize("NewMahalanobis", vi)
+	if err != nil {
+		return nil, err
+	}
+	return &Mahalanobis{vi: vi.Copy().data, n: n}, nil
+}
+
+/*
+ Returns the Mahalanobis metric for the observations in the rows of x, using
+ the inverse of their sample covariance. Returns ErrSingular if the
+ covariance is singular, as it is with fewer observations than variables.
+*/
+func MahalanobisFromData(x *GsArray) (*Mahalanobis, error) {
+	if len(x.shape) != 2 || x.shape[0] < 2 {
+		return nil, shapeError("MahalanobisFromData", x.shape, nil)
+	}
+	rows, cols := x.shape[0], x.shape[1]
+	centered := x.Copy()
+	for j := 0; j < cols; j++ {
+		mean := 0.0
+		for i := 0; i < rows; i++ {
+			mean += centered.data[i*cols+j]
+		}
+		mean /= float64(rows)
+		for i := 0; i < rows; i++ {
+			centered.data[i*cols+j] -= mean
+		}
+	}
+	cov := Zeros(cols, cols)
+	engine.Dgemm(true, false, cols, cols, rows, 1/float64(rows-1), centered.data, cols, centered.data, cols, 0, cov.data, cols)
+	vi, err := Inv(cov)
+	if err != nil {
+		return nil, err
+	}
+	return &Mahalanobis{vi: vi.data, n: cols}, nil
+}
+
+/*
+ Panics with a ShapeMismatchError if u and v do not match the size of VI
+*/
+func (m *Mahalanobis) Distance(u, v []float64) float64 {
+	if len(u) != m.n || len(v) != m.n {
+		panic(shapeError("Distance", []int{m.n, m.n}, []int{len(u)}))
+	}
+	diff := make([]float64, m.n)
+	for i := range diff {
+		diff[i] = u[i] - v[i]
+	}
+	sum := 0.0
+	for i := 0; i < m.n; i++ {
+		sum += diff[i] * engine.Ddot(m.n, m.vi[i*m.n:], 1, diff, 1)
+	}
+	// rounding can leave a tiny negative sum for nearly equal observations
+	return math.Sqrt(math.Max(sum, 0))
+}
+
+func (m *Mahalanobis) dims() int {
+	return m.n
+}
+
+/*
+ Returns the distances between all pairs of the observations in the rows of
+ x in condensed form: a vector holding the upper triangle of the distance
+ matrix row by row, d(0, 1), d(0, 2), ..., d(1, 2), .... Use SquareForm for
+ the full matrix.
+*/
+func Pdist(x *GsArray, metric Metric) *GsArray {
+	return must(TryPdist(x, metric))
+}
+
+/*
+ Same as Pdist but returns a ShapeMismatchError instead of panicking
+*/
+func TryPdist(x *GsArray, metric Metric) (*GsArray, error) {
+	if err := checkObservations("Pdist", x, metric); err != nil {
+		return nil, err
+	}
+	rows, cols := x.shape[0], x.shape[1]
+	vals := x.values()
+	result := Zeros(rows * (rows - 1) / 2)
+	k := 0
+	for i := 0; i < rows; i++ {
+		for j := i + 1; j < rows; j++ {
+			result.data[k] = metric.Distance(vals[i*cols:(i+1)*cols], vals[j*cols:(j+1)*cols])
+			k++
+		}
+	}
+	return result, nil
+}
+
+/*
+ Returns the matrix of distances between the observations in the rows of xa
+ and those in the rows of xb, element i, j holding the distance between row i
+ of xa and row j of xb
+*/
+func Cdist(xa, xb *GsArray, metric Metric) *GsArray {
+	return must(TryCdist(xa, xb, metric))
+}
+
+/*
+ Same as Cdist but returns a ShapeMismatchError instead of panicking
+*/
+func TryCdist(xa, xb *GsArray, metric Metric) (*GsArray, error) {
+	if err := checkObservations("Cdist", xa, metric); err != nil {
+		return nil, err
+	}
+	if err := checkObservations("Cdist", xb, metric); err != nil {
+		return nil, err
+	}
+	if xa.shape[1] != xb.shape[1] {
+		return nil, shapeError("Cdist", xa.shape, xb.shape)
+	}
+	ra, rb, cols := xa.shape[0], xb.shape[0], xa.shape[1]
+	va, vb := xa.values(), xb.values()
+	result := Zeros(ra, rb)
+	for i := 0; i < ra; i++ {
+		for j := 0; j < rb; j++ {
+			result.data[i*rb+j] = metric.Distance(va[i*cols:(i+1)*cols], vb[j*cols:(j+1)*cols])
+		}
+	}
+	return result, nil
+}
+
+/*
+ Returns a ShapeMismatchError naming op unless x is a matrix of observations
+ whose length suits metric
+*/
+func checkObservations(op string, x *GsArray, metric Metric) error {
+	if len(x.shape) != 2 {
+		return shapeError(op, x.shape, nil)
+	}
+	if m, ok := metric.(interface{ dims() int }); ok && m.dims() != x.shape[1] {
+		return shapeError(op, x.shape, []int{m.dims(), m.dims()})
+	}
+	return nil
+}
+
+/*
+ Converts between the condensed and square forms of a distance matrix: a
+ condensed vector as returned by Pdist gives the symmetric matrix with a zero
+ diagonal, and a square matrix gives the condensed vector of its upper
+ triangle.
+*/
+func SquareForm(d *GsArray) *GsArray {
+	return must(TrySquareForm(d))
+}
+
+/*
+ Same as SquareForm but returns a ShapeMismatchError instead of panicking when
+ d is neither a square matrix nor of a condensed length n*(n-1)/2
+*/
+func TrySquareForm(d *GsArray) (*GsArray, error) {
+	vals := d.values()
+	switch len(d.shape) {
+	case 1:
+		// solve n*(n-1)/2 = len for n
+		n := int(math.Round((1 + math.Sqrt(1+8*float64(len(vals)))) / 2))
+		if n*(n-1)/2 != len(vals) {
+			return nil, shapeError("SquareForm", d.shape, nil)
+		}
+		result := Zeros(n, n)
+		k := 0
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				result.data[i*n+j] = vals[k]
+				result.data[j*n+i] = vals[k]
+				k++
+			}
+		}
+		return result, nil
+	case 2:
+		n, err := squareSize("SquareForm", d)
+		if err != nil {
+			return nil, err
+		}
+		result := Zeros(n * (n - 1) / 2)
+		k := 0
+		for i := 0; i < n; i++ {
+			k += copy(result.data[k:], vals[i*n+i+1:(i+1)*n])
+		}
+		return result, nil
+	}
+	return nil, shapeError("SquareForm", d.shape, nil)
+}
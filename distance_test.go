@@ -0,0 +1,98 @@
+package goSci
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPdist(t *testing.T) {
+	x := FromSlice([]float64{0, 0, 3, 4, 1, 1}, 3, 2)
+	cases := []struct {
+		name   string
+		metric Metric
+		want   []float64
+	}{
+		{"Euclidean", Euclidean{}, []float64{5, math.Sqrt2, math.Sqrt(13)}},
+		{"Manhattan", Manhattan{}, []float64{7, 2, 5}},
+		{"Chebyshev", Chebyshev{}, []float64{4, 1, 3}},
+		{"Mahalanobis", must(NewMahalanobis(FromSlice([]float64{1, 0, 0, 4}, 2, 2))), []float64{math.Sqrt(73), math.Sqrt(5), math.Sqrt(40)}},
+	}
+	for _, c := range cases {
+		assertClose(t, c.name, Pdist(x, c.metric), FromSlice(c.want, 3), 1e-14)
+		// the rows of a transposed view are its observations
+		assertClose(t, c.name+" of a transpose", Pdist(x.T().Copy().T(), c.metric), FromSlice(c.want, 3), 1e-14)
+	}
+
+	y := FromSlice([]float64{1, 0, 0, 1, 1, 1, -2, 0}, 4, 2)
+	assertClose(t, "Cosine", Pdist(y, Cosine{}), FromSlice([]float64{1, 1 - 1/math.Sqrt2, 2, 1 - 1/math.Sqrt2, 1, 1 + 1/math.Sqrt2}, 6), 1e-14)
+	if d := Pdist(x, Cosine{}).Data(); !math.IsNaN(d[0]) {
+		t.Errorf("Cosine with a zero observation: got %v, want NaN", d[0])
+	}
+	assertArray(t, "Pdist of one observation", Pdist(Zeros(1, 3), Euclidean{}), []int{0}, []float64{})
+}
+
+func TestCdist(t *testing.T) {
+	xa := FromSlice([]float64{0, 0, 3, 4, 1, 1}, 3, 2)
+	xb := FromSlice([]float64{0, 0, 3, 0}, 2, 2)
+	assertClose(t, "Euclidean", Cdist(xa, xb, Euclidean{}), FromSlice([]float64{0, 3, 5, 4, math.Sqrt2, math.Sqrt(5)}, 3, 2), 1e-14)
+	assertClose(t, "Manhattan", Cdist(xb, xa, Manhattan{}), FromSlice([]float64{0, 7, 2, 3, 4, 3}, 2, 3), 0)
+
+	// a non-diagonal inverse covariance, (1, -1) [2 1; 1 2] (1, -1)^T = 2
+	m := must(NewMahalanobis(FromSlice([]float64{2, 1, 1, 2}, 2, 2)))
+	assertClose(t, "Mahalanobis", Cdist(FromSlice([]float64{1, 0}, 1, 2), FromSlice([]float64{0, 1}, 1, 2), m), FromSlice([]float64{math.Sqrt2}, 1, 1), 1e-14)
+
+	// the corners of a square have covariance 4/3 times the identity
+	corners := FromSlice([]float64{0, 0, 2, 0, 0, 2, 2, 2}, 4, 2)
+	m, err := MahalanobisFromData(corners)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "MahalanobisFromData", Pdist(corners, m), FromSlice([]float64{math.Sqrt(3), math.Sqrt(3), math.Sqrt(6), math.Sqrt(6), math.Sqrt(3), math.Sqrt(3)}, 6), 1e-14)
+}
+
+func TestSquareForm(t *testing.T) {
+	d := FromSlice([]float64{1, 2, 3, 4, 5, 6}, 6)
+	square := SquareForm(d)
+	assertArray(t, "SquareForm", square, []int{4, 4}, []float64{
+		0, 1, 2, 3,
+		1, 0, 4, 5,
+		2, 4, 0, 6,
+		3, 5, 6, 0,
+	})
+	assertArray(t, "SquareForm of a square", SquareForm(square), []int{6}, d.Data())
+	assertArray(t, "SquareForm of no distances", SquareForm(Zeros(0)), []int{1, 1}, []float64{0})
+
+	x := FromSlice([]float64{0, 0, 3, 4, 1, 1, 5, 2}, 4, 2)
+	assertClose(t, "SquareForm of Pdist", SquareForm(Pdist(x, Euclidean{})), Cdist(x, x, Euclidean{}), 1e-14)
+}
+
+func TestDistanceErrors(t *testing.T) {
+	m := must(NewMahalanobis(Eye(3)))
+	for name, err := range map[string]error{
+		"NewMahalanobis":              second(NewMahalanobis(Zeros(2, 3))),
+		"MahalanobisFromData":         second(MahalanobisFromData(Zeros(1, 3))),
+		"Pdist of a vector":           second(TryPdist(Zeros(3), Euclidean{})),
+		"Pdist with the wrong length": second(TryPdist(Zeros(4, 2), m)),
+		"Cdist":                       second(TryCdist(Zeros(2, 3), Zeros(2, 2), Euclidean{})),
+		"Cdist with the wrong length": second(TryCdist(Zeros(2, 2), Zeros(2, 2), m)),
+		"SquareForm of a length 4":    second(TrySquareForm(Zeros(4))),
+		"SquareForm of a rectangle":   second(TrySquareForm(Zeros(2, 3))),
+		"SquareForm of rank 3":        second(TrySquareForm(Zeros(2, 2, 2))),
+	} {
+		if !errors.As(err, new(*ShapeMismatchError)) {
+			t.Errorf("%s: got %v, want a ShapeMismatchError", name, err)
+		}
+	}
+	// the covariance of two points in the plane is singular
+	if _, err := MahalanobisFromData(FromSlice([]float64{0, 0, 1, 1}, 2, 2)); !errors.Is(err, ErrSingular) {
+		t.Errorf("MahalanobisFromData with a singular covariance: got %v", err)
+	}
+
+	defer func() {
+		if _, ok := recover().(*ShapeMismatchError); !ok {
+			t.Error("Distance with the wrong length did not panic with a ShapeMismatchError")
+		}
+	}()
+	m.Distance([]float64{1, 2}, []float64{3, 4})
+}
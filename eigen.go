@@ -0,0 +1,701 @@
+package goSci
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+/*
+ Largest number of QR iterations spent on a single eigenvalue before Eigh, Eig
+ and CEig give up with ErrNoConvergence
+*/
+const eigenIterations = 30
+
+/*
+ Returns the eigenvalues w, in increasing order, and the eigenvectors v of the
+ symmetric matrix a, a*v = v*diag(w). Column j of v is the unit eigenvector
+ belonging to w[j] and v is orthogonal. Only the lower triangle of a is used.
+ The matrix is reduced to tridiagonal form with Householder reflections and
+ then diagonalized with the implicit QL algorithm.
+*/
+func Eigh(a *GsArray) (w, v *GsArray, err error) {
+	if len(a.shape) != 2 || a.shape[0] != a.shape[1] {
+		return nil, nil, shapeError("Eigh", a.shape, nil)
+	}
+	n := a.shape[0]
+	v = a.Copy()
+	w = Zeros(n)
+	if n == 0 {
+		return w, v, nil
+	}
+	e := make([]float64, n)
+	tridiagonalize(n, v.data, w.data, e)
+	if !tridiagonalQL(n, w.data, e, v.data) {
+		return nil, nil, ErrNoConvergence
+	}
+	return w, v, nil
+}
+
+/*
+ Reduces the symmetric matrix in the lower triangle of the n by n matrix v to
+ tridiagonal form, leaving the diagonal in d, the subdiagonal in e[1:] and the
+ accumulated orthogonal transformation in v. Follows tred2 from EISPACK.
+*/
+func tridiagonalize(n int, v, d, e []float64) {
+	copy(d, v[(n-1)*n:])
+	for i := n - 1; i > 0; i-- {
+		scale, h := 0.0, 0.0
+		for k := 0; k < i; k++ {
+			scale += math.Abs(d[k])
+		}
+		if scale == 0 {
+			e[i] = d[i-1]
+			for j := 0; j < i; j++ {
+				d[j] = v[(i-1)*n+j]
+				v[i*n+j] = 0
+				v[j*n+i] = 0
+			}
+			d[i] = h
+			continue
+		}
+		// generate the Householder vector
+		for k := 0; k < i; k++ {
+			d[k] /= scale
+			h += d[k] * d[k]
+		}
+		f := d[i-1]
+		g := math.Sqrt(h)
+		if f > 0 {
+			g = -g
+		}
+		e[i] = scale * g
+		h -= f * g
+		d[i-1] = f - g
+		for j := 0; j < i; j++ {
+			e[j] = 0
+		}
+		// apply the similarity transformation to the remaining columns
+		for j := 0; j < i; j++ {
+			f = d[j]
+			v[j*n+i] = f
+			g = e[j] + v[j*n+j]*f
+			for k := j + 1; k < i; k++ {
+				g += v[k*n+j] * d[k]
+				e[k] += v[k*n+j] * f
+			}
+			e[j] = g
+		}
+		f = 0
+		for j := 0; j < i; j++ {
+			e[j] /= h
+			f += e[j] * d[j]
+		}
+		hh := f / (h + h)
+		for j := 0; j < i; j++ {
+			e[j] -= hh * d[j]
+		}
+		for j := 0; j < i; j++ {
+			f, g = d[j], e[j]
+			for k := j; k < i; k++ {
+				v[k*n+j] -= f*e[k] + g*d[k]
+			}
+			d[j] = v[(i-1)*n+j]
+			v[i*n+j] = 0
+		}
+		d[i] = h
+	}
+	// accumulate the transformations
+	for i := 0; i < n-1; i++ {
+		v[(n-1)*n+i] = v[i*n+i]
+		v[i*n+i] = 1
+		h := d[i+1]
+		if h != 0 {
+			for k := 0; k <= i; k++ {
+				d[k] = v[k*n+i+1] / h
+			}
+			for j := 0; j <= i; j++ {
+				g := 0.0
+				for k := 0; k <= i; k++ {
+					g += v[k*n+i+1] * v[k*n+j]
+				}
+				for k := 0; k <= i; k++ {
+					v[k*n+j] -= g * d[k]
+				}
+			}
+		}
+		for k := 0; k <= i; k++ {
+			v[k*n+i+1] = 0
+		}
+	}
+	for j := 0; j < n; j++ {
+		d[j] = v[(n-1)*n+j]
+		v[(n-1)*n+j] = 0
+	}
+	v[(n-1)*n+n-1] = 1
+	e[0] = 0
+}
+
+/*
+ Diagonalizes the symmetric tridiagonal matrix with diagonal d and subdiagonal
+ e[1:] by the implicit QL algorithm, accumulating the rotations into the
+ columns of v and sorting the eigenvalues in d into increasing order. Follows
+ tql2 from EISPACK. Returns false if an eigenvalue does not converge.
+*/
+func tridiagonalQL(n int, d, e, v []float64) bool {
+	const eps = 2.220446049250313e-16
+	copy(e, e[1:])
+	e[n-1] = 0
+	f, tst1 := 0.0, 0.0
+	for l := 0; l < n; l++ {
+		// find a small subdiagonal element
+		tst1 = math.Max(tst1, math.Abs(d[l])+math.Abs(e[l]))
+		m := l
+		for m < n-1 && math.Abs(e[m]) > eps*tst1 {
+			m++
+		}
+		for iter := 0; m > l; iter++ {
+			if iter == eigenIterations {
+				return false
+			}
+			// compute the implicit shift
+			g := d[l]
+			p := (d[l+1] - g) / (2 * e[l])
+			r := math.Copysign(math.Hypot(p, 1), p)
+			d[l] = e[l] / (p + r)
+			d[l+1] = e[l] * (p + r)
+			dl1 := d[l+1]
+			h := g - d[l]
+			for i := l + 2; i < n; i++ {
+				d[i] -= h
+			}
+			f += h
+			// implicit QL transformation
+			p = d[m]
+			c, c2, c3 := 1.0, 1.0, 1.0
+			el1 := e[l+1]
+			s, s2 := 0.0, 0.0
+			for i := m - 1; i >= l; i-- {
+				c3, c2, s2 = c2, c, s
+				g = c * e[i]
+				h = c * p
+				r = math.Hypot(p, e[i])
+				e[i+1] = s * r
+				s = e[i] / r
+				c = p / r
+				p = c*d[i] - s*g
+				d[i+1] = h + s*(c*g+s*d[i])
+				for k := 0; k < n; k++ {
+					h = v[k*n+i+1]
+					v[k*n+i+1] = s*v[k*n+i] + c*h
+					v[k*n+i] = c*v[k*n+i] - s*h
+				}
+			}
+			p = -s * s2 * c3 * el1 * e[l] / dl1
+			e[l] = s * p
+			d[l] = c * p
+			if math.Abs(e[l]) <= eps*tst1 {
+				break
+			}
+		}
+		d[l] += f
+		e[l] = 0
+	}
+	// selection sort keeps the columns of v in step with d
+	for i := 0; i < n-1; i++ {
+		k := i
+		for j := i + 1; j < n; j++ {
+			if d[j] < d[k] {
+				k = j
+			}
+		}
+		if k != i {
+			d[i], d[k] = d[k], d[i]
+			swapCols(v, n, n, i, k)
+		}
+	}
+	return true
+}
+
+/*
+ Returns the eigenvalues and eigenvectors of the square matrix a, which need
+ not be symmetric, so both may be complex. vectors[j] is the eigenvector
+ belonging to values[j], scaled to unit 2-norm, and complex eigenvalues come
+ in conjugate pairs with the positive imaginary part first. The matrix is
+ reduced to Hessenberg form and then to real Schur form with the shifted QR
+ algorithm. Use Eigh for symmetric matrices.
+*/
+func Eig(a *GsArray) (values []complex128, vectors [][]complex128, err error) {
+	if len(a.shape) != 2 || a.shape[0] != a.shape[1] {
+		return nil, nil, shapeError("Eig", a.shape, nil)
+	}
+	n := a.shape[0]
+	h := a.Copy().data
+	v := make([]float64, n*n)
+	d := make([]float64, n)
+	e := make([]float64, n)
+	hessenberg(n, h, v)
+	if !schur(n, h, v, d, e) {
+		return nil, nil, ErrNoConvergence
+	}
+	values = make([]complex128, n)
+	vectors = make([][]complex128, n)
+	for j := 0; j < n; j++ {
+		values[j] = complex(d[j], e[j])
+		vec := make([]complex128, n)
+		switch {
+		case e[j] == 0:
+			for i := range vec {
+				vec[i] = complex(v[i*n+j], 0)
+			}
+		case e[j] > 0:
+			// the pair is stored as the real and imaginary parts in columns j and j+1
+			for i := range vec {
+				vec[i] = complex(v[i*n+j], v[i*n+j+1])
+			}
+		default:
+			for i := range vec {
+				vec[i] = cmplx.Conj(vectors[j-1][i])
+			}
+		}
+		if e[j] >= 0 {
+			norm := 0.0
+			for _, val := range vec {
+				norm = math.Hypot(norm, cmplx.Abs(val))
+			}
+			if norm != 0 {
+				for i := range vec {
+					vec[i] /= complex(norm, 0)
+				}
+			}
+		}
+		vectors[j] = vec
+	}
+	return values, vectors, nil
+}
+
+/*
+ Reduces the n by n matrix h to upper Hessenberg form by orthogonal
+ similarity transformations, accumulated into v. Follows orthes from EISPACK.
+*/
+func hessenberg(n int, h, v []float64) {
+	ort := make([]float64, n)
+	high := n - 1
+	for m := 1; m < high; m++ {
+		scale := 0.0
+		for i := m; i <= high; i++ {
+			scale += math.Abs(h[i*n+m-1])
+		}
+		if scale == 0 {
+			continue
+		}
+		// compute the Householder transformation
+		hh := 0.0
+		for i := high; i >= m; i-- {
+			ort[i] = h[i*n+m-1] / scale
+			hh += ort[i] * ort[i]
+		}
+		g := math.Sqrt(hh)
+		if ort[m] > 0 {
+			g = -g
+		}
+		hh -= ort[m] * g
+		ort[m] -= g
+		// apply it as H = (I - u*u^T/hh) * H * (I - u*u^T/hh)
+		for j := m; j < n; j++ {
+			f := 0.0
+			for i := high; i >= m; i-- {
+				f += ort[i] * h[i*n+j]
+			}
+			f /= hh
+			for i := m; i <= high; i++ {
+				h[i*n+j] -= f * ort[i]
+			}
+		}
+		for i := 0; i <= high; i++ {
+			f := 0.0
+			for j := high; j >= m; j-- {
+				f += ort[j] * h[i*n+j]
+			}
+			f /= hh
+			for j := m; j <= high; j++ {
+				h[i*n+j] -= f * ort[j]
+			}
+		}
+		ort[m] *= scale
+		h[m*n+m-1] = scale * g
+	}
+	// accumulate the transformations
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			v[i*n+j] = 0
+		}
+		v[i*n+i] = 1
+	}
+	for m := high - 1; m >= 1; m-- {
+		if h[m*n+m-1] == 0 {
+			continue
+		}
+		for i := m + 1; i <= high; i++ {
+			ort[i] = h[i*n+m-1]
+		}
+		for j := m; j <= high; j++ {
+			g := 0.0
+			for i := m; i <= high; i++ {
+				g += ort[i] * v[i*n+j]
+			}
+			// double division avoids possible underflow
+			g = (g / ort[m]) / h[m*n+m-1]
+			for i := m; i <= high; i++ {
+				v[i*n+j] += g * ort[i]
+			}
+		}
+	}
+}
+
+/*
+ Reduces the upper Hessenberg matrix h to real Schur form with the shifted
+ double QR algorithm and finds the eigenvectors by back substitution. On
+ return d and e hold the real and imaginary parts of the eigenvalues and the
+ columns of v the eigenvectors, with a complex pair stored as its real and
+ imaginary parts in two adjacent columns. Follows hqr2 from EISPACK. Returns
+ false if an eigenvalue does not converge.
+*/
+func schur(nn int, h, v, d, e []float64) bool {
+	const eps = 2.220446049250313e-16
+	n := nn - 1
+	exshift := 0.0
+	var p, q, r, s, z, t, w, x, y float64
+
+	norm := 0.0
+	for i := 0; i < nn; i++ {
+		for j := max(i-1, 0); j < nn; j++ {
+			norm += math.Abs(h[i*nn+j])
+		}
+	}
+
+	iter := 0
+	for n >= 0 {
+		// look for a single small subdiagonal element
+		l := n
+		for l > 0 {
+			s = math.Abs(h[(l-1)*nn+l-1]) + math.Abs(h[l*nn+l])
+			if s == 0 {
+				s = norm
+			}
+			if math.Abs(h[l*nn+l-1]) <= eps*s {
+				break
+			}
+			l--
+		}
+
+		switch {
+		case l == n:
+			// one root found
+			h[n*nn+n] += exshift
+			d[n] = h[n*nn+n]
+			e[n] = 0
+			n--
+			iter = 0
+		case l == n-1:
+			// two roots found
+			w = h[n*nn+n-1] * h[(n-1)*nn+n]
+			p = (h[(n-1)*nn+n-1] - h[n*nn+n]) / 2
+			q = p*p + w
+			z = math.Sqrt(math.Abs(q))
+			h[n*nn+n] += exshift
+			h[(n-1)*nn+n-1] += exshift
+			x = h[n*nn+n]
+			if q >= 0 {
+				// real pair
+				if p >= 0 {
+					z = p + z
+				} else {
+					z = p - z
+				}
+				d[n-1] = x + z
+				d[n] = d[n-1]
+				if z != 0 {
+					d[n] = x - w/z
+				}
+				e[n-1], e[n] = 0, 0
+				x = h[n*nn+n-1]
+				s = math.Abs(x) + math.Abs(z)
+				p, q = x/s, z/s
+				r = math.Hypot(p, q)
+				p /= r
+				q /= r
+				for j := n - 1; j < nn; j++ {
+					z = h[(n-1)*nn+j]
+					h[(n-1)*nn+j] = q*z + p*h[n*nn+j]
+					h[n*nn+j] = q*h[n*nn+j] - p*z
+				}
+				for i := 0; i <= n; i++ {
+					z = h[i*nn+n-1]
+					h[i*nn+n-1] = q*z + p*h[i*nn+n]
+					h[i*nn+n] = q*h[i*nn+n] - p*z
+				}
+				for i := 0; i < nn; i++ {
+					z = v[i*nn+n-1]
+					v[i*nn+n-1] = q*z + p*v[i*nn+n]
+					v[i*nn+n] = q*v[i*nn+n] - p*z
+				}
+			} else {
+				// complex pair
+				d[n-1], d[n] = x+p, x+p
+				e[n-1], e[n] = z, -z
+			}
+			n -= 2
+			iter = 0
+		default:
+			// form the shift
+			x = h[n*nn+n]
+			y, w = 0, 0
+			if l < n {
+				y = h[(n-1)*nn+n-1]
+				w = h[n*nn+n-1] * h[(n-1)*nn+n]
+			}
+			// exceptional shifts break cycles the standard shift can fall into
+			if iter == 10 {
+				exshift += x
+				for i := 0; i <= n; i++ {
+					h[i*nn+i] -= x
+				}
+				s = math.Abs(h[n*nn+n-1]) + math.Abs(h[(n-1)*nn+n-2])
+				x = 0.75 * s
+				y = x
+				w = -0.4375 * s * s
+			}
+			if iter == 30 {
+				s = (y - x) / 2
+				s = s*s + w
+				if s > 0 {
+					s = math.Sqrt(s)
+					if y < x {
+						s = -s
+					}
+					s = x - w/((y-x)/2+s)
+					for i := 0; i <= n; i++ {
+						h[i*nn+i] -= s
+					}
+					exshift += s
+					x, y, w = 0.964, 0.964, 0.964
+				}
+			}
+			iter++
+			if iter > eigenIterations*max(nn, 2) {
+				return false
+			}
+
+			// look for two consecutive small subdiagonal elements
+			m := n - 2
+			for m >= l {
+				z = h[m*nn+m]
+				r = x - z
+				s = y - z
+				p = (r*s-w)/h[(m+1)*nn+m] + h[m*nn+m+1]
+				q = h[(m+1)*nn+m+1] - z - r - s
+				r = h[(m+2)*nn+m+1]
+				s = math.Abs(p) + math.Abs(q) + math.Abs(r)
+				p /= s
+				q /= s
+				r /= s
+				if m == l {
+					break
+				}
+				if math.Abs(h[m*nn+m-1])*(math.Abs(q)+math.Abs(r)) <
+					eps*(math.Abs(p)*(math.Abs(h[(m-1)*nn+m-1])+math.Abs(z)+math.Abs(h[(m+1)*nn+m+1]))) {
+					break
+				}
+				m--
+			}
+			for i := m + 2; i <= n; i++ {
+				h[i*nn+i-2] = 0
+				if i > m+2 {
+					h[i*nn+i-3] = 0
+				}
+			}
+
+			// double QR step involving rows l:n and columns m:n
+			for k := m; k <= n-1; k++ {
+				notlast := k != n-1
+				if k != m {
+					p = h[k*nn+k-1]
+					q = h[(k+1)*nn+k-1]
+					r = 0
+					if notlast {
+						r = h[(k+2)*nn+k-1]
+					}
+					x = math.Abs(p) + math.Abs(q) + math.Abs(r)
+					if x == 0 {
+						continue
+					}
+					p /= x
+					q /= x
+					r /= x
+				}
+				s = math.Sqrt(p*p + q*q + r*r)
+				if p < 0 {
+					s = -s
+				}
+				if s == 0 {
+					continue
+				}
+				if k != m {
+					h[k*nn+k-1] = -s * x
+				} else if l != m {
+					h[k*nn+k-1] = -h[k*nn+k-1]
+				}
+				p += s
+				x = p / s
+				y = q / s
+				z = r / s
+				q /= p
+				r /= p
+				for j := k; j < nn; j++ {
+					p = h[k*nn+j] + q*h[(k+1)*nn+j]
+					if notlast {
+						p += r * h[(k+2)*nn+j]
+						h[(k+2)*nn+j] -= p * z
+					}
+					h[k*nn+j] -= p * x
+					h[(k+1)*nn+j] -= p * y
+				}
+				for i := 0; i <= min(n, k+3); i++ {
+					p = x*h[i*nn+k] + y*h[i*nn+k+1]
+					if notlast {
+						p += z * h[i*nn+k+2]
+						h[i*nn+k+2] -= p * r
+					}
+					h[i*nn+k] -= p
+					h[i*nn+k+1] -= p * q
+				}
+				for i := 0; i < nn; i++ {
+					p = x*v[i*nn+k] + y*v[i*nn+k+1]
+					if notlast {
+						p += z * v[i*nn+k+2]
+						v[i*nn+k+2] -= p * r
+					}
+					v[i*nn+k] -= p
+					v[i*nn+k+1] -= p * q
+				}
+			}
+		}
+	}
+
+	if norm == 0 {
+		return true
+	}
+
+	// back substitute to find the vectors of the upper triangular form
+	for n = nn - 1; n >= 0; n-- {
+		p, q = d[n], e[n]
+		if q == 0 {
+			// real vector
+			l := n
+			h[n*nn+n] = 1
+			for i := n - 1; i >= 0; i-- {
+				w = h[i*nn+i] - p
+				r = 0
+				for j := l; j <= n; j++ {
+					r += h[i*nn+j] * h[j*nn+n]
+				}
+				if e[i] < 0 {
+					z, s = w, r
+					continue
+				}
+				l = i
+				if e[i] == 0 {
+					if w != 0 {
+						h[i*nn+n] = -r / w
+					} else {
+						h[i*nn+n] = -r / (eps * norm)
+					}
+				} else {
+					x = h[i*nn+i+1]
+					y = h[(i+1)*nn+i]
+					q = (d[i]-p)*(d[i]-p) + e[i]*e[i]
+					t = (x*s - z*r) / q
+					h[i*nn+n] = t
+					if math.Abs(x) > math.Abs(z) {
+						h[(i+1)*nn+n] = (-r - w*t) / x
+					} else {
+						h[(i+1)*nn+n] = (-s - y*t) / z
+					}
+				}
+				// overflow control
+				t = math.Abs(h[i*nn+n])
+				if (eps*t)*t > 1 {
+					for j := i; j <= n; j++ {
+						h[j*nn+n] /= t
+					}
+				}
+			}
+		} else if q < 0 {
+			// complex vector, the last component is taken to be imaginary
+			l := n - 1
+			if math.Abs(h[n*nn+n-1]) > math.Abs(h[(n-1)*nn+n]) {
+				h[(n-1)*nn+n-1] = q / h[n*nn+n-1]
+				h[(n-1)*nn+n] = -(h[n*nn+n] - p) / h[n*nn+n-1]
+			} else {
+				c := complex(0, -h[(n-1)*nn+n]) / complex(h[(n-1)*nn+n-1]-p, q)
+				h[(n-1)*nn+n-1], h[(n-1)*nn+n] = real(c), imag(c)
+			}
+			h[n*nn+n-1] = 0
+			h[n*nn+n] = 1
+			for i := n - 2; i >= 0; i-- {
+				ra, sa := 0.0, 0.0
+				for j := l; j <= n; j++ {
+					ra += h[i*nn+j] * h[j*nn+n-1]
+					sa += h[i*nn+j] * h[j*nn+n]
+				}
+				w = h[i*nn+i] - p
+				if e[i] < 0 {
+					z, r, s = w, ra, sa
+					continue
+				}
+				l = i
+				if e[i] == 0 {
+					c := complex(-ra, -sa) / complex(w, q)
+					h[i*nn+n-1], h[i*nn+n] = real(c), imag(c)
+				} else {
+					x = h[i*nn+i+1]
+					y = h[(i+1)*nn+i]
+					vr := (d[i]-p)*(d[i]-p) + e[i]*e[i] - q*q
+					vi := (d[i] - p) * 2 * q
+					if vr == 0 && vi == 0 {
+						vr = eps * norm * (math.Abs(w) + math.Abs(q) + math.Abs(x) + math.Abs(y) + math.Abs(z))
+					}
+					c := complex(x*r-z*ra+q*sa, x*s-z*sa-q*ra) / complex(vr, vi)
+					h[i*nn+n-1], h[i*nn+n] = real(c), imag(c)
+					if math.Abs(x) > math.Abs(z)+math.Abs(q) {
+						h[(i+1)*nn+n-1] = (-ra - w*h[i*nn+n-1] + q*h[i*nn+n]) / x
+						h[(i+1)*nn+n] = (-sa - w*h[i*nn+n] - q*h[i*nn+n-1]) / x
+					} else {
+						c = complex(-r-y*h[i*nn+n-1], -s-y*h[i*nn+n]) / complex(z, q)
+						h[(i+1)*nn+n-1], h[(i+1)*nn+n] = real(c), imag(c)
+					}
+				}
+				// overflow control
+				t = math.Max(math.Abs(h[i*nn+n-1]), math.Abs(h[i*nn+n]))
+				if (eps*t)*t > 1 {
+					for j := i; j <= n; j++ {
+						h[j*nn+n-1] /= t
+						h[j*nn+n] /= t
+					}
+				}
+			}
+		}
+	}
+
+	// back transform to the eigenvectors of the original matrix
+	for j := nn - 1; j >= 0; j-- {
+		for i := 0; i < nn; i++ {
+			z = 0
+			for k := 0; k <= j; k++ {
+				z += v[i*nn+k] * h[k*nn+j]
+			}
+			v[i*nn+j] = z
+		}
+	}
+	return true
+}
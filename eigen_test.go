@@ -0,0 +1,73 @@
+package goSci
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+func TestEighResidual(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 5, 12} {
+		a := randomArray(r, n, n)
+		s := Plus(a, a.T())
+		w, v, err := Eigh(s)
+		if err != nil {
+			t.Fatalf("n = %d: %v", n, err)
+		}
+		// A*V = V*diag(w) and V^T*V = I
+		assertClose(t, "Eigh residual", MatMult(s, v), ElemTimes(v, w), 1e-12)
+		assertClose(t, "Eigh orthogonality", MatMult(v.T(), v), Eye(n), 1e-12)
+		for i := 1; i < n; i++ {
+			if w.Get(i) < w.Get(i-1) {
+				t.Errorf("n = %d: eigenvalues not ascending: %v", n, w.Data())
+			}
+		}
+	}
+}
+
+func TestEigResidual(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for _, n := range []int{1, 2, 5, 12} {
+		a := randomArray(r, n, n)
+		values, vectors, err := Eig(a)
+		if err != nil {
+			t.Fatalf("n = %d: %v", n, err)
+		}
+		if residual := eigResidual(a, values, vectors); residual > 1e-10 {
+			t.Errorf("n = %d: |A*v - lambda*v| = %g", n, residual)
+		}
+	}
+
+	rotation := FromSlice([]float64{0, -1, 1, 0}, 2, 2)
+	values, _, err := Eig(rotation)
+	if err != nil || cmplx.Abs(values[0]-1i) > 1e-14 || cmplx.Abs(values[1]+1i) > 1e-14 {
+		t.Errorf("rotation: got %v, %v, want +-i", values, err)
+	}
+
+	// a Jordan block is defective, with a single eigenvector
+	jordan := Eye(4)
+	jordan.Put(1, 0, 1)
+	if values, _, err := Eig(jordan); err != nil || cmplx.Abs(values[0]-1) > 1e-7 {
+		t.Errorf("Jordan block: got %v, %v", values, err)
+	}
+}
+
+/*
+ Returns the largest |A*v - lambda*v| over the eigenpairs of the real matrix a
+*/
+func eigResidual(a *GsArray, values []complex128, vectors [][]complex128) float64 {
+	n := a.shape[0]
+	residual := 0.0
+	for j, lambda := range values {
+		for i := 0; i < n; i++ {
+			var sum complex128
+			for k := 0; k < n; k++ {
+				sum += complex(a.Get(i, k), 0) * vectors[j][k]
+			}
+			residual = math.Max(residual, cmplx.Abs(sum-lambda*vectors[j][i]))
+		}
+	}
+	return residual
+}
@@ -0,0 +1,149 @@
+package goSci
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*
+ Returned when the shapes of the operands are not valid for an operation.
+ Shape1 and Shape2 are the offending shapes, e.g. the two operands of Plus or
+ the old and new shape given to Reshape. Shape2 is nil when Shape1 is invalid
+ on its own, such as an empty array passed to MaxAxis.
+*/
+type ShapeMismatchError struct {
+	Op     string
+	Shape1 []int
+	Shape2 []int
+}
+
+func (e *ShapeMismatchError) Error() string {
+	if e.Shape2 == nil {
+		return fmt.Sprintf("goSci: %s: shape %v is invalid", e.Op, e.Shape1)
+	}
+	return fmt.Sprintf("goSci: %s: shapes %v and %v do not match", e.Op, e.Shape1, e.Shape2)
+}
+
+/*
+ Returned when a position is outside of an array. Axis is the offending axis
+ and Size its length. An Axis of -1 means the number of coordinates given
+ did not match the number of dimensions, which is then held in Size.
+*/
+type IndexOutOfRangeError struct {
+	Axis  int
+	Index int
+	Size  int
+}
+
+func (e *IndexOutOfRangeError) Error() string {
+	if e.Axis < 0 {
+		return fmt.Sprintf("goSci: %d coordinates given for an array with %d dimensions", e.Index, e.Size)
+	}
+	return fmt.Sprintf("goSci: index %d is out of range for axis %d with size %d", e.Index, e.Axis, e.Size)
+}
+
+/*
+ Returned when an axis, or one of the ALL, COLS and ROWS selectors, does not
+ exist for an array with NDim dimensions.
+*/
+type InvalidAxisError struct {
+	Axis int
+	NDim int
+}
+
+func (e *InvalidAxisError) Error() string {
+	return fmt.Sprintf("goSci: axis %d is invalid for an array with %d dimensions", e.Axis, e.NDim)
+}
+
+/*
+ Returned when an operation needs the elements of a view to be contiguous
+*/
+var ErrNotContiguous = errors.New("goSci: array is a non-contiguous view, use Copy first")
+
+/*
+ Returned when solving with or inverting a matrix that is singular
+*/
+var ErrSingular = errors.New("goSci: matrix is singular")
+
+/*
+ Returned when a Cholesky factorization or downdate meets a matrix that is not
+ symmetric positive definite
+*/
+var ErrNotPositiveDefinite = errors.New("goSci: matrix is not positive definite")
+
+/*
+ Returned when an iterative algorithm, such as the QR iteration of Eig, does
+ not converge within its iteration limit
+*/
+var ErrNoConvergence = errors.New("goSci: iteration did not converge")
+
+/*
+ Returned for a Slice with a zero step or more than one Ellipsis
+*/
+var ErrInvalidSlice = errors.New("goSci: invalid slice")
+
+/*
+ Returned by Norm for an order that is not defined for the shape, such as
+ Fro or Nuclear for a vector or Ord(3) for a matrix
+*/
+var ErrInvalidNorm = errors.New("goSci: invalid norm order")
+
+/*
+ Returned by Concatenate, Stack and the other joins when given no arrays, as
+ the shape of the result is then unknown
+*/
+var ErrNoArrays = errors.New("goSci: no arrays to join")
+
+/*
+ Returned when an argument is outside the domain of an operation, such as a
+ zero step for ArangeStep
+*/
+var ErrDomain = errors.New("goSci: argument out of domain")
+
+func shapeError(op string, x, y []int) error {
+	s1 := make([]int, len(x))
+	copy(s1, x)
+	var s2 []int
+	if y != nil {
+		s2 = make([]int, len(y))
+		copy(s2, y)
+	}
+	return &ShapeMismatchError{Op: op, Shape1: s1, Shape2: s2}
+}
+
+/*
+ Returns axis as a position in 0..n-1, counting negative axes back from the end
+*/
+func checkAxis(axis, n int) (int, error) {
+	pos := axis
+	if pos < 0 {
+		pos += n
+	}
+	if pos < 0 || pos >= n {
+		return 0, &InvalidAxisError{Axis: axis, NDim: n}
+	}
+	return pos, nil
+}
+
+/*
+ Returns x, panicking with err if it is not nil. Used by the panicking forms
+ of the operations that have a Try variant.
+*/
+func must[T any](x T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return x
+}
+
+func mustDo(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+/*
+ Returned by Einsum for subscripts that are malformed, name an output label
+ not in the inputs or do not match the number of operands
+*/
+var ErrInvalidSubscripts = errors.New("goSci: invalid einsum subscripts")
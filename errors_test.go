@@ -0,0 +1,46 @@
+package goSci
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTryErrors(t *testing.T) {
+	x := Arange(6)
+	x.Reshape(2, 3)
+
+	_, err := x.TryGet(5, 0)
+	var ie *IndexOutOfRangeError
+	if !errors.As(err, &ie) || ie.Axis != 0 || ie.Index != 5 || ie.Size != 2 {
+		t.Errorf("TryGet: got %v", err)
+	}
+	_, err = x.TryGet(1)
+	if !errors.As(err, &ie) || ie.Axis != -1 {
+		t.Errorf("TryGet with too few coordinates: got %v", err)
+	}
+	_, err = TrySum(x, 7)
+	var ae *InvalidAxisError
+	if !errors.As(err, &ae) || ae.Axis != 7 || ae.NDim != 2 {
+		t.Errorf("TrySum: got %v", err)
+	}
+	if err := x.TryReshape(4); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("TryReshape: got %v", err)
+	}
+	if err := x.T().TryReshape(6); !errors.Is(err, ErrNotContiguous) {
+		t.Errorf("TryReshape of a transpose: got %v", err)
+	}
+	if _, err := TryMatMult(x, x); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("TryMatMult: got %v", err)
+	}
+	if _, err := TryFromSlice(make([]float64, 5), 2, 3); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("TryFromSlice: got %v", err)
+	}
+
+	// the panicking variants panic with the same typed errors
+	defer func() {
+		if _, ok := recover().(*IndexOutOfRangeError); !ok {
+			t.Error("Put out of range did not panic with an IndexOutOfRangeError")
+		}
+	}()
+	x.Put(1, 2, 2)
+}
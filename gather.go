@@ -0,0 +1,233 @@
+package goSci
+
+/*
+ Returns the elements of x at indices along axis. The result has the shape of
+ x with that axis replaced by the shape of indices, so a vector of indices
+ reorders or resamples the rows of a matrix with axis 0. Negative indices
+ count from the end of the axis.
+ e.g.
+ Take(x, ArrayFromSlice([]int64{2, 0, 0}, 3), 0)
+ holds rows 2, 0 and 0 of x
+*/
+func Take(x *GsArray, indices *Array[int64], axis int) *GsArray {
+	return must(TryTake(x, indices, axis))
+}
+
+/*
+ Same as Take but returns an InvalidAxisError or IndexOutOfRangeError instead
+ of panicking
+*/
+func TryTake(x *GsArray, indices *Array[int64], axis int) (*GsArray, error) {
+	ax, err := checkAxis(axis, len(x.shape))
+	if err != nil {
+		return nil, err
+	}
+	n := x.shape[ax]
+	rows := make([]int, len(indices.data))
+	for k, idx := range indices.data {
+		if rows[k], err = wrapIndex(idx, ax, n); err != nil {
+			return nil, err
+		}
+	}
+	shape := append(append(append([]int(nil), x.shape[:ax]...), indices.shape...), x.shape[ax+1:]...)
+	outer, inner := shapeSize(x.shape[:ax]), shapeSize(x.shape[ax+1:])
+	vals := x.values()
+	result := Zeros(shape...)
+	for o := 0; o < outer; o++ {
+		for k, row := range rows {
+			start := (o*n + row) * inner
+			copy(result.data[(o*len(rows)+k)*inner:], vals[start:start+inner])
+		}
+	}
+	return result, nil
+}
+
+/*
+ Returns the slices of x at indices along axis, keeping the rank of x.
+ It is Take with a list of indices.
+*/
+func IndexSelect(x *GsArray, axis int, indices []int) *GsArray {
+	return must(TryIndexSelect(x, axis, indices))
+}
+
+/*
+ Same as IndexSelect but returns an InvalidAxisError or IndexOutOfRangeError
+ instead of panicking
+*/
+func TryIndexSelect(x *GsArray, axis int, indices []int) (*GsArray, error) {
+	idx := ZerosOf[int64](len(indices))
+	for i, val := range indices {
+		idx.data[i] = int64(val)
+	}
+	return TryTake(x, idx, axis)
+}
+
+/*
+ Returns the elements of x picked along axis by indices, which has the rank
+ of x: element (i, j, k) of the result for axis 1 is x[i][indices[i][j][k]][k].
+ The other axes of x and indices are broadcast together, and the result has
+ the broadcast shape with the length of indices along axis.
+*/
+func TakeAlongAxis(x *GsArray, indices *Array[int64], axis int) *GsArray {
+	return must(TryTakeAlongAxis(x, indices, axis))
+}
+
+/*
+ Same as TakeAlongAxis but returns a ShapeMismatchError, InvalidAxisError or
+ IndexOutOfRangeError instead of panicking
+*/
+func TryTakeAlongAxis(x *GsArray, indices *Array[int64], axis int) (*GsArray, error) {
+	ax, shape, err := alongAxisShape("TakeAlongAxis", x, indices, axis)
+	if err != nil {
+		return nil, err
+	}
+	result := Zeros(shape...)
+	i := 0
+	err = walkAlongAxis(x, indices, nil, ax, shape, func(xi, _ int) {
+		result.data[i] = x.data[xi]
+		i++
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+ Sets the elements of x picked along axis by indices, as in TakeAlongAxis, to
+ values, broadcast to the shape of indices. The other axes of indices must
+ broadcast to those of x. Where an index repeats the last value wins.
+*/
+func (x *GsArray) PutAlongAxis(indices *Array[int64], values *GsArray, axis int) {
+	mustDo(x.TryPutAlongAxis(indices, values, axis))
+}
+
+/*
+ Same as PutAlongAxis but returns a ShapeMismatchError, InvalidAxisError or
+ IndexOutOfRangeError instead of panicking
+*/
+func (x *GsArray) TryPutAlongAxis(indices *Array[int64], values *GsArray, axis int) error {
+	return x.scatter("PutAlongAxis", indices, values, axis, func(dst *float64, val float64) { *dst = val })
+}
+
+/*
+ Adds values into the elements of x picked along axis by indices, as in
+ PutAlongAxis, except that where an index repeats every value is added.
+ e.g. with axis 0 and vectors, x[indices[k]] += values[k] for every k, which
+ accumulates a histogram or the sums of groups.
+*/
+func (x *GsArray) ScatterAdd(indices *Array[int64], values *GsArray, axis int) {
+	mustDo(x.TryScatterAdd(indices, values, axis))
+}
+
+/*
+ Same as ScatterAdd but returns a ShapeMismatchError, InvalidAxisError or
+ IndexOutOfRangeError instead of panicking
+*/
+func (x *GsArray) TryScatterAdd(indices *Array[int64], values *GsArray, axis int) error {
+	return x.scatter("ScatterAdd", indices, values, axis, func(dst *float64, val float64) { *dst += val })
+}
+
+/*
+ Applies op to each element of x picked by indices along axis and the
+ matching element of values
+*/
+func (x *GsArray) scatter(name string, indices *Array[int64], values *GsArray, axis int, op func(dst *float64, val float64)) error {
+	ax, shape, err := alongAxisShape(name, x, indices, axis)
+	if err != nil {
+		return err
+	}
+	for d := range shape {
+		if d != ax && shape[d] != x.shape[d] {
+			return shapeError(name, x.shape, indices.shape)
+		}
+	}
+	if broadcast, ok := broadcastShapes(shape, values.shape); !ok || !sameShape(broadcast, shape) {
+		return shapeError(name, shape, values.shape)
+	}
+	// values may be x itself, read at other positions than those written
+	if values == x || overlaps(x, values) {
+		values = values.Copy()
+	}
+	// check every index before writing so a bad one leaves x unchanged
+	if err := walkAlongAxis(x, indices, nil, ax, shape, func(int, int) {}); err != nil {
+		return err
+	}
+	return walkAlongAxis(x, indices, values, ax, shape, func(xi, vi int) {
+		op(&x.data[xi], values.data[vi])
+	})
+}
+
+/*
+ Returns the normalized axis and the shape of the result of picking elements
+ of x along axis with indices: the broadcast of the shapes of x and indices
+ except along axis, where it is the length of indices
+*/
+func alongAxisShape(name string, x *GsArray, indices *Array[int64], axis int) (int, []int, error) {
+	ax, err := checkAxis(axis, len(x.shape))
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(indices.shape) != len(x.shape) {
+		return 0, nil, shapeError(name, x.shape, indices.shape)
+	}
+	own := append([]int(nil), x.shape...)
+	own[ax] = indices.shape[ax]
+	shape, ok := broadcastShapes(own, indices.shape)
+	if !ok {
+		return 0, nil, shapeError(name, x.shape, indices.shape)
+	}
+	return ax, shape, nil
+}
+
+/*
+ Walks shape in row major order, calling visit with the index into x.data of
+ the element picked by indices along axis and, when values is not nil, the
+ index into values.data of the matching value. Stops with an
+ IndexOutOfRangeError at the first index out of range.
+*/
+func walkAlongAxis(x *GsArray, indices *Array[int64], values *GsArray, axis int, shape []int, visit func(xi, vi int)) error {
+	target := append([]int(nil), shape...)
+	target[axis] = x.shape[axis]
+	xStrides := broadcastStrides(x, target)
+	axisStride := x.getStrides()[axis]
+	xStrides[axis] = 0
+	strides := [][]int{xStrides, arrayStrides(indices, shape)}
+	offsets := []int{x.offset, 0}
+	if values != nil {
+		strides = append(strides, broadcastStrides(values, shape))
+		offsets = append(offsets, values.offset)
+	}
+	var err error
+	walkStrided(shape, offsets, strides, func(pos []int) {
+		if err != nil {
+			return
+		}
+		row, e := wrapIndex(indices.data[pos[1]], axis, x.shape[axis])
+		if e != nil {
+			err = e
+			return
+		}
+		vi := 0
+		if values != nil {
+			vi = pos[2]
+		}
+		visit(pos[0]+row*axisStride, vi)
+	})
+	return err
+}
+
+/*
+ Returns idx as a position along an axis of length n, counting negative
+ indices from the end, or an IndexOutOfRangeError
+*/
+func wrapIndex(idx int64, axis, n int) (int, error) {
+	i := int(idx)
+	if i < 0 {
+		i += n
+	}
+	if i < 0 || i >= n {
+		return 0, &IndexOutOfRangeError{Axis: axis, Index: int(idx), Size: n}
+	}
+	return i, nil
+}
@@ -0,0 +1,141 @@
+package goSci
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTake(t *testing.T) {
+	x := Arange(12)
+	x.Reshape(3, 4)
+
+	rows := ArrayFromSlice([]int64{2, 0, -1}, 3)
+	assertArray(t, "Take rows", Take(x, rows, 0), []int{3, 4}, []float64{8, 9, 10, 11, 0, 1, 2, 3, 8, 9, 10, 11})
+	// indices of rank two replace the axis by their shape
+	cols := ArrayFromSlice([]int64{3, 0, 1, 1}, 2, 2)
+	assertArray(t, "Take columns", Take(x, cols, -1), []int{3, 2, 2}, []float64{
+		3, 0, 1, 1,
+		7, 4, 5, 5,
+		11, 8, 9, 9,
+	})
+	assertArray(t, "Take from a transpose", Take(x.T(), ArrayFromSlice([]int64{1}, 1), 0), []int{1, 3}, []float64{1, 5, 9})
+	assertArray(t, "Take from a stepped view", Take(x.Slice(Span(0, 3, 2), Span(3, Omit, -1)), ArrayFromSlice([]int64{0, 2}, 2), 1), []int{2, 2}, []float64{3, 1, 11, 9})
+	assertArray(t, "Take nothing", Take(x, ZerosOf[int64](0), 1), []int{3, 0}, []float64{})
+
+	assertArray(t, "IndexSelect", IndexSelect(x, 1, []int{2, -4}), []int{3, 2}, []float64{2, 0, 6, 4, 10, 8})
+
+	cases := []struct {
+		name string
+		err  error
+		axis int
+		idx  int
+		size int
+	}{
+		{"Take", second(TryTake(x, ArrayFromSlice([]int64{0, 3}, 2), 0)), 0, 3, 3},
+		{"Take negative", second(TryTake(x, ArrayFromSlice([]int64{-5}, 1), 1)), 1, -5, 4},
+		{"IndexSelect", second(TryIndexSelect(x, -1, []int{4})), 1, 4, 4},
+	}
+	for _, c := range cases {
+		var ie *IndexOutOfRangeError
+		if !errors.As(c.err, &ie) || ie.Axis != c.axis || ie.Index != c.idx || ie.Size != c.size {
+			t.Errorf("%s out of range: got %v", c.name, c.err)
+		}
+	}
+	if _, err := TryTake(x, rows, 2); !errors.As(err, new(*InvalidAxisError)) {
+		t.Errorf("Take along an invalid axis: got %v", err)
+	}
+}
+
+func TestTakeAlongAxis(t *testing.T) {
+	x := FromSlice([]float64{
+		10, 30, 20,
+		60, 40, 50,
+	}, 2, 3)
+	// the positions of the largest element of each row
+	largest := ArrayFromSlice([]int64{1, 0}, 2, 1)
+	assertArray(t, "TakeAlongAxis rows", TakeAlongAxis(x, largest, 1), []int{2, 1}, []float64{30, 60})
+	order := ArrayFromSlice([]int64{1, 0, 0, 0, 1, 1}, 2, 3)
+	assertArray(t, "TakeAlongAxis columns", TakeAlongAxis(x, order, 0), []int{2, 3}, []float64{60, 30, 20, 10, 40, 50})
+	// the other axes broadcast, here a single row of indices for every row of x
+	assertArray(t, "TakeAlongAxis broadcast", TakeAlongAxis(x, ArrayFromSlice([]int64{2, -3}, 1, 2), 1), []int{2, 2}, []float64{20, 10, 50, 60})
+	assertArray(t, "TakeAlongAxis of a transpose", TakeAlongAxis(x.T(), ArrayFromSlice([]int64{1, 0, 1}, 3, 1), 1), []int{3, 1}, []float64{60, 30, 50})
+
+	var ie *IndexOutOfRangeError
+	if _, err := TryTakeAlongAxis(x, ArrayFromSlice([]int64{0, 3}, 2, 1), 1); !errors.As(err, &ie) || ie.Axis != 1 || ie.Index != 3 {
+		t.Errorf("TakeAlongAxis out of range: got %v", err)
+	}
+	for name, err := range map[string]error{
+		"of a lower rank":   second(TryTakeAlongAxis(x, ArrayFromSlice([]int64{0}, 1), 1)),
+		"of the wrong size": second(TryTakeAlongAxis(x, ZerosOf[int64](3, 1), 1)),
+	} {
+		if !errors.As(err, new(*ShapeMismatchError)) {
+			t.Errorf("TakeAlongAxis with indices %s: got %v", name, err)
+		}
+	}
+}
+
+func TestPutAlongAxis(t *testing.T) {
+	x := Zeros(2, 3)
+	x.PutAlongAxis(ArrayFromSlice([]int64{2, 0}, 2, 1), FromSlice([]float64{5, 6}, 2, 1), 1)
+	assertArray(t, "PutAlongAxis", x, []int{2, 3}, []float64{0, 0, 5, 6, 0, 0})
+	// a scalar value broadcasts
+	x.PutAlongAxis(ArrayFromSlice([]int64{1, 1, 1}, 1, 3), FromSlice([]float64{7}, 1), 0)
+	assertArray(t, "PutAlongAxis scalar", x, []int{2, 3}, []float64{0, 0, 5, 7, 7, 7})
+	// the last of repeated indices wins
+	x.PutAlongAxis(ArrayFromSlice([]int64{0, 0}, 1, 2), FromSlice([]float64{1, 2}, 1, 2), 1)
+	assertArray(t, "PutAlongAxis repeated", x, []int{2, 3}, []float64{2, 0, 5, 2, 7, 7})
+
+	// writes go through a strided view to its base
+	base := Zeros(3, 4)
+	view := base.Slice(Span(0, 3, 2), Span(1, 4, 2))
+	view.PutAlongAxis(ArrayFromSlice([]int64{1, 0}, 2, 1), FromSlice([]float64{8, 9}, 2, 1), 1)
+	assertArray(t, "PutAlongAxis into a view", base, []int{3, 4}, []float64{0, 0, 0, 8, 0, 0, 0, 0, 0, 9, 0, 0})
+}
+
+func TestScatterAdd(t *testing.T) {
+	// duplicate indices all add, as for a histogram
+	counts := Zeros(4)
+	counts.ScatterAdd(ArrayFromSlice([]int64{1, 3, 1, 1, -1}, 5), FromSlice([]float64{1}, 1), 0)
+	assertArray(t, "histogram", counts, []int{4}, []float64{0, 3, 0, 2})
+
+	// the sums of the groups of the columns of each row
+	sums := Zeros(2, 2)
+	groups := ArrayFromSlice([]int64{0, 1, 0, 0}, 1, 4)
+	sums.ScatterAdd(groups, FromSlice([]float64{1, 2, 3, 4, 5, 6, 7, 8}, 2, 4), 1)
+	assertArray(t, "group sums", sums, []int{2, 2}, []float64{8, 2, 20, 6})
+
+	// values that are x itself are read before any is written
+	x := FromSlice([]float64{1, 2, 3}, 3)
+	x.ScatterAdd(ArrayFromSlice([]int64{1, 2, 0}, 3), x, 0)
+	assertArray(t, "values aliasing x", x, []int{3}, []float64{4, 3, 5})
+	y := FromSlice([]float64{1, 2, 3, 4}, 4)
+	y.ScatterAdd(ArrayFromSlice([]int64{0, 0}, 2), y.Slice(Span(2, 4, 1)), 0)
+	assertArray(t, "values overlapping x", y, []int{4}, []float64{8, 2, 3, 4})
+
+	// a bad index anywhere leaves x unchanged
+	x = FromSlice([]float64{1, 2, 3}, 3)
+	err := x.TryScatterAdd(ArrayFromSlice([]int64{0, 1, 3}, 3), Ones(3), 0)
+	var ie *IndexOutOfRangeError
+	if !errors.As(err, &ie) || ie.Axis != 0 || ie.Index != 3 || ie.Size != 3 {
+		t.Errorf("ScatterAdd out of range: got %v", err)
+	}
+	assertArray(t, "x after a failed ScatterAdd", x, []int{3}, []float64{1, 2, 3})
+	if err := x.TryPutAlongAxis(ArrayFromSlice([]int64{-4}, 1), Ones(1), 0); !errors.As(err, &ie) || ie.Index != -4 {
+		t.Errorf("PutAlongAxis out of range: got %v", err)
+	}
+
+	m := Zeros(2, 3)
+	for name, err := range map[string]error{
+		"indices of a lower rank":    m.TryScatterAdd(ArrayFromSlice([]int64{0}, 1), Ones(1), 1),
+		"indices larger than x":      m.TryScatterAdd(ZerosOf[int64](3, 1), Ones(1), 1),
+		"indices smaller than x":     m.TryPutAlongAxis(ZerosOf[int64](1, 2), Ones(1), 0),
+		"values larger than indices": m.TryScatterAdd(ZerosOf[int64](2, 1), Ones(2, 2), 1),
+	} {
+		if !errors.As(err, new(*ShapeMismatchError)) {
+			t.Errorf("ScatterAdd with %s: got %v", name, err)
+		}
+	}
+	if err := m.TryScatterAdd(ZerosOf[int64](2, 1), Ones(1), -3); !errors.As(err, new(*InvalidAxisError)) {
+		t.Errorf("ScatterAdd along an invalid axis: got %v", err)
+	}
+}
@@ -4,6 +4,8 @@ package goSci
 type GsArray struct {
 	data        []float64
 	shape       []int
+	strides     []int     // nil when data holds exactly the elements in row major order
+	offset      int       // index in data of the first element of a strided view
 }
 
 const(
@@ -67,18 +69,69 @@ func Eye(size int) *GsArray {
 	return array
 }
 
+/*
+ Creates a GsArray with shape defined by shape that holds data in row major
+ order. The array shares data rather than copying it.
+*/
+func FromSlice(data []float64, shape ... int) *GsArray {
+	array, err := TryFromSlice(data, shape...)
+	if err != nil {
+		panic(err)
+	}
+	return array
+}
+
+/*
+ Same as FromSlice but returns a ShapeMismatchError instead of panicking when
+ the length of data is not the product of shape
+*/
+func TryFromSlice(data []float64, shape ... int) (*GsArray, error) {
+	product := 1
+	for _, value := range shape {
+		product *= value
+	}
+	if product != len(data) {
+		return nil, shapeError("FromSlice", []int{len(data)}, shape)
+	}
+	array := new(GsArray)
+	array.data = data
+	array.shape = append([]int(nil), shape...)
+	return array, nil
+}
+
+/*
+ Returns a copy of the elements of array in row major order
+*/
+func (array *GsArray) Data() []float64 {
+	return array.Copy().data
+}
+
 /*
  Reshapes array to new shape. The product of shape must be the same as product of old shape.
+ Panics if array is a view whose elements are not contiguous, use Copy first.
 */
 func (array *GsArray) Reshape(shape ... int) {
+	if err := array.TryReshape(shape...); err != nil {
+		panic(err)
+	}
+}
+
+/*
+ Same as Reshape but returns a ShapeMismatchError or ErrNotContiguous instead of panicking
+*/
+func (array *GsArray) TryReshape(shape ... int) error {
 	product := 1
 	for _, value := range shape {
 		product *= value
 	}
-	if product != len(array.data) {
-		panic("ValueError: total size of new array must be unchanged")
+	if product != array.Size() {
+		return shapeError("Reshape", array.shape, shape)
+	}
+	if array.strides != nil {
+		return ErrNotContiguous
 	}
 	array.shape = shape
+	return nil
 }
 
 /*
@@ -88,50 +141,61 @@ func (array *GsArray) Reshape(shape ... int) {
  would put 1.5 into row 4 column 3
 */
 func (array *GsArray) Put(val float64, pos ... int) {
-	if len(array.shape) != len(pos){
-		panic("Invalid posistion!")
-	}
-	if len(array.shape) == 1{
-		array.data[pos[0]] = val
-	}else if len(array.shape) == 2 {
-		dim2 := array.shape[1]
-		array.data[pos[0]*dim2 + pos[1]] = val
-	}else {
-		prod := make([]int, len(pos))
-		prod[len(pos) - 1] = 1
-		for i:=2; i <= len(pos); i++ {
-			prod[len(pos) - i]  = array.shape[len(pos) - i + 1] * prod[len(pos) - i + 1]
-		}
-		idx := 0
-		for i:=0; i < len(pos) ; i++ {
-			idx += prod[i] * pos[i]
-		}
-		array.data[idx] = val
+	if err := array.TryPut(val, pos...); err != nil {
+		panic(err)
+	}
+}
+
+/*
+ Same as Put but returns an IndexOutOfRangeError instead of panicking
+*/
+func (array *GsArray) TryPut(val float64, pos ... int) error {
+	idx, err := array.index(pos)
+	if err != nil {
+		return err
 	}
+	array.data[idx] = val
+	return nil
 }
 /*
  returns value of value at postion pos
 */
 func (array *GsArray) Get(pos ... int) float64 {
-	if len(array.shape) != len(pos){
-		panic("Invalid posistion!")
-	}
-	if len(array.shape) == 1{
-		return array.data[pos[0]]
+	val, err := array.TryGet(pos...)
+	if err != nil {
+		panic(err)
 	}
-	if len(array.shape) == 2 {
-		dim2 := array.shape[1]
-		return array.data[pos[0]*dim2 + pos[1]]
+	return val
+}
+
+/*
+ Same as Get but returns an IndexOutOfRangeError instead of panicking
+*/
+func (array *GsArray) TryGet(pos ... int) (float64, error) {
+	idx, err := array.index(pos)
+	if err != nil {
+		return 0, err
 	}
-	prod := make([]int, len(pos))
-	prod[len(pos) - 1] = 1
-	for i:=2; i <= len(pos); i++ {
-		prod[len(pos) - i]  = array.shape[len(pos) - i + 1] * prod[len(pos) - i + 1]
+	return array.data[idx], nil
+}
+
+/*
+ Returns the index into data of the element at postion pos
+*/
+func (array *GsArray) index(pos []int) (int, error) {
+	if len(array.shape) != len(pos){
+		return 0, &IndexOutOfRangeError{Axis: -1, Index: len(pos), Size: len(array.shape)}
 	}
-	idx := 0
-	for i:=0; i < len(pos) ; i++ {
-		idx += prod[i] * pos[i]
+	idx := array.offset
+	for i, p := range pos {
+		if p < 0 || p >= array.shape[i] {
+			return 0, &IndexOutOfRangeError{Axis: i, Index: p, Size: array.shape[i]}
+		}
+		if array.strides == nil {
+			idx = idx*array.shape[i] + p
+		} else {
+			idx += p * array.strides[i]
+		}
 	}
-	return array.data[idx]
+	return idx, nil
 }
-
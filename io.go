@@ -19,11 +19,12 @@ func (array *GsArray) String() string {
 		return "I only print arrays with dimension less than 3."
 	}
 	buff := bytes.NewBufferString("")
-	for i:=0; i<len(array.data); i++ {
+	data := array.values()
+	for i:=0; i<len(data); i++ {
 		if i % array.shape[1] == 0 && i != 0 {
 			fmt.Fprint(buff, "\n")
 		}
-		fmt.Fprintf(buff, "%f ", array.data[i])
+		fmt.Fprintf(buff, "%f ", data[i])
 	}
 	return buff.String()
 }
@@ -85,13 +86,14 @@ func (array *GsArray) WriteTable(fileName string, delim string) error {
 	defer file.Close()
 	
 	buff := bytes.NewBufferString("")
-	for i:=0; i<len(array.data); i++ {
+	data := array.values()
+	for i:=0; i<len(data); i++ {
 		if i % array.shape[1] == 0 && i != 0 {
 			fmt.Fprint(buff, "\n")
 		} else if i != 0{
 			fmt.Fprintf(buff, "%s", delim)
 		}
-		fmt.Fprintf(buff, "%f", array.data[i])
+		fmt.Fprintf(buff, "%f", data[i])
 	}
 	w := bufio.NewWriter(file)
 	_, err = w.WriteString(buff.String())
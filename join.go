@@ -0,0 +1,362 @@
+package goSci
+
+/*
+ Returns the arrays joined along an existing axis. They must have the same
+ rank and the same length on every other axis.
+ e.g.
+ Concatenate(0, x, y, z)
+ stacks the rows of three matrices with the same number of columns
+*/
+func Concatenate(axis int, arrays ...*GsArray) *GsArray {
+	return must(TryConcatenate(axis, arrays...))
+}
+
+/*
+ Same as Concatenate but returns a ShapeMismatchError or InvalidAxisError,
+ or ErrNoArrays when given no arrays, instead of panicking
+*/
+func TryConcatenate(axis int, arrays ...*GsArray) (*GsArray, error) {
+	if len(arrays) == 0 {
+		return nil, ErrNoArrays
+	}
+	first := arrays[0]
+	ax, err := checkAxis(axis, len(first.shape))
+	if err != nil {
+		return nil, err
+	}
+	shape := append([]int(nil), first.shape...)
+	shape[ax] = 0
+	for _, x := range arrays {
+		if len(x.shape) != len(first.shape) {
+			return nil, shapeError("Concatenate", first.shape, x.shape)
+		}
+		for d := range x.shape {
+			if d != ax && x.shape[d] != first.shape[d] {
+				return nil, shapeError("Concatenate", first.shape, x.shape)
+			}
+		}
+		shape[ax] += x.shape[ax]
+	}
+	result := Zeros(shape...)
+	outer, inner := shapeSize(shape[:ax]), shapeSize(shape[ax+1:])
+	k := 0
+	vals := make([][]float64, len(arrays))
+	for i, x := range arrays {
+		vals[i] = x.values()
+	}
+	for o := 0; o < outer; o++ {
+		for i, x := range arrays {
+			chunk := x.shape[ax] * inner
+			k += copy(result.data[k:], vals[i][o*chunk:(o+1)*chunk])
+		}
+	}
+	return result, nil
+}
+
+/*
+ Returns the arrays, which must all have the same shape, joined along a new
+ axis at position axis of the result
+ e.g.
+ Stack(0, x, y)
+ of two vectors of length n is a 2 by n matrix
+*/
+func Stack(axis int, arrays ...*GsArray) *GsArray {
+	return must(TryStack(axis, arrays...))
+}
+
+/*
+ Same as Stack but returns a ShapeMismatchError or InvalidAxisError, or
+ ErrNoArrays when given no arrays, instead of panicking
+*/
+func TryStack(axis int, arrays ...*GsArray) (*GsArray, error) {
+	if len(arrays) == 0 {
+		return nil, ErrNoArrays
+	}
+	ax, err := checkAxis(axis, len(arrays[0].shape)+1)
+	if err != nil {
+		return nil, err
+	}
+	expanded := make([]*GsArray, len(arrays))
+	for i, x := range arrays {
+		if !sameShape(x.shape, arrays[0].shape) {
+			return nil, shapeError("Stack", arrays[0].shape, x.shape)
+		}
+		expanded[i] = expandAxis(x, ax)
+	}
+	return TryConcatenate(ax, expanded...)
+}
+
+/*
+ Returns the arrays joined horizontally: along axis 1, or along axis 0 for
+ vectors
+*/
+func HStack(arrays ...*GsArray) *GsArray {
+	return must(TryHStack(arrays...))
+}
+
+/*
+ Same as HStack but returns a ShapeMismatchError, or ErrNoArrays when given
+ no arrays, instead of panicking
+*/
+func TryHStack(arrays ...*GsArray) (*GsArray, error) {
+	if len(arrays) > 0 && len(arrays[0].shape) == 1 {
+		return TryConcatenate(0, arrays...)
+	}
+	return TryConcatenate(1, arrays...)
+}
+
+/*
+ Returns the arrays joined vertically along axis 0, with vectors of length n
+ taken as 1 by n rows
+*/
+func VStack(arrays ...*GsArray) *GsArray {
+	return must(TryVStack(arrays...))
+}
+
+/*
+ Same as VStack but returns a ShapeMismatchError, or ErrNoArrays when given
+ no arrays, instead of panicking
+*/
+func TryVStack(arrays ...*GsArray) (*GsArray, error) {
+	expanded := make([]*GsArray, len(arrays))
+	for i, x := range arrays {
+		expanded[i] = x
+		if len(x.shape) == 1 {
+			expanded[i] = expandAxis(x, 0)
+		}
+	}
+	return TryConcatenate(0, expanded...)
+}
+
+/*
+ Returns the arrays joined in depth along axis 2, with vectors of length n
+ taken as 1 by n by 1 and m by n matrices as m by n by 1
+*/
+func DStack(arrays ...*GsArray) *GsArray {
+	return must(TryDStack(arrays...))
+}
+
+/*
+ Same as DStack but returns a ShapeMismatchError, or ErrNoArrays when given
+ no arrays, instead of panicking
+*/
+func TryDStack(arrays ...*GsArray) (*GsArray, error) {
+	expanded := make([]*GsArray, len(arrays))
+	for i, x := range arrays {
+		switch len(x.shape) {
+		case 1:
+			expanded[i] = expandAxis(expandAxis(x, 0), 2)
+		case 2:
+			expanded[i] = expandAxis(x, 2)
+		default:
+			expanded[i] = x
+		}
+	}
+	return TryConcatenate(2, expanded...)
+}
+
+/*
+ Returns a view of x with a new axis of length one inserted at position axis
+*/
+func expandAxis(x *GsArray, axis int) *GsArray {
+	idx := make([]Index, len(x.shape)+1)
+	for i := range idx {
+		idx[i] = All
+	}
+	idx[axis] = NewAxis
+	return x.Slice(idx...)
+}
+
+/*
+ Splits x into sections views of equal length along axis. The length of the
+ axis must be a multiple of sections.
+*/
+func Split(x *GsArray, sections, axis int) []*GsArray {
+	return must(TrySplit(x, sections, axis))
+}
+
+/*
+ Same as Split but returns a ShapeMismatchError or InvalidAxisError instead
+ of panicking
+*/
+func TrySplit(x *GsArray, sections, axis int) ([]*GsArray, error) {
+	ax, err := checkAxis(axis, len(x.shape))
+	if err != nil {
+		return nil, err
+	}
+	if sections <= 0 || x.shape[ax]%sections != 0 {
+		return nil, shapeError("Split", x.shape, []int{sections})
+	}
+	return TryArraySplit(x, sections, axis)
+}
+
+/*
+ Splits x into sections views along axis. Unlike Split the length of the axis
+ need not be a multiple of sections: the first length % sections views get
+ one extra element.
+*/
+func ArraySplit(x *GsArray, sections, axis int) []*GsArray {
+	return must(TryArraySplit(x, sections, axis))
+}
+
+/*
+ Same as ArraySplit but returns a ShapeMismatchError or InvalidAxisError
+ instead of panicking
+*/
+func TryArraySplit(x *GsArray, sections, axis int) ([]*GsArray, error) {
+	ax, err := checkAxis(axis, len(x.shape))
+	if err != nil {
+		return nil, err
+	}
+	if sections <= 0 {
+		return nil, shapeError("ArraySplit", x.shape, []int{sections})
+	}
+	n := x.shape[ax]
+	bounds := make([]int, sections-1)
+	stop := 0
+	for i := range bounds {
+		stop += n / sections
+		if i < n%sections {
+			stop++
+		}
+		bounds[i] = stop
+	}
+	return TrySplitAt(x, bounds, axis)
+}
+
+/*
+ Splits x along axis before each of the increasing positions in bounds, into
+ len(bounds)+1 views
+ e.g.
+ SplitAt(x, []int{2, 5}, 0)
+ gives rows 0:2, 2:5 and 5: of x
+*/
+func SplitAt(x *GsArray, bounds []int, axis int) []*GsArray {
+	return must(TrySplitAt(x, bounds, axis))
+}
+
+/*
+ Same as SplitAt but returns an InvalidAxisError or IndexOutOfRangeError
+ instead of panicking
+*/
+func TrySplitAt(x *GsArray, bounds []int, axis int) ([]*GsArray, error) {
+	ax, err := checkAxis(axis, len(x.shape))
+	if err != nil {
+		return nil, err
+	}
+	n := x.shape[ax]
+	idx := make([]Index, len(x.shape))
+	for i := range idx {
+		idx[i] = All
+	}
+	parts := make([]*GsArray, 0, len(bounds)+1)
+	start := 0
+	for _, stop := range append(append([]int(nil), bounds...), n) {
+		if stop < start || stop > n {
+			return nil, &IndexOutOfRangeError{Axis: ax, Index: stop, Size: n}
+		}
+		idx[ax] = Span(start, stop, 1)
+		parts = append(parts, x.Slice(idx...))
+		start = stop
+	}
+	return parts, nil
+}
+
+/*
+ Returns x repeated reps[i] times along axis i. When reps is shorter than the
+ rank of x it is padded with leading ones, and when it is longer x is taken to
+ have leading axes of length one.
+ e.g.
+ Tile(x, 2, 3)
+ of an m by n matrix is the 2m by 3n matrix of copies of x
+*/
+func Tile(x *GsArray, reps ...int) *GsArray {
+	return must(TryTile(x, reps...))
+}
+
+/*
+ Same as Tile but returns a ShapeMismatchError instead of panicking when a
+ count is negative
+*/
+func TryTile(x *GsArray, reps ...int) (*GsArray, error) {
+	n := max(len(reps), len(x.shape))
+	xShape, xStrides := make([]int, n), make([]int, n)
+	counts := make([]int, n)
+	own := x.getStrides()
+	for i := 0; i < n; i++ {
+		xShape[i], counts[i] = 1, 1
+		if j := i - (n - len(x.shape)); j >= 0 {
+			xShape[i], xStrides[i] = x.shape[j], own[j]
+		}
+		if j := i - (n - len(reps)); j >= 0 {
+			if reps[j] < 0 {
+				return nil, shapeError("Tile", x.shape, reps)
+			}
+			counts[i] = reps[j]
+		}
+	}
+	// walk (count, length) pairs of axes, which in row major order visit the
+	// elements of the result in turn, with the copies sharing a stride of zero
+	walkShape := make([]int, 0, 2*n)
+	strides := make([]int, 0, 2*n)
+	shape := make([]int, n)
+	for i := 0; i < n; i++ {
+		walkShape = append(walkShape, counts[i], xShape[i])
+		strides = append(strides, 0, xStrides[i])
+		shape[i] = counts[i] * xShape[i]
+	}
+	return gatherStrided(x, shape, walkShape, strides), nil
+}
+
+/*
+ Returns x with each element repeated repeats times along axis
+ e.g.
+ Repeat(FromSlice([]float64{1, 2}, 2), 2, 0)
+ is [1, 1, 2, 2]
+*/
+func Repeat(x *GsArray, repeats, axis int) *GsArray {
+	return must(TryRepeat(x, repeats, axis))
+}
+
+/*
+ Same as Repeat but returns an InvalidAxisError or ShapeMismatchError instead
+ of panicking
+*/
+func TryRepeat(x *GsArray, repeats, axis int) (*GsArray, error) {
+	ax, err := checkAxis(axis, len(x.shape))
+	if err != nil {
+		return nil, err
+	}
+	if repeats < 0 {
+		return nil, shapeError("Repeat", x.shape, []int{repeats})
+	}
+	// walk the axis as (length, repeats) with the copies sharing a stride of zero
+	own := x.getStrides()
+	walkShape := make([]int, 0, len(x.shape)+1)
+	strides := make([]int, 0, len(x.shape)+1)
+	for i, dim := range x.shape {
+		walkShape = append(walkShape, dim)
+		strides = append(strides, own[i])
+		if i == ax {
+			walkShape = append(walkShape, repeats)
+			strides = append(strides, 0)
+		}
+	}
+	shape := append([]int(nil), x.shape...)
+	shape[ax] *= repeats
+	return gatherStrided(x, shape, walkShape, strides), nil
+}
+
+/*
+ Returns an array of shape shape holding the elements of x visited by walking
+ walkShape with strides from the offset of x
+*/
+func gatherStrided(x *GsArray, shape, walkShape, strides []int) *GsArray {
+	result := Zeros(shape...)
+	i := 0
+	walkStrided(walkShape, []int{x.offset}, [][]int{strides}, func(pos []int) {
+		result.data[i] = x.data[pos[0]]
+		i++
+	})
+	return result
+}
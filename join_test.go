@@ -0,0 +1,80 @@
+package goSci
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestJoin(t *testing.T) {
+	x := FromSlice([]float64{0, 1, 2, 3}, 2, 2)
+	y := FromSlice([]float64{4, 5}, 1, 2)
+
+	assertArray(t, "Concatenate axis 0", Concatenate(0, x, y), []int{3, 2}, []float64{0, 1, 2, 3, 4, 5})
+	assertArray(t, "Concatenate axis -1", Concatenate(-1, x, x.T()), []int{2, 4},
+		[]float64{0, 1, 0, 2, 2, 3, 1, 3})
+	assertArray(t, "Stack axis 1", Stack(1, x, x), []int{2, 2, 2}, []float64{0, 1, 0, 1, 2, 3, 2, 3})
+
+	v := FromSlice([]float64{1, 2}, 2)
+	assertArray(t, "HStack of vectors", HStack(v, v), []int{4}, []float64{1, 2, 1, 2})
+	assertArray(t, "HStack of matrices", HStack(x, x), []int{2, 4}, []float64{0, 1, 0, 1, 2, 3, 2, 3})
+	assertArray(t, "VStack", VStack(v, x), []int{3, 2}, []float64{1, 2, 0, 1, 2, 3})
+	assertArray(t, "DStack", DStack(v, v), []int{1, 2, 2}, []float64{1, 1, 2, 2})
+}
+
+func TestJoinErrors(t *testing.T) {
+	x := Zeros(2, 2)
+	if _, err := TryConcatenate(0, x, Zeros(2, 3)); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("Concatenate of mismatched shapes: got %v", err)
+	}
+	if _, err := TryStack(3, x, x); !errors.As(err, new(*InvalidAxisError)) {
+		t.Errorf("Stack along axis 3: got %v", err)
+	}
+	for name, err := range map[string]error{
+		"Concatenate": second(TryConcatenate(0)),
+		"Stack":       second(TryStack(0)),
+		"HStack":      second(TryHStack()),
+		"VStack":      second(TryVStack()),
+		"DStack":      second(TryDStack()),
+	} {
+		if !errors.Is(err, ErrNoArrays) {
+			t.Errorf("%s of no arrays: got %v", name, err)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	x := Arange(12)
+	x.Reshape(3, 4)
+
+	parts := Split(x, 2, 1)
+	assertArray(t, "Split", parts[1], []int{3, 2}, []float64{2, 3, 6, 7, 10, 11})
+	// the parts are views of x
+	parts[0].Put(-1, 0, 0)
+	if x.Get(0, 0) != -1 {
+		t.Error("Split did not return views")
+	}
+
+	parts = ArraySplit(Arange(5), 3, 0)
+	if len(parts) != 3 || parts[0].Size() != 2 || parts[1].Size() != 2 || parts[2].Size() != 1 {
+		t.Errorf("ArraySplit of 5 into 3: sizes %d, %d, %d", parts[0].Size(), parts[1].Size(), parts[2].Size())
+	}
+	parts = SplitAt(Arange(6), []int{2, 5}, 0)
+	assertArray(t, "SplitAt", parts[1], []int{3}, []float64{2, 3, 4})
+	assertArray(t, "SplitAt tail", parts[2], []int{1}, []float64{5})
+
+	if _, err := TrySplit(x, 3, 1); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("Split of 4 into 3: got %v", err)
+	}
+}
+
+func TestTileRepeat(t *testing.T) {
+	x := FromSlice([]float64{1, 2}, 2)
+	assertArray(t, "Tile", Tile(x, 2, 2), []int{2, 4}, []float64{1, 2, 1, 2, 1, 2, 1, 2})
+	assertArray(t, "Repeat", Repeat(x, 2, 0), []int{4}, []float64{1, 1, 2, 2})
+	m := FromSlice([]float64{1, 2, 3, 4}, 2, 2)
+	assertArray(t, "Tile of a transpose", Tile(m.T(), 1, 2), []int{2, 4}, []float64{1, 3, 1, 3, 2, 4, 2, 4})
+	assertArray(t, "Repeat axis 1", Repeat(m, 2, 1), []int{2, 4}, []float64{1, 1, 2, 2, 3, 3, 4, 4})
+	if _, err := TryTile(x, -1); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("Tile with a negative count: got %v", err)
+	}
+}
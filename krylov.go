@@ -0,0 +1,425 @@
+package goSci
+
+import "math"
+
+/*
+ A linear map applied by the iterative solvers, which only need to multiply
+ vectors by the matrix. GsArray implements it for dense matrices and the
+ types of the sparse package for sparse ones, and a matrix-free operator only
+ needs an Apply method. Preconditioners are LinearOperators that apply an
+ approximate inverse.
+*/
+type LinearOperator interface {
+	// Returns the product of the operator with the vector x
+	Apply(x *GsArray) (*GsArray, error)
+}
+
+/*
+ Returns the matrix product of the matrix x with v, a vector or a matrix,
+ so that a dense GsArray is a LinearOperator
+*/
+func (x *GsArray) Apply(v *GsArray) (*GsArray, error) {
+	if len(v.shape) != 1 {
+		return TryMatMult(x, v)
+	}
+	if len(x.shape) != 2 {
+		return nil, shapeError("Apply", x.shape, v.shape)
+	}
+	y := Zeros(x.shape[0])
+	return y, TryGemv(1, x, v, 0, y)
+}
+
+/*
+ The Jacobi, or diagonal, preconditioner, which divides by the diagonal of
+ the matrix
+*/
+type Jacobi struct {
+	inv []float64
+}
+
+/*
+ Returns the Jacobi preconditioner for a square matrix a, or for the matrix
+ whose diagonal is the vector a. Returns ErrSingular if the diagonal has a zero.
+*/
+func NewJacobi(a *GsArray) (*Jacobi, error) {
+	var diag []float64
+	switch {
+	case len(a.shape) == 1:
+		diag = a.Data()
+	case len(a.shape) == 2 && a.shape[0] == a.shape[1]:
+		diag = make([]float64, a.shape[0])
+		for i := range diag {
+			diag[i] = a.Get(i, i)
+		}
+	default:
+		return nil, shapeError("NewJacobi", a.shape, nil)
+	}
+	inv := make([]float64, len(diag))
+	for i, val := range diag {
+		if val == 0 {
+			return nil, ErrSingular
+		}
+		inv[i] = 1 / val
+	}
+	return &Jacobi{inv: inv}, nil
+}
+
+func (j *Jacobi) Apply(r *GsArray) (*GsArray, error) {
+	if len(r.shape) != 1 || r.shape[0] != len(j.inv) {
+		return nil, shapeError("Apply", []int{len(j.inv)}, r.shape)
+	}
+	z := r.Copy()
+	for i, val := range j.inv {
+		z.data[i] *= val
+	}
+	return z, nil
+}
+
+/*
+ Settings for CG, BiCGSTAB and GMRES. A nil *KrylovOptions, or a zero field,
+ selects the default.
+*/
+type KrylovOptions struct {
+	Tol     float64        // stop once the residual norm is at most Tol times the norm of b, default 1e-8
+	MaxIter int            // largest number of iterations, default 10 times the size of b
+	Precond LinearOperator // applies an approximate inverse of A, default none
+	X0      *GsArray       // starting guess, default zero
+	Restart int            // iterations between GMRES restarts, default min(n, 30)
+}
+
+/*
+ The outcome of an iterative solve
+*/
+type KrylovResult struct {
+	X          *GsArray  // the approximate solution
+	Iterations int       // number of iterations made
+	Converged  bool      // the tolerance was met
+	Residuals  []float64 // residual 2-norm before the first and after every iteration
+}
+
+/*
+ Solves A*x = b for a symmetric positive definite operator a by the
+ preconditioned conjugate gradient method. The preconditioner must also be
+ symmetric positive definite. If the tolerance is not met within the
+ iteration limit the result so far is returned with ErrNoConvergence.
+*/
+func CG(a LinearOperator, b *GsArray, opts *KrylovOptions) (*KrylovResult, error) {
+	k, err := newKrylov("CG", a, b, opts)
+	if err != nil || k.done {
+		return k.result, err
+	}
+	n := k.n
+	x, r := k.x, k.r
+	z, err := k.precondition(r)
+	if err != nil {
+		return nil, err
+	}
+	p := append([]float64(nil), z...)
+	rz := engine.Ddot(n, r, 1, z, 1)
+	for k.iterating() {
+		ap, err := k.apply(a, p)
+		if err != nil {
+			return nil, err
+		}
+		pap := engine.Ddot(n, p, 1, ap, 1)
+		if pap == 0 {
+			break
+		}
+		alpha := rz / pap
+		engine.Daxpy(n, alpha, p, 1, x, 1)
+		engine.Daxpy(n, -alpha, ap, 1, r, 1)
+		if k.record(engine.Dnrm2(n, r, 1)) {
+			break
+		}
+		if z, err = k.precondition(r); err != nil {
+			return nil, err
+		}
+		rzNew := engine.Ddot(n, r, 1, z, 1)
+		beta := rzNew / rz
+		rz = rzNew
+		for i := range p {
+			p[i] = z[i] + beta*p[i]
+		}
+	}
+	return k.finish()
+}
+
+/*
+ Solves A*x = b for a general square operator a by the stabilized
+ biconjugate gradient method, preconditioned on the right. If the tolerance
+ is not met within the iteration limit, or the method breaks down, the result
+ so far is returned with ErrNoConvergence.
+*/
+func BiCGSTAB(a LinearOperator, b *GsArray, opts *KrylovOptions) (*KrylovResult, error) {
+	k, err := newKrylov("BiCGSTAB", a, b, opts)
+	if err != nil || k.done {
+		return k.result, err
+	}
+	n := k.n
+	x, r := k.x, k.r
+	rHat := append([]float64(nil), r...)
+	p := make([]float64, n)
+	v := make([]float64, n)
+	s := make([]float64, n)
+	rho, alpha, omega := 1.0, 1.0, 1.0
+	for k.iterating() {
+		rhoNew := engine.Ddot(n, rHat, 1, r, 1)
+		if rhoNew == 0 || omega == 0 {
+			break
+		}
+		beta := (rhoNew / rho) * (alpha / omega)
+		rho = rhoNew
+		for i := range p {
+			p[i] = r[i] + beta*(p[i]-omega*v[i])
+		}
+		pHat, err := k.precondition(p)
+		if err != nil {
+			return nil, err
+		}
+		if v, err = k.apply(a, pHat); err != nil {
+			return nil, err
+		}
+		rv := engine.Ddot(n, rHat, 1, v, 1)
+		if rv == 0 {
+			break
+		}
+		alpha = rho / rv
+		for i := range s {
+			s[i] = r[i] - alpha*v[i]
+		}
+		engine.Daxpy(n, alpha, pHat, 1, x, 1)
+		if sNorm := engine.Dnrm2(n, s, 1); sNorm <= k.target {
+			copy(r, s)
+			k.record(sNorm)
+			break
+		}
+		sHat, err := k.precondition(s)
+		if err != nil {
+			return nil, err
+		}
+		t, err := k.apply(a, sHat)
+		if err != nil {
+			return nil, err
+		}
+		tt := engine.Ddot(n, t, 1, t, 1)
+		omega = 0
+		if tt != 0 {
+			omega = engine.Ddot(n, t, 1, s, 1) / tt
+		}
+		engine.Daxpy(n, omega, sHat, 1, x, 1)
+		for i := range r {
+			r[i] = s[i] - omega*t[i]
+		}
+		if k.record(engine.Dnrm2(n, r, 1)) {
+			break
+		}
+	}
+	return k.finish()
+}
+
+/*
+ Solves A*x = b for a general square operator a by the generalized minimal
+ residual method, preconditioned on the right and restarted every
+ opts.Restart iterations to bound its memory. Within a cycle the recorded
+ residuals are the norms GMRES minimizes, which equal the true residual norms
+ in exact arithmetic. If the tolerance is not met within the iteration limit
+ the result so far is returned with ErrNoConvergence.
+*/
+func GMRES(a LinearOperator, b *GsArray, opts *KrylovOptions) (*KrylovResult, error) {
+	k, err := newKrylov("GMRES", a, b, opts)
+	if err != nil || k.done {
+		return k.result, err
+	}
+	n := k.n
+	m := min(n, 30)
+	if opts != nil && opts.Restart > 0 {
+		m = opts.Restart
+	}
+	x, r := k.x, k.r
+	// Arnoldi basis V, preconditioned basis Z, Hessenberg matrix H reduced to
+	// triangular form by the Givens rotations (cs, sn) and the rotated residual g
+	v := make([][]float64, m+1)
+	z := make([][]float64, m)
+	h := make([]float64, (m+1)*m)
+	cs, sn := make([]float64, m), make([]float64, m)
+	g := make([]float64, m+1)
+	beta := engine.Dnrm2(n, r, 1)
+	for k.iterating() {
+		for i := range g {
+			g[i] = 0
+		}
+		g[0] = beta
+		v[0] = append(v[0][:0], r...)
+		engine.Dscal(n, 1/beta, v[0], 1)
+		j := 0
+		for j < m && k.iterating() {
+			if z[j], err = k.precondition(v[j]); err != nil {
+				return nil, err
+			}
+			w, err := k.apply(a, z[j])
+			if err != nil {
+				return nil, err
+			}
+			// modified Gram-Schmidt
+			for i := 0; i <= j; i++ {
+				h[i*m+j] = engine.Ddot(n, w, 1, v[i], 1)
+				engine.Daxpy(n, -h[i*m+j], v[i], 1, w, 1)
+			}
+			hNext := engine.Dnrm2(n, w, 1)
+			h[(j+1)*m+j] = hNext
+			if hNext != 0 {
+				engine.Dscal(n, 1/hNext, w, 1)
+			}
+			v[j+1] = w
+			for i := 0; i < j; i++ {
+				hi, hi1 := h[i*m+j], h[(i+1)*m+j]
+				h[i*m+j] = cs[i]*hi + sn[i]*hi1
+				h[(i+1)*m+j] = -sn[i]*hi + cs[i]*hi1
+			}
+			rho := math.Hypot(h[j*m+j], hNext)
+			if rho == 0 {
+				break
+			}
+			cs[j], sn[j] = h[j*m+j]/rho, hNext/rho
+			h[j*m+j] = rho
+			h[(j+1)*m+j] = 0
+			g[j+1] = -sn[j] * g[j]
+			g[j] *= cs[j]
+			j++
+			if k.record(math.Abs(g[j])) || hNext == 0 {
+				break
+			}
+		}
+		// x += Z*y where H*y = g
+		engine.Dtrsv(Upper, false, NonUnit, j, h, max(m, 1), g, 1)
+		for i := 0; i < j; i++ {
+			engine.Daxpy(n, g[i], z[i], 1, x, 1)
+		}
+		if j == 0 {
+			break
+		}
+		ax, err := k.apply(a, x)
+		if err != nil {
+			return nil, err
+		}
+		for i := range r {
+			r[i] = k.b[i] - ax[i]
+		}
+		// the estimate within the cycle can drift from the true residual
+		beta = engine.Dnrm2(n, r, 1)
+		k.converged = beta <= k.target
+	}
+	return k.finish()
+}
+
+/*
+ The state shared by the Krylov solvers
+*/
+type krylov struct {
+	n         int
+	b, x, r   []float64
+	target    float64 // residual norm at which to stop
+	maxIter   int
+	precond   LinearOperator
+	converged bool
+	done      bool // the starting guess already solves the system
+	result    *KrylovResult
+}
+
+/*
+ Checks the arguments and computes the starting residual r = b - A*x0
+*/
+func newKrylov(op string, a LinearOperator, b *GsArray, opts *KrylovOptions) (*krylov, error) {
+	if len(b.shape) != 1 {
+		return &krylov{}, shapeError(op, b.shape, nil)
+	}
+	n := b.shape[0]
+	k := &krylov{n: n, b: b.Data(), maxIter: 10 * n}
+	tol := 1e-8
+	if opts != nil {
+		if opts.Tol > 0 {
+			tol = opts.Tol
+		}
+		if opts.MaxIter > 0 {
+			k.maxIter = opts.MaxIter
+		}
+		k.precond = opts.Precond
+	}
+	k.x = make([]float64, n)
+	if opts != nil && opts.X0 != nil {
+		if len(opts.X0.shape) != 1 || opts.X0.shape[0] != n {
+			return &krylov{}, shapeError(op, b.shape, opts.X0.shape)
+		}
+		k.x = opts.X0.Data()
+	}
+	k.result = &KrylovResult{}
+	k.target = tol * engine.Dnrm2(n, k.b, 1)
+	ax, err := k.apply(a, k.x)
+	if err != nil {
+		return &krylov{}, err
+	}
+	k.r = make([]float64, n)
+	for i := range k.r {
+		k.r[i] = k.b[i] - ax[i]
+	}
+	if k.record(engine.Dnrm2(n, k.r, 1)) {
+		k.done = true
+		k.result.Iterations = 0
+		k.result.X = FromSlice(k.x, n)
+	}
+	return k, nil
+}
+
+/*
+ Returns a copy of A*x for the operator a, checking the length of the result
+*/
+func (k *krylov) apply(a LinearOperator, x []float64) ([]float64, error) {
+	y, err := a.Apply(FromSlice(x, k.n))
+	if err != nil {
+		return nil, err
+	}
+	if len(y.shape) != 1 || y.shape[0] != k.n {
+		return nil, shapeError("Apply", []int{k.n}, y.shape)
+	}
+	return y.Data(), nil
+}
+
+/*
+ Returns the preconditioned r, a copy of r without a preconditioner
+*/
+func (k *krylov) precondition(r []float64) ([]float64, error) {
+	if k.precond == nil {
+		return append([]float64(nil), r...), nil
+	}
+	return k.apply(k.precond, r)
+}
+
+/*
+ Reports whether another iteration may be made
+*/
+func (k *krylov) iterating() bool {
+	return !k.converged && k.result.Iterations < k.maxIter
+}
+
+/*
+ Appends the residual norm of an iteration to the history and reports whether
+ it meets the tolerance. The first call records the starting residual.
+*/
+func (k *krylov) record(norm float64) bool {
+	if len(k.result.Residuals) > 0 {
+		k.result.Iterations++
+	}
+	k.result.Residuals = append(k.result.Residuals, norm)
+	k.converged = norm <= k.target
+	k.result.Converged = k.converged
+	return k.converged
+}
+
+func (k *krylov) finish() (*KrylovResult, error) {
+	k.result.X = FromSlice(k.x, k.n)
+	k.result.Converged = k.converged
+	if !k.converged {
+		return k.result, ErrNoConvergence
+	}
+	return k.result, nil
+}
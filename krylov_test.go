@@ -0,0 +1,65 @@
+package goSci
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+/*
+ Returns |A*x - b| / |b|
+*/
+func relativeResidual(a, x, b *GsArray) float64 {
+	r := Minus(MatMult(a, FromSlice(x.Data(), x.Size(), 1)), FromSlice(b.Data(), b.Size(), 1))
+	return math.Sqrt(Dot(r.Slice(All, At(0)), r.Slice(All, At(0))) / Dot(b, b))
+}
+
+func TestKrylovSolvers(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	n := 40
+	g := randomArray(r, n, n)
+	spd := Plus(MatMult(g, g.T()), Times(Eye(n), float64(n)))
+	nonsym := Plus(g, Times(Eye(n), 2*math.Sqrt(float64(n))))
+	b := randomArray(r, n)
+	jacobi, err := NewJacobi(nonsym)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name  string
+		a     *GsArray
+		solve func(LinearOperator, *GsArray, *KrylovOptions) (*KrylovResult, error)
+		opts  *KrylovOptions
+	}{
+		{"CG", spd, CG, nil},
+		{"BiCGSTAB", nonsym, BiCGSTAB, nil},
+		{"BiCGSTAB Jacobi", nonsym, BiCGSTAB, &KrylovOptions{Precond: jacobi}},
+		{"GMRES", nonsym, GMRES, nil},
+		{"GMRES restarted", nonsym, GMRES, &KrylovOptions{Restart: 5, MaxIter: 1000}},
+		{"GMRES Jacobi", nonsym, GMRES, &KrylovOptions{Precond: jacobi, Tol: 1e-12}},
+	}
+	for _, c := range cases {
+		res, err := c.solve(c.a, b, c.opts)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		tol := 1e-8
+		if c.opts != nil && c.opts.Tol > 0 {
+			tol = c.opts.Tol
+		}
+		if !res.Converged || relativeResidual(c.a, res.X, b) > 2*tol {
+			t.Errorf("%s: residual %g after %d iterations", c.name, relativeResidual(c.a, res.X, b), res.Iterations)
+		}
+		if len(res.Residuals) != res.Iterations+1 {
+			t.Errorf("%s: %d residuals for %d iterations", c.name, len(res.Residuals), res.Iterations)
+		}
+	}
+
+	res, err := CG(spd, b, &KrylovOptions{MaxIter: 2})
+	if !errors.Is(err, ErrNoConvergence) || res == nil || res.Converged || res.Iterations != 2 {
+		t.Errorf("CG with 2 iterations: got %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package goSci
+
+/*
+ The LAPACK routines used by goSci's decompositions. As with Blas, matrices
+ are row major with the given leading dimension and pivot indices are zero
+ based. GoLapack is a pure go implementation, CLapack (built with -tags
+ atlas) calls ATLAS's clapack interface and other backends can be installed
+ with SetLapack.
+*/
+type Lapack interface {
+	// LU factorization with partial pivoting of the m by n matrix A, stored in
+	// place as the unit lower triangular L and upper triangular U. Row i was
+	// interchanged with row ipiv[i]. Returns false if U has a zero on its diagonal.
+	Dgetrf(m, n int, a []float64, lda int, ipiv []int) bool
+	// Solves op(A)*X = B in place of B using the factorization from Dgetrf, where B is n by nrhs
+	Dgetrs(trans bool, n, nrhs int, a []float64, lda int, ipiv []int, b []float64, ldb int)
+	// Cholesky factorization of the symmetric positive definite n by n matrix A
+	// held in its uplo triangle, A = L*L^T for Lower or U^T*U for Upper, stored
+	// in place of that triangle. Returns false if A is not positive definite.
+	Dpotrf(uplo Uplo, n int, a []float64, lda int) bool
+	// Solves A*X = B in place of B using the factorization from Dpotrf, where B is n by nrhs
+	Dpotrs(uplo Uplo, n, nrhs int, a []float64, lda int, b []float64, ldb int)
+}
+
+/*
+ The backend used by the decompositions in the package
+*/
+var lapackEngine Lapack = GoLapack{}
+
+/*
+ Installs l as the LAPACK backend and returns the previous one
+*/
+func SetLapack(l Lapack) Lapack {
+	previous := lapackEngine
+	lapackEngine = l
+	return previous
+}
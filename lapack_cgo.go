@@ -0,0 +1,89 @@
+//go:build cgo && atlas
+
+package goSci
+
+/*
+  #cgo LDFLAGS: -llapack_atlas -lblas
+  #include <atlas/clapack.h>
+*/
+import "C"
+
+/*
+ Implementation of Lapack backed by ATLAS's clapack interface. Building with
+ -tags atlas makes it the default backend.
+ ATLAS pivots columns rather than rows when factoring a row major matrix, so
+ matrices are transposed into column major order around each call to keep the
+ conventions of the Lapack interface.
+*/
+type CLapack struct{}
+
+func init() {
+	lapackEngine = CLapack{}
+}
+
+/*
+ Copies the rows by cols row major matrix in a to column major order and back
+*/
+func toColMajor(rows, cols int, a []float64, lda int) []float64 {
+	t := make([]float64, rows*cols)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			t[j*rows+i] = a[i*lda+j]
+		}
+	}
+	return t
+}
+
+func fromColMajor(rows, cols int, t []float64, a []float64, lda int) {
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			a[i*lda+j] = t[j*rows+i]
+		}
+	}
+}
+
+func (CLapack) Dgetrf(m, n int, a []float64, lda int, ipiv []int) bool {
+	if m == 0 || n == 0 {
+		return true
+	}
+	t := toColMajor(m, n, a, lda)
+	cpiv := make([]C.int, min(m, n))
+	info := C.clapack_dgetrf(C.CblasColMajor, C.int(m), C.int(n), cptr(t), C.int(m), &cpiv[0])
+	fromColMajor(m, n, t, a, lda)
+	for i, p := range cpiv {
+		ipiv[i] = int(p)
+	}
+	return info == 0
+}
+
+func (CLapack) Dgetrs(trans bool, n, nrhs int, a []float64, lda int, ipiv []int, b []float64, ldb int) {
+	if n == 0 || nrhs == 0 {
+		return
+	}
+	t := toColMajor(n, n, a, lda)
+	tb := toColMajor(n, nrhs, b, ldb)
+	cpiv := make([]C.int, n)
+	for i, p := range ipiv[:n] {
+		cpiv[i] = C.int(p)
+	}
+	C.clapack_dgetrs(C.CblasColMajor, cblasTrans(trans), C.int(n), C.int(nrhs), cptr(t), C.int(n), &cpiv[0], cptr(tb), C.int(n))
+	fromColMajor(n, nrhs, tb, b, ldb)
+}
+
+/*
+ The Cholesky routines have no pivoting, so ATLAS's row major support is used
+ directly
+*/
+func (CLapack) Dpotrf(uplo Uplo, n int, a []float64, lda int) bool {
+	if n == 0 {
+		return true
+	}
+	return C.clapack_dpotrf(C.CblasRowMajor, cblasUplo(uplo), C.int(n), cptr(a), C.int(lda)) == 0
+}
+
+func (CLapack) Dpotrs(uplo Uplo, n, nrhs int, a []float64, lda int, b []float64, ldb int) {
+	if n == 0 || nrhs == 0 {
+		return
+	}
+	C.clapack_dpotrs(C.CblasRowMajor, cblasUplo(uplo), C.int(n), C.int(nrhs), cptr(a), C.int(lda), cptr(b), C.int(ldb))
+}
@@ -0,0 +1,110 @@
+package goSci
+
+import "math"
+
+/*
+ Pure go implementation of Lapack built on the installed Blas backend, used
+ unless the package is built with -tags atlas
+*/
+type GoLapack struct{}
+
+/*
+ Right looking elimination: for each column the largest remaining element is
+ swapped onto the diagonal, the column below it scaled into L and the outer
+ product subtracted from the trailing submatrix.
+*/
+func (GoLapack) Dgetrf(m, n int, a []float64, lda int, ipiv []int) bool {
+	nonSingular := true
+	for k := 0; k < min(m, n); k++ {
+		p := k + engine.Idamax(m-k, a[k*lda+k:], lda)
+		ipiv[k] = p
+		if a[p*lda+k] == 0 {
+			nonSingular = false
+			continue
+		}
+		if p != k {
+			swapRows(a, lda, n, k, p)
+		}
+		if k+1 == m {
+			continue
+		}
+		engine.Dscal(m-k-1, 1/a[k*lda+k], a[(k+1)*lda+k:], lda)
+		if k+1 < n {
+			engine.Dger(m-k-1, n-k-1, -1, a[(k+1)*lda+k:], lda, a[k*lda+k+1:], 1, a[(k+1)*lda+k+1:], lda)
+		}
+	}
+	return nonSingular
+}
+
+/*
+ A = P*L*U so A*X = B is L*U*X = P^T*B and A^T*X = B is U^T*L^T*(P^T*X) = B
+*/
+func (GoLapack) Dgetrs(trans bool, n, nrhs int, a []float64, lda int, ipiv []int, b []float64, ldb int) {
+	if !trans {
+		for i := 0; i < n; i++ {
+			if ipiv[i] != i {
+				swapRows(b, ldb, nrhs, i, ipiv[i])
+			}
+		}
+		engine.Dtrsm(Left, Lower, false, Unit, n, nrhs, 1, a, lda, b, ldb)
+		engine.Dtrsm(Left, Upper, false, NonUnit, n, nrhs, 1, a, lda, b, ldb)
+		return
+	}
+	engine.Dtrsm(Left, Upper, true, NonUnit, n, nrhs, 1, a, lda, b, ldb)
+	engine.Dtrsm(Left, Lower, true, Unit, n, nrhs, 1, a, lda, b, ldb)
+	for i := n - 1; i >= 0; i-- {
+		if ipiv[i] != i {
+			swapRows(b, ldb, nrhs, i, ipiv[i])
+		}
+	}
+}
+
+/*
+ Interchanges the first n elements of rows i and j of a
+*/
+func swapRows(a []float64, lda, n, i, j int) {
+	rowI := a[i*lda : i*lda+n]
+	rowJ := a[j*lda : j*lda+n]
+	for k := range rowI {
+		rowI[k], rowJ[k] = rowJ[k], rowI[k]
+	}
+}
+
+/*
+ Column by column: each diagonal element is found from the rows already
+ factored and the rest of its column of L, or row of U, by one Dgemv.
+*/
+func (GoLapack) Dpotrf(uplo Uplo, n int, a []float64, lda int) bool {
+	for j := 0; j < n; j++ {
+		var d float64
+		if uplo == Lower {
+			d = a[j*lda+j] - engine.Ddot(j, a[j*lda:], 1, a[j*lda:], 1)
+		} else {
+			d = a[j*lda+j] - engine.Ddot(j, a[j:], lda, a[j:], lda)
+		}
+		if !(d > 0) {
+			return false
+		}
+		d = math.Sqrt(d)
+		a[j*lda+j] = d
+		if j+1 == n {
+			continue
+		}
+		if uplo == Lower {
+			engine.Dgemv(false, n-j-1, j, -1, a[(j+1)*lda:], lda, a[j*lda:], 1, 1, a[(j+1)*lda+j:], lda)
+			engine.Dscal(n-j-1, 1/d, a[(j+1)*lda+j:], lda)
+		} else {
+			engine.Dgemv(true, j, n-j-1, -1, a[j+1:], lda, a[j:], lda, 1, a[j*lda+j+1:], 1)
+			engine.Dscal(n-j-1, 1/d, a[j*lda+j+1:], 1)
+		}
+	}
+	return true
+}
+
+/*
+ A = L*L^T so A*X = B is two triangular solves, with L and then L^T
+*/
+func (GoLapack) Dpotrs(uplo Uplo, n, nrhs int, a []float64, lda int, b []float64, ldb int) {
+	engine.Dtrsm(Left, uplo, uplo == Upper, NonUnit, n, nrhs, 1, a, lda, b, ldb)
+	engine.Dtrsm(Left, uplo, uplo == Lower, NonUnit, n, nrhs, 1, a, lda, b, ldb)
+}
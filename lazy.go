@@ -0,0 +1,288 @@
+package goSci
+
+import "math"
+
+/*
+ An elementwise expression over GsArrays that is evaluated lazily. Arithmetic
+ on an Expr only records the operation; Eval then computes the whole
+ expression in a single pass over blocks of the result, holding one block per
+ operation rather than a full temporary array per step. Operands are broadcast
+ as in Plus, and shape errors are kept in the Expr until it is evaluated.
+ e.g.
+ d := Lazy(x).Minus(Lazy(mean))
+ sq := d.ElemTimes(d).Eval()
+ allocates only the result, with d computed once per block.
+*/
+type Expr struct {
+	shape  []int
+	err    error
+	array  *GsArray
+	value  float64
+	unary  func(float64) float64
+	binary func(a, b float64) float64
+	args   []*Expr
+}
+
+/*
+ Returns the expression holding x. x is read when the expression is
+ evaluated, not when it is built, so changes to x in between are seen.
+*/
+func Lazy(x *GsArray) *Expr {
+	return &Expr{shape: x.Shape(), array: x}
+}
+
+/*
+ Returns the expression holding the scalar val, which broadcasts against any
+ shape
+*/
+func Const(val float64) *Expr {
+	return &Expr{shape: []int{}, value: val}
+}
+
+/*
+ Returns the shape the expression evaluates to
+*/
+func (e *Expr) Shape() []int {
+	return append([]int(nil), e.shape...)
+}
+
+/*
+ Returns the expression e + y
+*/
+func (e *Expr) Plus(y *Expr) *Expr {
+	return e.combine("Plus", y, add)
+}
+
+/*
+ Returns the expression e - y
+*/
+func (e *Expr) Minus(y *Expr) *Expr {
+	return e.combine("Minus", y, sub)
+}
+
+/*
+ Returns the expression of the element wise multiplication of e and y
+*/
+func (e *Expr) ElemTimes(y *Expr) *Expr {
+	return e.combine("ElemTimes", y, mul)
+}
+
+/*
+ Returns the expression of the element wise division of e by y
+*/
+func (e *Expr) Divide(y *Expr) *Expr {
+	return e.combine("Divide", y, div)
+}
+
+/*
+ Returns the expression of e raised element wise to the power y
+*/
+func (e *Expr) Pow(y *Expr) *Expr {
+	return e.combine("Pow", y, math.Pow)
+}
+
+/*
+ Returns the expression a*e where a is a scalar
+*/
+func (e *Expr) Times(a float64) *Expr {
+	return e.Apply(func(val float64) float64 { return val * a })
+}
+
+/*
+ Returns the expression of f applied to every element of e, as ArrayFun does.
+ f may be called concurrently for large expressions.
+*/
+func (e *Expr) Apply(f func(float64) float64) *Expr {
+	return &Expr{shape: e.shape, err: e.err, unary: f, args: []*Expr{e}}
+}
+
+/*
+ Returns the expression of op applied element wise to e and y, as Maximum,
+ Hypot and the other binary functions do
+*/
+func (e *Expr) Apply2(y *Expr, op func(a, b float64) float64) *Expr {
+	return e.combine("Apply2", y, op)
+}
+
+func (e *Expr) combine(name string, y *Expr, op func(a, b float64) float64) *Expr {
+	shape, ok := broadcastShapes(e.shape, y.shape)
+	err := e.err
+	if err == nil {
+		err = y.err
+	}
+	if err == nil && !ok {
+		err = shapeError(name, e.shape, y.shape)
+	}
+	return &Expr{shape: shape, err: err, binary: op, args: []*Expr{e, y}}
+}
+
+/*
+ Computes the expression. Panics if the shapes of its operands do not
+ broadcast.
+*/
+func (e *Expr) Eval() *GsArray {
+	return must(e.TryEval())
+}
+
+/*
+ Same as Eval but returns a ShapeMismatchError instead of panicking
+*/
+func (e *Expr) TryEval() (*GsArray, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	result := Zeros(e.shape...)
+	e.run(func(_, start int, vals []float64) {
+		copy(result.data[start:], vals)
+	})
+	return result, nil
+}
+
+/*
+ Returns the sum of the elements of the expression without storing them. The
+ sum is folded as SumAxis folds it, so it is the same as the sum of Eval().
+*/
+func (e *Expr) Sum() float64 {
+	return must(e.TrySum())
+}
+
+/*
+ Same as Sum but returns a ShapeMismatchError instead of panicking
+*/
+func (e *Expr) TrySum() (float64, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	partials := make([]float64, (shapeSize(e.shape)+reduceBlock-1)/reduceBlock)
+	e.run(func(block, _ int, vals []float64) {
+		acc := 0.0
+		for _, val := range vals {
+			acc += val
+		}
+		partials[block] = acc
+	})
+	acc := 0.0
+	for _, partial := range partials {
+		acc += partial
+	}
+	return acc, nil
+}
+
+/*
+ One operation of a compiled expression: the result of step k is kept in
+ register k and args are the registers of its operands
+*/
+type exprStep struct {
+	expr    *Expr
+	args    []int
+	strides []int
+	// the operand is packed with the shape of the result, so its register
+	// is a slice of its data
+	direct bool
+}
+
+/*
+ Returns the operations of the expression in an order where each one follows
+ its operands, with an operand used more than once computed once
+*/
+func (e *Expr) compile() []exprStep {
+	var steps []exprStep
+	seen := make(map[*Expr]int)
+	var visit func(x *Expr) int
+	visit = func(x *Expr) int {
+		if k, ok := seen[x]; ok {
+			return k
+		}
+		step := exprStep{expr: x}
+		for _, arg := range x.args {
+			step.args = append(step.args, visit(arg))
+		}
+		if x.array != nil {
+			step.direct = x.array.strides == nil && sameShape(x.array.shape, e.shape)
+			step.strides = broadcastStrides(x.array, e.shape)
+		}
+		seen[x] = len(steps)
+		steps = append(steps, step)
+		return seen[x]
+	}
+	visit(e)
+	return steps
+}
+
+/*
+ Evaluates the expression a block of reduceBlock elements of the result at a
+ time, in row major order, passing emit the index of each block, the index of
+ its first element and its values. Blocks are split across the worker pool,
+ so emit may be called concurrently for different blocks.
+*/
+func (e *Expr) run(emit func(block, start int, vals []float64)) {
+	steps := e.compile()
+	size := shapeSize(e.shape)
+	blocks := (size + reduceBlock - 1) / reduceBlock
+	parallelFor(blocks, size*len(steps), func(first, last int) {
+		regs := make([][]float64, len(steps))
+		bufs := make([][]float64, len(steps))
+		for k, step := range steps {
+			if !step.direct {
+				bufs[k] = make([]float64, reduceBlock)
+			}
+		}
+		for b := first; b < last; b++ {
+			start := b * reduceBlock
+			n := min(reduceBlock, size-start)
+			for k, step := range steps {
+				x := step.expr
+				if step.direct {
+					regs[k] = x.array.data[start : start+n]
+					continue
+				}
+				out := bufs[k][:n]
+				switch {
+				case x.array != nil:
+					gatherBlock(out, x.array, step.strides, e.shape, start)
+				case x.unary != nil:
+					for i, val := range regs[step.args[0]] {
+						out[i] = x.unary(val)
+					}
+				case x.binary != nil:
+					a, c := regs[step.args[0]], regs[step.args[1]]
+					for i := range out {
+						out[i] = x.binary(a[i], c[i])
+					}
+				default:
+					for i := range out {
+						out[i] = x.value
+					}
+				}
+				regs[k] = out
+			}
+			emit(b, start, regs[len(steps)-1])
+		}
+	})
+}
+
+/*
+ Fills dst with the elements of x read with strides as an array of shape
+ shape, starting from the element at row major index start
+*/
+func gatherBlock(dst []float64, x *GsArray, strides, shape []int, start int) {
+	counter := make([]int, len(shape))
+	pos := x.offset
+	for d, idx := len(shape)-1, start; d >= 0; d-- {
+		counter[d] = idx % shape[d]
+		idx /= shape[d]
+		pos += counter[d] * strides[d]
+	}
+	for i := range dst {
+		dst[i] = x.data[pos]
+		for d := len(shape) - 1; d >= 0; d-- {
+			counter[d]++
+			pos += strides[d]
+			if counter[d] < shape[d] {
+				break
+			}
+			pos -= strides[d] * shape[d]
+			counter[d] = 0
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package goSci
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestLazyEval(t *testing.T) {
+	x := Arange(12)
+	x.Reshape(3, 4)
+	y := FromSlice([]float64{1, 2, 3, 4}, 4)
+
+	e := Lazy(x).Plus(Lazy(y)).Times(2).Minus(Lazy(x)).Apply(math.Sqrt)
+	assertClose(t, "broadcast expression", e.Eval(), ArrayFun(Minus(Times(Plus(x, y), 2), x), math.Sqrt), 0)
+
+	// a transposed operand and a column broadcast against it
+	xt := x.T()
+	col := FromSlice([]float64{1, 2, 3, 4}, 4, 1)
+	assertClose(t, "transposed operand", Lazy(xt).Divide(Lazy(col)).Pow(Const(2)).Eval(),
+		Pow(Divide(xt, col), FromSlice([]float64{2}, 1)), 0)
+	assertClose(t, "Apply2", Lazy(xt).Apply2(Lazy(col), math.Max).Eval(), Maximum(xt, col), 0)
+	if got := Lazy(x).ElemTimes(Lazy(y)).Shape(); !sameShape(got, []int{3, 4}) {
+		t.Errorf("Shape: got %v", got)
+	}
+
+	// the operands are read when the expression is evaluated
+	sq := Lazy(y).ElemTimes(Lazy(y))
+	y.Put(10, 0)
+	assertArray(t, "late read", sq.Eval(), []int{4}, []float64{100, 4, 9, 16})
+
+	_, err := Lazy(x).Plus(Lazy(Ones(5))).Times(3).TryEval()
+	var sm *ShapeMismatchError
+	if !errors.As(err, &sm) || sm.Op != "Plus" {
+		t.Errorf("TryEval of mismatched shapes: got %v", err)
+	}
+	if _, err := Const(1).Minus(Lazy(Ones(2))).ElemTimes(Lazy(Ones(3))).TrySum(); !errors.As(err, &sm) || sm.Op != "ElemTimes" {
+		t.Errorf("TrySum of mismatched shapes: got %v", err)
+	}
+}
+
+func TestLazyLarge(t *testing.T) {
+	r := rand.New(rand.NewSource(24))
+	m := randomArray(r, 600, 500)
+	row := randomArray(r, 500)
+
+	// spans many blocks, with a node shared by two operations
+	d := Lazy(m.T()).Minus(Lazy(reshaped(row, []int{500, 1})))
+	got := d.ElemTimes(d).Plus(d)
+	diff := Minus(m.T(), reshaped(row, []int{500, 1}))
+	want := Plus(ElemTimes(diff, diff), diff)
+	assertClose(t, "large expression", got.Eval(), want, 0)
+
+	// the fused sum folds as Sum does
+	sum := got.Sum()
+	if math.Float64bits(sum) != math.Float64bits(Sum(got.Eval(), ALL).data[0]) {
+		t.Errorf("Sum %v differs from the sum of Eval %v", sum, Sum(got.Eval(), ALL).data[0])
+	}
+	if math.Abs(sum-Sum(want, ALL).data[0]) > 1e-9*math.Abs(sum) {
+		t.Errorf("Sum %v, eager sum %v", sum, Sum(want, ALL).data[0])
+	}
+}
+
+func TestStdev(t *testing.T) {
+	// Stdev evaluates the squared deviations lazily
+	a := FromSlice([]float64{1, 2, 3, 4, 5, 7}, 3, 2)
+	want := []float64{math.Sqrt(8.0 / 3), math.Sqrt(38.0 / 9)}
+	for i, got := range Stdev(a, COLS).Data() {
+		if math.Abs(got-want[i]) > 1e-12 {
+			t.Errorf("Stdev of column %d: got %v, want %v", i, got, want[i])
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package goSci
+
+import "math"
+
+/*
+ The result of a least squares solve, see Lstsq
+*/
+type LstsqResult struct {
+	X         *GsArray // the minimum norm solution, shaped like b with n rows
+	Residuals *GsArray // squared 2-norm of each column of b - a*X, empty unless a has full column rank and more rows than columns
+	Rank      int      // effective rank of a
+	S         *GsArray // singular values of a in decreasing order
+}
+
+/*
+ Returns the x of smallest 2-norm minimizing the 2-norm of a*x - b for any m by
+ n matrix a. b may be a vector or a matrix whose columns are separate right
+ hand sides. Singular values below rcond times the largest are treated as
+ zero when finding the rank; rcond <= 0 uses machine epsilon times max(m, n).
+ Tall problems are first reduced with a column pivoted QR decomposition.
+ Returns ErrNoConvergence if the singular values of a cannot be found.
+*/
+func Lstsq(a, b *GsArray, rcond float64) (*LstsqResult, error) {
+	if len(a.shape) != 2 || len(b.shape) == 0 || len(b.shape) > 2 || b.shape[0] != a.shape[0] {
+		return nil, shapeError("Lstsq", a.shape, b.shape)
+	}
+	m, n := a.shape[0], a.shape[1]
+	if rcond <= 0 {
+		rcond = 2.220446049250313e-16 * float64(max(m, n))
+	}
+	nrhs := 1
+	if len(b.shape) == 2 {
+		nrhs = b.shape[1]
+	}
+	res := &LstsqResult{X: Zeros(n, nrhs), Residuals: Zeros(0)}
+	if m >= n {
+		f, err := NewQRPivot(a)
+		if err != nil {
+			return nil, err
+		}
+		c := f.applyQT(b)
+		// R is n by n, R = U*S*V^T, so z = V*S^+*U^T*c[:n] solves R*z = c[:n]
+		r := f.R(true)
+		u, s, v, ok := jacobiSVD(n, n, r.data, max(n, 1))
+		if !ok {
+			return nil, ErrNoConvergence
+		}
+		res.Rank = svdRank(s, rcond)
+		z := pinvApply(n, n, u, s, v, res.Rank, c.data[:n*nrhs], nrhs)
+		f.unpermute(z, res.X.data, nrhs)
+		if res.Rank == n && m > n {
+			res.Residuals = Zeros(nrhs)
+			for i := n; i < m; i++ {
+				for j := 0; j < nrhs; j++ {
+					val := c.data[i*nrhs+j]
+					res.Residuals.data[j] += val * val
+				}
+			}
+		}
+		res.S = &GsArray{data: s, shape: []int{len(s)}}
+	} else {
+		// a^T = U*S*V^T is tall, so a = V*S*U^T and x = U*S^+*V^T*b
+		at := a.T().Copy()
+		u, s, v, ok := jacobiSVD(n, m, at.data, max(m, 1))
+		if !ok {
+			return nil, ErrNoConvergence
+		}
+		res.Rank = svdRank(s, rcond)
+		res.X.data = pinvApply(m, n, v, s, u, res.Rank, b.Copy().data, nrhs)
+		res.S = &GsArray{data: s, shape: []int{len(s)}}
+	}
+	if len(b.shape) == 1 {
+		res.X.shape = []int{n}
+		if res.Residuals.Size() == 1 {
+			res.Residuals.shape = []int{1}
+		}
+	}
+	return res, nil
+}
+
+/*
+ Returns the number of singular values in s, sorted in decreasing order, that
+ are greater than rcond times the largest
+*/
+func svdRank(s []float64, rcond float64) int {
+	if len(s) == 0 {
+		return 0
+	}
+	tol := rcond * s[0]
+	rank := 0
+	for rank < len(s) && s[rank] > tol && !math.IsNaN(s[rank]) {
+		rank++
+	}
+	return rank
+}
+
+/*
+ Returns V*S^+*U^T*b for the m by n matrix U*S*V^T given as U (m by k), the k
+ singular values s and V (n by k), keeping only the first rank singular
+ values. b is m by nrhs and the result n by nrhs, row major.
+*/
+func pinvApply(m, n int, u, s, v []float64, rank int, b []float64, nrhs int) []float64 {
+	k := len(s)
+	y := make([]float64, max(rank*nrhs, 1))
+	engine.Dgemm(true, false, rank, nrhs, m, 1, u, max(k, 1), b, max(nrhs, 1), 0, y, max(nrhs, 1))
+	for i := 0; i < rank; i++ {
+		engine.Dscal(nrhs, 1/s[i], y[i*nrhs:], 1)
+	}
+	x := make([]float64, n*nrhs)
+	engine.Dgemm(false, false, n, nrhs, rank, 1, v, max(k, 1), y, max(nrhs, 1), 0, x, max(nrhs, 1))
+	return x
+}
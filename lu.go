@@ -0,0 +1,146 @@
+package goSci
+
+/*
+ The LU decomposition with partial pivoting of a square matrix, A = P*L*U
+ where P is a permutation, L is unit lower triangular and U upper triangular.
+*/
+type LU struct {
+	lu       *GsArray // L below the diagonal and U on and above it
+	pivots   []int    // row i was interchanged with row pivots[i]
+	singular bool     // U has a zero on its diagonal
+}
+
+/*
+ Returns the LU decomposition of the square matrix a.
+ The factorization always succeeds for square matrices; a singular matrix is
+ reported by Solve and Inv.
+*/
+func NewLU(a *GsArray) (*LU, error) {
+	if len(a.shape) != 2 || a.shape[0] != a.shape[1] {
+		return nil, shapeError("NewLU", a.shape, nil)
+	}
+	n := a.shape[0]
+	f := &LU{lu: a.Copy(), pivots: make([]int, n)}
+	f.singular = !lapackEngine.Dgetrf(n, n, f.lu.data, max(n, 1), f.pivots)
+	return f, nil
+}
+
+/*
+ Returns the unit lower triangular factor L
+*/
+func (f *LU) L() *GsArray {
+	n := f.lu.shape[0]
+	l := Eye(n)
+	for i := 0; i < n; i++ {
+		copy(l.data[i*n:i*n+i], f.lu.data[i*n:i*n+i])
+	}
+	return l
+}
+
+/*
+ Returns the upper triangular factor U
+*/
+func (f *LU) U() *GsArray {
+	n := f.lu.shape[0]
+	u := Zeros(n, n)
+	for i := 0; i < n; i++ {
+		copy(u.data[i*n+i:(i+1)*n], f.lu.data[i*n+i:(i+1)*n])
+	}
+	return u
+}
+
+/*
+ Returns the permutation matrix P
+*/
+func (f *LU) P() *GsArray {
+	n := f.lu.shape[0]
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	for i, p := range f.pivots {
+		perm[i], perm[p] = perm[p], perm[i]
+	}
+	p := Zeros(n, n)
+	for i, row := range perm {
+		p.data[row*n+i] = 1
+	}
+	return p
+}
+
+/*
+ Returns the determinant of A
+*/
+func (f *LU) Det() float64 {
+	n := f.lu.shape[0]
+	det := 1.0
+	for i := 0; i < n; i++ {
+		det *= f.lu.data[i*n+i]
+		if f.pivots[i] != i {
+			det = -det
+		}
+	}
+	return det
+}
+
+/*
+ Returns x solving A*x = b. b may be a vector or a matrix whose columns are
+ separate right hand sides, and x has the same shape as b.
+ Returns ErrSingular if A is singular.
+*/
+func (f *LU) Solve(b *GsArray) (*GsArray, error) {
+	n := f.lu.shape[0]
+	if len(b.shape) == 0 || len(b.shape) > 2 || b.shape[0] != n {
+		return nil, shapeError("Solve", f.lu.shape, b.shape)
+	}
+	if f.singular {
+		return nil, ErrSingular
+	}
+	x := b.Copy()
+	nrhs := 1
+	if len(x.shape) == 2 {
+		nrhs = x.shape[1]
+	}
+	lapackEngine.Dgetrs(false, n, nrhs, f.lu.data, max(n, 1), f.pivots, x.data, max(nrhs, 1))
+	return x, nil
+}
+
+/*
+ Returns the inverse of A, or ErrSingular if A is singular
+*/
+func (f *LU) Inv() (*GsArray, error) {
+	return f.Solve(Eye(f.lu.shape[0]))
+}
+
+/*
+ Returns x solving a*x = b for the square matrix a, see LU.Solve
+*/
+func Solve(a, b *GsArray) (*GsArray, error) {
+	f, err := NewLU(a)
+	if err != nil {
+		return nil, err
+	}
+	return f.Solve(b)
+}
+
+/*
+ Returns the inverse of the square matrix a, or ErrSingular if a is singular
+*/
+func Inv(a *GsArray) (*GsArray, error) {
+	f, err := NewLU(a)
+	if err != nil {
+		return nil, err
+	}
+	return f.Inv()
+}
+
+/*
+ Returns the determinant of the square matrix a
+*/
+func Det(a *GsArray) (float64, error) {
+	f, err := NewLU(a)
+	if err != nil {
+		return 0, err
+	}
+	return f.Det(), nil
+}
@@ -0,0 +1,129 @@
+package goSci
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestLU(t *testing.T) {
+	r := rand.New(rand.NewSource(8))
+	for _, n := range []int{1, 2, 5, 12} {
+		a := randomArray(r, n, n)
+		f, err := NewLU(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l, u, p := f.L(), f.U(), f.P()
+		assertClose(t, "P*L*U", MatMult(p, MatMult(l, u)), a, 1e-12)
+		for i := 0; i < n; i++ {
+			if l.Get(i, i) != 1 {
+				t.Errorf("L has %v on its diagonal", l.Get(i, i))
+			}
+			for j := i + 1; j < n; j++ {
+				if l.Get(i, j) != 0 || u.Get(j, i) != 0 {
+					t.Fatalf("L or U is not triangular at %d, %d", i, j)
+				}
+			}
+		}
+		// partial pivoting keeps the multipliers in L at most one
+		if maxAbsDiff(l, Zeros(n, n)) > 1 {
+			t.Errorf("L has an element larger than one: %v", l)
+		}
+
+		inv, err := f.Inv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertClose(t, "Inv*A", MatMult(inv, a), Eye(n), 1e-10)
+	}
+}
+
+func TestLUSolve(t *testing.T) {
+	r := rand.New(rand.NewSource(9))
+	a := randomArray(r, 6, 6)
+	f, _ := NewLU(a)
+
+	b := randomArray(r, 6)
+	x, err := f.Solve(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameShape(x.shape, b.shape) {
+		t.Fatalf("Solve of a vector: got shape %v", x.shape)
+	}
+	assertClose(t, "A*x", reshaped(MatMult(a, reshaped(x, []int{6, 1})), []int{6}), b, 1e-10)
+
+	// several right hand sides, given as a strided view
+	bs := randomArray(r, 6, 8).Slice(All, Span(0, 8, 2))
+	xs, err := Solve(a, bs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "A*X", MatMult(a, xs), bs, 1e-10)
+
+	// a transposed matrix is factored as its values
+	xt, _ := Solve(a.T(), b)
+	assertClose(t, "A^T*x", reshaped(MatMult(a.T(), reshaped(xt, []int{6, 1})), []int{6}), b, 1e-10)
+
+	if _, err := f.Solve(Zeros(5)); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("Solve with a right hand side of the wrong length: got %v", err)
+	}
+}
+
+func TestLUDet(t *testing.T) {
+	a := FromSlice([]float64{
+		2, 1, 1,
+		4, -6, 0,
+		-2, 7, 2,
+	}, 3, 3)
+	det, err := Det(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "Det", FromSlice([]float64{det}, 1), FromSlice([]float64{-16}, 1), 1e-12)
+
+	// a row swap negates the determinant
+	swapped := a.Slice(Span(2, Omit, -1))
+	if det, _ := Det(swapped); math.Abs(det-16) > 1e-12 {
+		t.Errorf("Det with reversed rows: got %v, want 16", det)
+	}
+	if det, _ := Det(Eye(4)); det != 1 {
+		t.Errorf("Det of the identity: got %v", det)
+	}
+}
+
+func TestLUErrors(t *testing.T) {
+	// the second column is twice the first, and elimination is exact so U
+	// gets an exact zero on its diagonal
+	singular := FromSlice([]float64{
+		1, 2, 3,
+		2, 4, 7,
+		4, 8, 1,
+	}, 3, 3)
+	f, err := NewLU(singular)
+	if err != nil {
+		t.Fatalf("NewLU of a singular matrix: %v", err)
+	}
+	if det := f.Det(); det != 0 {
+		t.Errorf("Det of a singular matrix: got %v", det)
+	}
+	if _, err := f.Solve(Ones(3)); !errors.Is(err, ErrSingular) {
+		t.Errorf("Solve: got %v, want ErrSingular", err)
+	}
+	if _, err := Inv(Zeros(2, 2)); !errors.Is(err, ErrSingular) {
+		t.Errorf("Inv: got %v, want ErrSingular", err)
+	}
+
+	for name, err := range map[string]error{
+		"NewLU": second(NewLU(Zeros(2, 3))),
+		"Solve": second(Solve(Zeros(3), Zeros(3))),
+		"Inv":   second(Inv(Zeros(3, 2))),
+		"Det":   second(Det(Zeros(2, 2, 2))),
+	} {
+		if !errors.As(err, new(*ShapeMismatchError)) {
+			t.Errorf("%s of a non-square array: got %v", name, err)
+		}
+	}
+}
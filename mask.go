@@ -0,0 +1,267 @@
+package goSci
+
+import "math"
+
+/*
+ Returns the mask of x > y elementwise. The arrays are broadcast to a common
+ shape, so a one element array compares every element against a scalar.
+*/
+func Greater(x, y *GsArray) *Array[bool] {
+	return must(TryGreater(x, y))
+}
+
+/*
+ Same as Greater but returns a ShapeMismatchError instead of panicking
+*/
+func TryGreater(x, y *GsArray) (*Array[bool], error) {
+	return compareOp("Greater", x, y, func(a, b float64) bool { return a > b })
+}
+
+/*
+ Returns the mask of x < y elementwise, broadcasting as Greater does
+*/
+func Less(x, y *GsArray) *Array[bool] {
+	return must(TryLess(x, y))
+}
+
+/*
+ Same as Less but returns a ShapeMismatchError instead of panicking
+*/
+func TryLess(x, y *GsArray) (*Array[bool], error) {
+	return compareOp("Less", x, y, func(a, b float64) bool { return a < b })
+}
+
+/*
+ Returns the mask of x == y elementwise, broadcasting as Greater does.
+ NaN is not equal to anything, itself included.
+*/
+func Equal(x, y *GsArray) *Array[bool] {
+	return must(TryEqual(x, y))
+}
+
+/*
+ Same as Equal but returns a ShapeMismatchError instead of panicking
+*/
+func TryEqual(x, y *GsArray) (*Array[bool], error) {
+	return compareOp("Equal", x, y, func(a, b float64) bool { return a == b })
+}
+
+/*
+ Returns cmp applied elementwise to x and y after broadcasting them to a
+ common shape. name is the operation reported in a ShapeMismatchError.
+*/
+func compareOp(name string, x, y *GsArray, cmp func(a, b float64) bool) (*Array[bool], error) {
+	shape, ok := broadcastShapes(x.shape, y.shape)
+	if !ok {
+		return nil, shapeError(name, x.shape, y.shape)
+	}
+	result := ZerosOf[bool](shape...)
+	i := 0
+	strides := [][]int{broadcastStrides(x, shape), broadcastStrides(y, shape)}
+	walkStrided(shape, []int{x.offset, y.offset}, strides, func(pos []int) {
+		result.data[i] = cmp(x.data[pos[0]], y.data[pos[1]])
+		i++
+	})
+	return result, nil
+}
+
+/*
+ Returns the mask of the elements of x that are NaN
+*/
+func IsNaN(x *GsArray) *Array[bool] {
+	return testElements(x, math.IsNaN)
+}
+
+/*
+ Returns the mask of the elements of x that are positive or negative infinity
+*/
+func IsInf(x *GsArray) *Array[bool] {
+	return testElements(x, func(val float64) bool { return math.IsInf(val, 0) })
+}
+
+func testElements(x *GsArray, test func(float64) bool) *Array[bool] {
+	result := ZerosOf[bool](x.shape...)
+	for i, val := range x.values() {
+		result.data[i] = test(val)
+	}
+	return result
+}
+
+/*
+ Returns the elementwise logical and of the masks a and b, broadcast to a
+ common shape
+*/
+func And(a, b *Array[bool]) *Array[bool] {
+	return must(TryAnd(a, b))
+}
+
+/*
+ Same as And but returns a ShapeMismatchError instead of panicking
+*/
+func TryAnd(a, b *Array[bool]) (*Array[bool], error) {
+	return logicalOp("And", a, b, func(p, q bool) bool { return p && q })
+}
+
+/*
+ Returns the elementwise logical or of the masks a and b, broadcast to a
+ common shape
+*/
+func Or(a, b *Array[bool]) *Array[bool] {
+	return must(TryOr(a, b))
+}
+
+/*
+ Same as Or but returns a ShapeMismatchError instead of panicking
+*/
+func TryOr(a, b *Array[bool]) (*Array[bool], error) {
+	return logicalOp("Or", a, b, func(p, q bool) bool { return p || q })
+}
+
+/*
+ Returns the elementwise logical negation of the mask a
+*/
+func Not(a *Array[bool]) *Array[bool] {
+	result := ZerosOf[bool](a.shape...)
+	for i, val := range a.data {
+		result.data[i] = !val
+	}
+	return result
+}
+
+func logicalOp(name string, a, b *Array[bool], op func(p, q bool) bool) (*Array[bool], error) {
+	shape, ok := broadcastShapes(a.shape, b.shape)
+	if !ok {
+		return nil, shapeError(name, a.shape, b.shape)
+	}
+	result := ZerosOf[bool](shape...)
+	i := 0
+	strides := [][]int{arrayStrides(a, shape), arrayStrides(b, shape)}
+	walkStrided(shape, []int{0, 0}, strides, func(pos []int) {
+		result.data[i] = op(a.data[pos[0]], b.data[pos[1]])
+		i++
+	})
+	return result, nil
+}
+
+/*
+ Returns the elements of x where mask is true, in row major order, as a
+ vector. mask must have the shape of x.
+*/
+func MaskedSelect(x *GsArray, mask *Array[bool]) *GsArray {
+	return must(TryMaskedSelect(x, mask))
+}
+
+/*
+ Same as MaskedSelect but returns a ShapeMismatchError instead of panicking
+*/
+func TryMaskedSelect(x *GsArray, mask *Array[bool]) (*GsArray, error) {
+	if !sameShape(x.shape, mask.shape) {
+		return nil, shapeError("MaskedSelect", x.shape, mask.shape)
+	}
+	selected := make([]float64, 0)
+	for i, val := range x.values() {
+		if mask.data[i] {
+			selected = append(selected, val)
+		}
+	}
+	return FromSlice(selected, len(selected)), nil
+}
+
+/*
+ Sets the elements of x where mask is true to the matching elements of y.
+ mask and y are broadcast to the shape of x, so a one element y fills the
+ selected elements with a scalar.
+ e.g.
+ x.MaskedAssign(IsNaN(x), FromSlice([]float64{0}, 1))
+*/
+func (x *GsArray) MaskedAssign(mask *Array[bool], y *GsArray) {
+	mustDo(x.TryMaskedAssign(mask, y))
+}
+
+/*
+ Same as MaskedAssign but returns a ShapeMismatchError instead of panicking
+*/
+func (x *GsArray) TryMaskedAssign(mask *Array[bool], y *GsArray) error {
+	if shape, ok := broadcastShapes(x.shape, mask.shape); !ok || !sameShape(shape, x.shape) {
+		return shapeError("MaskedAssign", x.shape, mask.shape)
+	}
+	if shape, ok := broadcastShapes(x.shape, y.shape); !ok || !sameShape(shape, x.shape) {
+		return shapeError("MaskedAssign", x.shape, y.shape)
+	}
+	if overlaps(x, y) {
+		y = y.Copy()
+	}
+	strides := [][]int{x.getStrides(), arrayStrides(mask, x.shape), broadcastStrides(y, x.shape)}
+	walkStrided(x.shape, []int{x.offset, 0, y.offset}, strides, func(pos []int) {
+		if mask.data[pos[1]] {
+			x.data[pos[0]] = y.data[pos[2]]
+		}
+	})
+	return nil
+}
+
+/*
+ Returns the elements of x where cond is true and of y where it is false.
+ cond, x and y are broadcast to a common shape.
+*/
+func Where(cond *Array[bool], x, y *GsArray) *GsArray {
+	return must(TryWhere(cond, x, y))
+}
+
+/*
+ Same as Where but returns a ShapeMismatchError instead of panicking
+*/
+func TryWhere(cond *Array[bool], x, y *GsArray) (*GsArray, error) {
+	shape, ok := broadcastShapes(x.shape, y.shape)
+	if !ok {
+		return nil, shapeError("Where", x.shape, y.shape)
+	}
+	valueShape := shape
+	if shape, ok = broadcastShapes(cond.shape, valueShape); !ok {
+		return nil, shapeError("Where", cond.shape, valueShape)
+	}
+	result := Zeros(shape...)
+	i := 0
+	strides := [][]int{arrayStrides(cond, shape), broadcastStrides(x, shape), broadcastStrides(y, shape)}
+	walkStrided(shape, []int{0, x.offset, y.offset}, strides, func(pos []int) {
+		if cond.data[pos[0]] {
+			result.data[i] = x.data[pos[1]]
+		} else {
+			result.data[i] = y.data[pos[2]]
+		}
+		i++
+	})
+	return result, nil
+}
+
+/*
+ Returns the positions of the nonzero elements of x, one slice of indices per
+ axis, in row major order: element k is nonzero at
+ (indices[0][k], indices[1][k], ...)
+*/
+func Nonzero(x *GsArray) [][]int {
+	indices := make([][]int, len(x.shape))
+	strides := contiguousStrides(x.shape)
+	for i, val := range x.values() {
+		if val == 0 {
+			continue
+		}
+		for axis, stride := range strides {
+			indices[axis] = append(indices[axis], i/stride%x.shape[axis])
+		}
+	}
+	return indices
+}
+
+/*
+ Returns the number of nonzero elements of x
+*/
+func CountNonzero(x *GsArray) int {
+	count := 0
+	for _, val := range x.values() {
+		if val != 0 {
+			count++
+		}
+	}
+	return count
+}
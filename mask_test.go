@@ -0,0 +1,183 @@
+package goSci
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	x := FromSlice([]float64{1, 5, math.NaN(), 3, 4, 0}, 2, 3)
+	row := FromSlice([]float64{3, 4, math.NaN()}, 3)
+	col := FromSlice([]float64{4, 0}, 2, 1)
+	scalar := FromSlice([]float64{3}, 1)
+
+	cases := []struct {
+		name  string
+		got   *Array[bool]
+		shape []int
+		want  []bool
+	}{
+		{"Greater scalar", Greater(x, scalar), []int{2, 3}, []bool{false, true, false, false, true, false}},
+		{"Less row", Less(x, row), []int{2, 3}, []bool{true, false, false, false, false, false}},
+		{"Equal col", Equal(x, col), []int{2, 3}, []bool{false, false, false, false, false, true}},
+		// NaN is not equal to itself
+		{"Equal itself", Equal(x, x), []int{2, 3}, []bool{true, true, false, true, true, true}},
+		// both operands broadcast
+		{"Greater col row", Greater(col, row), []int{2, 3}, []bool{true, false, false, false, false, false}},
+		{"Less transpose", Less(x.T(), FromSlice([]float64{2, 4}, 2)), []int{3, 2}, []bool{true, true, false, false, false, true}},
+		{"Greater stepped", Greater(x.Slice(All, Span(0, 3, 2)), scalar), []int{2, 2}, []bool{false, false, false, false}},
+	}
+	for _, c := range cases {
+		if !reflect.DeepEqual(c.got.Shape(), c.shape) || !reflect.DeepEqual(c.got.Data(), c.want) {
+			t.Errorf("%s: got %v with shape %v, want %v", c.name, c.got.Data(), c.got.Shape(), c.want)
+		}
+	}
+
+	if got := IsNaN(x.T()).Data(); !reflect.DeepEqual(got, []bool{false, false, false, false, true, false}) {
+		t.Errorf("IsNaN of a transpose: got %v", got)
+	}
+	if got := IsInf(FromSlice([]float64{math.Inf(-1), 1, math.Inf(1)}, 3)).Data(); !reflect.DeepEqual(got, []bool{true, false, true}) {
+		t.Errorf("IsInf: got %v", got)
+	}
+
+	for name, err := range map[string]error{
+		"Greater": second(TryGreater(x, Zeros(2))),
+		"Less":    second(TryLess(x, Zeros(3, 3))),
+		"Equal":   second(TryEqual(Zeros(4), x)),
+	} {
+		var sm *ShapeMismatchError
+		if !errors.As(err, &sm) || sm.Op != name {
+			t.Errorf("%s with mismatched shapes: got %v", name, err)
+		}
+	}
+}
+
+func TestLogical(t *testing.T) {
+	a := ArrayFromSlice([]bool{true, false, true, false}, 2, 2)
+	b := ArrayFromSlice([]bool{true, true, false, false}, 2, 2)
+	col := ArrayFromSlice([]bool{false, true}, 2, 1)
+
+	if got := And(a, b).Data(); !reflect.DeepEqual(got, []bool{true, false, false, false}) {
+		t.Errorf("And: got %v", got)
+	}
+	if got := Or(a, b).Data(); !reflect.DeepEqual(got, []bool{true, true, true, false}) {
+		t.Errorf("Or: got %v", got)
+	}
+	if got := And(a, col).Data(); !reflect.DeepEqual(got, []bool{false, false, true, false}) {
+		t.Errorf("And broadcast: got %v", got)
+	}
+	if got := Or(col, ArrayFromSlice([]bool{false, true, false}, 3)); !reflect.DeepEqual(got.Shape(), []int{2, 3}) ||
+		!reflect.DeepEqual(got.Data(), []bool{false, true, false, true, true, true}) {
+		t.Errorf("Or broadcast: got %v with shape %v", got.Data(), got.Shape())
+	}
+	if got := Not(a); !reflect.DeepEqual(got.Data(), []bool{false, true, false, true}) || !reflect.DeepEqual(got.Shape(), []int{2, 2}) {
+		t.Errorf("Not: got %v", got.Data())
+	}
+	// Not returns a new mask
+	if a.Get(0, 0) != true {
+		t.Error("Not changed its argument")
+	}
+
+	if _, err := TryAnd(a, OnesOf[bool](3)); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("And with mismatched shapes: got %v", err)
+	}
+	if _, err := TryOr(OnesOf[bool](2, 3), a); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("Or with mismatched shapes: got %v", err)
+	}
+}
+
+func TestMaskedSelect(t *testing.T) {
+	x := Arange(6)
+	x.Reshape(2, 3)
+	xt := x.T()
+	mask := Greater(xt, FromSlice([]float64{2}, 1))
+	// a transpose is selected from in its own row major order
+	assertArray(t, "MaskedSelect of a transpose", MaskedSelect(xt, mask), []int{3}, []float64{3, 4, 5})
+	assertArray(t, "MaskedSelect of none", MaskedSelect(x, ZerosOf[bool](2, 3)), []int{0}, []float64{})
+
+	// the mask must have the shape of x, it is not broadcast
+	if _, err := TryMaskedSelect(x, OnesOf[bool](3)); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("MaskedSelect with a smaller mask: got %v", err)
+	}
+	if _, err := TryMaskedSelect(x, mask); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("MaskedSelect with a transposed mask: got %v", err)
+	}
+}
+
+func TestMaskedAssign(t *testing.T) {
+	x := FromSlice([]float64{1, math.NaN(), 3, math.NaN()}, 2, 2)
+	x.MaskedAssign(IsNaN(x), FromSlice([]float64{0}, 1))
+	assertArray(t, "scalar", x, []int{2, 2}, []float64{1, 0, 3, 0})
+
+	// a mask row and a column of values broadcast over x
+	x.MaskedAssign(ArrayFromSlice([]bool{false, true}, 2), FromSlice([]float64{7, 8}, 2, 1))
+	assertArray(t, "broadcast", x, []int{2, 2}, []float64{1, 7, 3, 8})
+
+	// assigning into a strided view writes through to its base
+	base := Zeros(3, 4)
+	view := base.Slice(Span(0, 3, 2), Span(1, 4, 2))
+	view.MaskedAssign(ArrayFromSlice([]bool{true, false, true, true}, 2, 2), FromSlice([]float64{1, 2, 3, 4}, 2, 2))
+	assertArray(t, "strided view", base, []int{3, 4}, []float64{0, 1, 0, 0, 0, 0, 0, 0, 0, 3, 0, 4})
+
+	// y overlapping x is read before x is written
+	v := Arange(4)
+	v.MaskedAssign(OnesOf[bool](4), v.Slice(Span(Omit, Omit, -1)))
+	assertArray(t, "overlapping y", v, []int{4}, []float64{3, 2, 1, 0})
+
+	for name, err := range map[string]error{
+		"a larger mask":            x.TryMaskedAssign(OnesOf[bool](3, 2, 2), Zeros(1)),
+		"a mismatched mask":        x.TryMaskedAssign(OnesOf[bool](3), Zeros(1)),
+		"values of a larger shape": x.TryMaskedAssign(OnesOf[bool](2, 2), Zeros(2, 2, 2)),
+		"mismatched values":        x.TryMaskedAssign(OnesOf[bool](2, 2), Zeros(3)),
+	} {
+		if !errors.As(err, new(*ShapeMismatchError)) {
+			t.Errorf("MaskedAssign with %s: got %v", name, err)
+		}
+	}
+}
+
+func TestWhere(t *testing.T) {
+	cond := ArrayFromSlice([]bool{true, false, false, true}, 2, 2)
+	x := FromSlice([]float64{1, 2, 3, 4}, 2, 2)
+	y := FromSlice([]float64{-1}, 1)
+	assertArray(t, "Where", Where(cond, x, y), []int{2, 2}, []float64{1, -1, -1, 4})
+	assertArray(t, "Where of a transpose", Where(cond, x.T(), Times(x, 10)), []int{2, 2}, []float64{1, 20, 30, 4})
+
+	// cond broadcast against both values
+	row := ArrayFromSlice([]bool{true, false, true}, 3)
+	col := FromSlice([]float64{1, 2}, 2, 1)
+	assertArray(t, "Where broadcast", Where(row, col, FromSlice([]float64{0}, 1)), []int{2, 3}, []float64{1, 0, 1, 2, 0, 2})
+	// cond may have more axes than the values
+	assertArray(t, "Where with a larger cond", Where(OnesOf[bool](2, 1, 2), FromSlice([]float64{5, 6}, 2), y), []int{2, 1, 2}, []float64{5, 6, 5, 6})
+
+	if _, err := TryWhere(cond, x, Zeros(3)); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("Where with mismatched values: got %v", err)
+	}
+	if _, err := TryWhere(OnesOf[bool](3), x, y); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("Where with a mismatched cond: got %v", err)
+	}
+}
+
+func TestNonzero(t *testing.T) {
+	x := FromSlice([]float64{0, 2, 0, -1, math.NaN(), 0}, 2, 3)
+	if got := Nonzero(x); !reflect.DeepEqual(got, [][]int{{0, 1, 1}, {1, 0, 1}}) {
+		t.Errorf("Nonzero: got %v", got)
+	}
+	if got := Nonzero(x.T()); !reflect.DeepEqual(got, [][]int{{0, 1, 1}, {1, 0, 1}}) {
+		t.Errorf("Nonzero of a transpose: got %v", got)
+	}
+	if got := Nonzero(x.Slice(All, At(2))); len(got) != 1 || len(got[0]) != 0 {
+		t.Errorf("Nonzero of zeros: got %v", got)
+	}
+	if got := CountNonzero(x); got != 3 {
+		t.Errorf("CountNonzero: got %d, want 3", got)
+	}
+	if got := CountNonzero(x.Slice(All, Span(0, 3, 2))); got != 1 {
+		t.Errorf("CountNonzero of a stepped view: got %d, want 1", got)
+	}
+	if got := CountNonzero(Zeros(0, 4)); got != 0 {
+		t.Errorf("CountNonzero of an empty array: got %d", got)
+	}
+}
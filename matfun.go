@@ -0,0 +1,299 @@
+package goSci
+
+import "math"
+
+/*
+ Degrees of the Padé approximants Expm chooses from, and the largest 1-norm
+ of the matrix for which each is accurate to double precision (Higham 2005)
+*/
+var (
+	padeDegrees = []int{3, 5, 7, 9, 13}
+	padeTheta   = []float64{1.495585217958292e-2, 2.539398330063230e-1, 9.504178996162932e-1, 2.097847961257068, 5.371920351148152}
+	padeCoeffs  = [][]float64{
+		{120, 60, 12, 1},
+		{30240, 15120, 3360, 420, 30, 1},
+		{17297280, 8648640, 1995840, 277200, 25200, 1512, 56, 1},
+		{17643225600, 8821612800, 2075673600, 302702400, 30270240, 2162160, 110880, 3960, 90, 1},
+		{64764752532480000, 32382376266240000, 7771770303897600, 1187353796428800, 129060195264000,
+			10559470521600, 670442572800, 33522128640, 1323241920, 40840800, 960960, 16380, 182, 1},
+	}
+)
+
+/*
+ Returns the matrix exponential of the square matrix a by scaling and
+ squaring: a is scaled by a power of two until a Padé approximant of the
+ lowest sufficient degree is accurate, and the approximant is squared back up.
+*/
+func Expm(a *GsArray) (*GsArray, error) {
+	if _, err := squareSize("Expm", a); err != nil {
+		return nil, err
+	}
+	norm := norm1(a)
+	for d, degree := range padeDegrees[:len(padeDegrees)-1] {
+		if norm <= padeTheta[d] {
+			return padeExp(a.Copy(), padeCoeffs[d], degree)
+		}
+	}
+	// scale so the norm is below the limit of the degree 13 approximant
+	s := 0
+	if theta := padeTheta[len(padeTheta)-1]; norm > theta {
+		s = int(math.Ceil(math.Log2(norm / theta)))
+	}
+	scaled := Times(a, math.Ldexp(1, -s))
+	r, err := padeExp(scaled, padeCoeffs[len(padeCoeffs)-1], 13)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < s; i++ {
+		r = MatMult(r, r)
+	}
+	return r, nil
+}
+
+/*
+ Returns the [m/m] Padé approximant of exp(a), (V - U)^-1 * (V + U) where U
+ holds the odd and V the even terms of the numerator polynomial with
+ coefficients b
+*/
+func padeExp(a *GsArray, b []float64, m int) (*GsArray, error) {
+	n := a.shape[0]
+	ident := Eye(n)
+	a2 := MatMult(a, a)
+	var u, v *GsArray
+	if m == 13 {
+		a4 := MatMult(a2, a2)
+		a6 := MatMult(a4, a2)
+		u = MatMult(a6, linearCombination([]float64{b[13], b[11], b[9]}, a6, a4, a2))
+		u.PlusEquals(linearCombination([]float64{b[7], b[5], b[3], b[1]}, a6, a4, a2, ident))
+		u = MatMult(a, u)
+		v = MatMult(a6, linearCombination([]float64{b[12], b[10], b[8]}, a6, a4, a2))
+		v.PlusEquals(linearCombination([]float64{b[6], b[4], b[2], b[0]}, a6, a4, a2, ident))
+	} else {
+		// powers[k] = a^(2k)
+		powers := []*GsArray{ident, a2}
+		for len(powers) <= m/2 {
+			powers = append(powers, MatMult(powers[len(powers)-1], a2))
+		}
+		u, v = Zeros(n, n), Zeros(n, n)
+		for k := 0; 2*k <= m; k++ {
+			if 2*k+1 <= m {
+				Axpy(b[2*k+1], powers[k], u)
+			}
+			Axpy(b[2*k], powers[k], v)
+		}
+		u = MatMult(a, u)
+	}
+	return Solve(Minus(v, u), Plus(v, u))
+}
+
+/*
+ Returns the sum of coeffs[i]*terms[i]
+*/
+func linearCombination(coeffs []float64, terms ...*GsArray) *GsArray {
+	sum := Zeros(terms[0].Shape()...)
+	for i, term := range terms {
+		Axpy(coeffs[i], term, sum)
+	}
+	return sum
+}
+
+/*
+ Returns the principal square root of the square matrix a, the X with
+ X*X = a whose eigenvalues have positive real parts, by the Denman-Beavers
+ iteration with determinant scaling. The principal real square root exists
+ when a has no eigenvalues on the closed negative real axis; otherwise the
+ iteration fails with ErrSingular or ErrNoConvergence.
+*/
+func Sqrtm(a *GsArray) (*GsArray, error) {
+	if _, err := squareSize("Sqrtm", a); err != nil {
+		return nil, err
+	}
+	y, _, err := denmanBeavers(a.Copy())
+	return y, err
+}
+
+/*
+ Runs the Denman-Beavers iteration Y <- (Y + Z^-1)/2, Z <- (Z + Y^-1)/2 from
+ Y = a, Z = I, which converges to Y = a^(1/2) and Z = a^(-1/2)
+*/
+func denmanBeavers(a *GsArray) (y, z *GsArray, err error) {
+	const maxIter = 100
+	n := a.shape[0]
+	y, z = a, Eye(n)
+	for iter := 0; iter < maxIter; iter++ {
+		yLU, err := NewLU(y)
+		if err != nil {
+			return nil, nil, err
+		}
+		zLU, err := NewLU(z)
+		if err != nil {
+			return nil, nil, err
+		}
+		yInv, err := yLU.Inv()
+		if err != nil {
+			return nil, nil, err
+		}
+		zInv, err := zLU.Inv()
+		if err != nil {
+			return nil, nil, err
+		}
+		// scaling by |det(Y)*det(Z)|^(-1/2n) speeds up the early iterations
+		mu := 1.0
+		if iter < 10 {
+			mu = math.Pow(math.Abs(yLU.Det()*zLU.Det()), -1/(2*float64(n)))
+			if math.IsInf(mu, 0) || math.IsNaN(mu) || mu == 0 {
+				mu = 1
+			}
+		}
+		yNext := linearCombination([]float64{mu / 2, 1 / (2 * mu)}, y, zInv)
+		zNext := linearCombination([]float64{mu / 2, 1 / (2 * mu)}, z, yInv)
+		change := frobenius(Minus(yNext, y))
+		y, z = yNext, zNext
+		if change <= 4*sqrtmTol(n)*frobenius(y) {
+			return y, z, nil
+		}
+	}
+	return nil, nil, ErrNoConvergence
+}
+
+/*
+ Returns the tolerance the iterations of Sqrtm and Logm aim for with n by n matrices
+*/
+func sqrtmTol(n int) float64 {
+	return math.Sqrt(float64(max(n, 1))) * 2.220446049250313e-16
+}
+
+/*
+ Returns the principal logarithm of the square matrix a, the X with
+ Expm(X) = a whose eigenvalues have imaginary parts in (-pi, pi), by inverse
+ scaling and squaring: square roots are taken until a is close to the
+ identity, the logarithm there is evaluated by Gauss-Legendre quadrature of
+ log(I + X) = integral over [0, 1] of X*(I + t*X)^-1 dt, and the result is
+ scaled back up. The principal real logarithm exists when a has no eigenvalues
+ on the closed negative real axis; otherwise Logm fails with ErrSingular or
+ ErrNoConvergence.
+*/
+func Logm(a *GsArray) (*GsArray, error) {
+	n, err := squareSize("Logm", a)
+	if err != nil {
+		return nil, err
+	}
+	const maxRoots = 64
+	ident := Eye(n)
+	r := a.Copy()
+	k := 0
+	for ; norm1(Minus(r, ident)) > 0.25; k++ {
+		if k == maxRoots {
+			return nil, ErrNoConvergence
+		}
+		if r, _, err = denmanBeavers(r); err != nil {
+			return nil, err
+		}
+	}
+	x := Minus(r, ident)
+	nodes, weights := gaussLegendre(8)
+	log := Zeros(n, n)
+	for i, t := range nodes {
+		// X*(I + t*X)^-1 = (I + t*X)^-1*X since the factors commute
+		term, err := Solve(Plus(ident, Times(x, t)), x)
+		if err != nil {
+			return nil, err
+		}
+		Axpy(weights[i], term, log)
+	}
+	log.TimesEquals(math.Ldexp(1, k))
+	return log, nil
+}
+
+/*
+ Returns the nodes and weights of the m point Gauss-Legendre rule on [0, 1],
+ the nodes found as roots of the Legendre polynomial by Newton's method
+*/
+func gaussLegendre(m int) (nodes, weights []float64) {
+	nodes, weights = make([]float64, m), make([]float64, m)
+	for i := 0; i < m; i++ {
+		x := math.Cos(math.Pi * (float64(i) + 0.75) / (float64(m) + 0.5))
+		var deriv float64
+		for iter := 0; iter < 100; iter++ {
+			// evaluate P_m(x) and its derivative by the three term recurrence
+			p0, p1 := 1.0, x
+			for k := 2; k <= m; k++ {
+				p0, p1 = p1, ((2*float64(k)-1)*x*p1-(float64(k)-1)*p0)/float64(k)
+			}
+			deriv = float64(m) * (x*p1 - p0) / (x*x - 1)
+			dx := p1 / deriv
+			x -= dx
+			if math.Abs(dx) < 1e-16 {
+				break
+			}
+		}
+		nodes[i] = (1 - x) / 2
+		weights[i] = 1 / ((1 - x*x) * deriv * deriv)
+	}
+	return nodes, weights
+}
+
+/*
+ Returns the square matrix a raised to the integer power k by repeated
+ squaring. k = 0 gives the identity and a negative k a power of the inverse,
+ which fails with ErrSingular if a is singular.
+*/
+func MatrixPower(a *GsArray, k int) (*GsArray, error) {
+	n, err := squareSize("MatrixPower", a)
+	if err != nil {
+		return nil, err
+	}
+	base := a.Copy()
+	if k < 0 {
+		if base, err = Inv(a); err != nil {
+			return nil, err
+		}
+		k = -k
+	}
+	result := Eye(n)
+	for first := true; k > 0; k >>= 1 {
+		if k&1 == 1 {
+			if first {
+				result, first = base.Copy(), false
+			} else {
+				result = MatMult(result, base)
+			}
+		}
+		if k > 1 {
+			base = MatMult(base, base)
+		}
+	}
+	return result, nil
+}
+
+/*
+ Returns the size of the square matrix a, or a ShapeMismatchError naming op
+*/
+func squareSize(op string, a *GsArray) (int, error) {
+	if len(a.shape) != 2 || a.shape[0] != a.shape[1] {
+		return 0, shapeError(op, a.shape, nil)
+	}
+	return a.shape[0], nil
+}
+
+/*
+ Returns the 1-norm of the matrix a, its largest column sum of magnitudes
+ (for Expm's and Logm's choice of scaling)
+*/
+func norm1(a *GsArray) float64 {
+	rows, cols := a.shape[0], a.shape[1]
+	vals := a.values()
+	norm := 0.0
+	for j := 0; j < cols; j++ {
+		norm = math.Max(norm, engine.Dasum(rows, vals[j:], cols))
+	}
+	return norm
+}
+
+/*
+ Returns the Frobenius norm of a
+*/
+func frobenius(a *GsArray) float64 {
+	vals := a.values()
+	return engine.Dnrm2(len(vals), vals, 1)
+}
@@ -0,0 +1,115 @@
+package goSci
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func rotation(theta float64) *GsArray {
+	c, s := math.Cos(theta), math.Sin(theta)
+	return FromSlice([]float64{c, -s, s, c}, 2, 2)
+}
+
+func TestExpm(t *testing.T) {
+	for _, theta := range []float64{1e-3, 0.5, 3, 40} {
+		gen := FromSlice([]float64{0, -theta, theta, 0}, 2, 2)
+		e, err := Expm(gen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertClose(t, "Expm of a rotation generator", e, rotation(theta), 1e-13*math.Max(1, theta))
+	}
+
+	// the series of a nilpotent matrix stops at N^2 / 2
+	n := FromSlice([]float64{0, 1, 0, 0, 0, 1, 0, 0, 0}, 3, 3)
+	e, err := Expm(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertArray(t, "Expm of a nilpotent matrix", e, []int{3, 3}, []float64{1, 1, 0.5, 0, 1, 1, 0, 0, 1})
+
+	// the example of Moler and Van Loan, where the Taylor series cancels badly
+	e, err = Expm(FromSlice([]float64{-49, 24, -64, 31}, 2, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []float64{-0.73575875814475, 0.55181909965810, -1.47151759908826, 1.10363824071557}
+	for i, val := range e.Data() {
+		if math.Abs(val-want[i]) > 1e-10*math.Abs(want[i]) {
+			t.Errorf("Expm of the Moler and Van Loan matrix: element %d is %.14f, want %.14f", i, val, want[i])
+		}
+	}
+}
+
+func TestLogmSqrtm(t *testing.T) {
+	r := rand.New(rand.NewSource(14))
+	for _, n := range []int{1, 3, 8} {
+		b := Times(randomArray(r, n, n), 0.5)
+		eb, err := Expm(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l, err := Logm(eb)
+		if err != nil {
+			t.Fatalf("Logm of a %d by %d matrix: %v", n, n, err)
+		}
+		assertClose(t, "Logm(Expm(A))", l, b, 1e-12)
+
+		// diagonally dominant, so the eigenvalues have positive real parts
+		a := Plus(Times(randomArray(r, n, n), 0.3), Times(Eye(n), float64(n)))
+		s, err := Sqrtm(a)
+		if err != nil {
+			t.Fatalf("Sqrtm of a %d by %d matrix: %v", n, n, err)
+		}
+		assertClose(t, "Sqrtm(A)^2", MatMult(s, s), a, 1e-11)
+	}
+
+	// the principal logarithm of a rotation by less than pi
+	l, err := Logm(rotation(2.5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertArray(t, "Logm of a rotation", l, []int{2, 2}, []float64{0, -2.5, 2.5, 0})
+
+	// a negative real eigenvalue has no real principal square root or logarithm
+	neg := Times(Eye(2), -1)
+	if _, err := Sqrtm(neg); !errors.Is(err, ErrSingular) && !errors.Is(err, ErrNoConvergence) {
+		t.Errorf("Sqrtm with negative eigenvalues: got %v", err)
+	}
+	if _, err := Logm(neg); !errors.Is(err, ErrSingular) && !errors.Is(err, ErrNoConvergence) {
+		t.Errorf("Logm with negative eigenvalues: got %v", err)
+	}
+}
+
+func TestMatrixPower(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+	a := Plus(randomArray(r, 4, 4), Times(Eye(4), 4))
+
+	p, err := MatrixPower(a, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "MatrixPower(A, 0)", p, Eye(4), 0)
+
+	p, err = MatrixPower(a, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "MatrixPower(A, 5)", p, MatMult(MatMult(MatMult(a, a), MatMult(a, a)), a), 1e-9)
+
+	inv, err := Inv(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err = MatrixPower(a, -3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "MatrixPower(A, -3)", p, MatMult(MatMult(inv, inv), inv), 1e-12)
+
+	if _, err := MatrixPower(Zeros(2, 2), -1); !errors.Is(err, ErrSingular) {
+		t.Errorf("negative power of a singular matrix: got %v", err)
+	}
+}
@@ -0,0 +1,277 @@
+package goSci
+
+import "math"
+
+/*
+ Selects the norm computed by Norm and NormAxis. Ord(p) gives the vector
+ p-norms, sum(|x|^p)^(1/p), with Ord(0) counting the nonzero elements,
+ Ord(math.Inf(1)) the largest and Ord(math.Inf(-1)) the smallest magnitude.
+ For matrices Ord(1) and Ord(math.Inf(1)) are the largest column and row sums
+ of magnitudes, Ord(-1) and Ord(math.Inf(-1)) the smallest, Ord(-2) the
+ smallest singular value, and Fro, Nuclear and Spectral the norms below. Fro
+ and Nuclear are only defined for matrices.
+*/
+type NormOrd struct {
+	kind normKind
+	p    float64
+}
+
+type normKind int
+
+const (
+	pNorm normKind = iota
+	froNorm
+	nuclearNorm
+)
+
+var (
+	Fro      = NormOrd{kind: froNorm}     // Frobenius norm, the 2-norm of all the elements
+	Nuclear  = NormOrd{kind: nuclearNorm} // sum of the singular values of a matrix
+	Spectral = Ord(2)                     // largest singular value of a matrix, the matrix 2-norm
+)
+
+/*
+ Returns the order p, see NormOrd
+*/
+func Ord(p float64) NormOrd {
+	return NormOrd{p: p}
+}
+
+/*
+ Returns the ord norm of x: a vector norm for a vector, a matrix norm for a
+ matrix and a vector norm of the flattened array for higher ranks
+*/
+func Norm(x *GsArray, ord NormOrd) float64 {
+	norm, err := TryNorm(x, ord)
+	if err != nil {
+		panic(err)
+	}
+	return norm
+}
+
+/*
+ Same as Norm but returns ErrInvalidNorm instead of panicking when ord is not
+ defined for x, or ErrNoConvergence when the singular values of a matrix cannot
+ be found
+*/
+func TryNorm(x *GsArray, ord NormOrd) (float64, error) {
+	if len(x.shape) == 2 {
+		return matrixNorm(x.shape[0], x.shape[1], x.values(), ord)
+	}
+	return vectorNorm(x.values(), 1, x.Size(), ord)
+}
+
+/*
+ Returns the ord norm of x over axes: a vector norm along a single axis or a
+ matrix norm over a pair of axes, the first holding the rows. With no axes it
+ is Norm. keepDims keeps the reduced axes with length one, as in SumAxis.
+*/
+func NormAxis(x *GsArray, ord NormOrd, keepDims bool, axes ...int) *GsArray {
+	return must(TryNormAxis(x, ord, keepDims, axes...))
+}
+
+/*
+ Same as NormAxis but returns an InvalidAxisError, ErrInvalidNorm when ord is
+ not defined or ErrNoConvergence, see TryNorm, instead of panicking
+*/
+func TryNormAxis(x *GsArray, ord NormOrd, keepDims bool, axes ...int) (*GsArray, error) {
+	if len(axes) == 0 && len(x.shape) <= 2 {
+		for i := range x.shape {
+			axes = append(axes, i)
+		}
+	}
+	reduced, err := reducedAxes(x, axes)
+	if err != nil {
+		return nil, err
+	}
+	if len(axes) > 2 {
+		return nil, &InvalidAxisError{Axis: axes[2], NDim: len(x.shape)}
+	}
+	result := Zeros(reducedShape(x.shape, reduced, keepDims)...)
+	if len(axes) == 0 {
+		norm, err := vectorNorm(x.values(), 1, x.Size(), ord)
+		result.data[0] = norm
+		return result, err
+	}
+	// move the reduced axes to the end so each norm is over a packed block
+	perm := make([]int, 0, len(x.shape))
+	for i, r := range reduced {
+		if !r {
+			perm = append(perm, i)
+		}
+	}
+	for _, axis := range axes {
+		axis, _ = checkAxis(axis, len(x.shape))
+		perm = append(perm, axis)
+	}
+	vals := x.Transpose(perm...).Copy().data
+	rows, cols := 1, x.shape[perm[len(perm)-1]]
+	if len(axes) == 2 {
+		rows = x.shape[perm[len(perm)-2]]
+	}
+	block := rows * cols
+	for i := range result.data {
+		var err error
+		if len(axes) == 1 {
+			result.data[i], err = vectorNorm(vals[i*block:], 1, block, ord)
+		} else {
+			result.data[i], err = matrixNorm(rows, cols, vals[i*block:(i+1)*block], ord)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+/*
+ Returns the ord norm of the n elements of x, inc apart
+*/
+func vectorNorm(x []float64, inc, n int, ord NormOrd) (float64, error) {
+	if ord.kind != pNorm {
+		return 0, ErrInvalidNorm
+	}
+	p := ord.p
+	switch {
+	case p == 2:
+		return engine.Dnrm2(n, x, inc), nil
+	case p == 1:
+		return engine.Dasum(n, x, inc), nil
+	case math.IsInf(p, 1):
+		if n == 0 {
+			return 0, nil
+		}
+		return math.Abs(x[engine.Idamax(n, x, inc)*inc]), nil
+	case math.IsInf(p, -1):
+		norm := math.Inf(1)
+		for i := 0; i < n; i++ {
+			norm = math.Min(norm, math.Abs(x[i*inc]))
+		}
+		return norm, nil
+	case p == 0:
+		count := 0.0
+		for i := 0; i < n; i++ {
+			if x[i*inc] != 0 {
+				count++
+			}
+		}
+		return count, nil
+	case p > 0:
+		// scaling by the largest magnitude avoids overflow in |x|^p
+		if n == 0 {
+			return 0, nil
+		}
+		scale := math.Abs(x[engine.Idamax(n, x, inc)*inc])
+		if scale == 0 || math.IsInf(scale, 1) {
+			return scale, nil
+		}
+		return scale * powerSum(x, inc, n, scale, p), nil
+	case p < 0:
+		// for negative p scaling by the smallest magnitude avoids overflow,
+		// and a zero element makes the norm zero
+		if n == 0 {
+			return math.Inf(1), nil
+		}
+		scale := math.Inf(1)
+		for i := 0; i < n; i++ {
+			scale = math.Min(scale, math.Abs(x[i*inc]))
+		}
+		if scale == 0 || math.IsInf(scale, 1) {
+			return scale, nil
+		}
+		return scale * powerSum(x, inc, n, scale, p), nil
+	}
+	return 0, ErrInvalidNorm
+}
+
+/*
+ Returns sum(|x/scale|^p)^(1/p) over the n elements of x, inc apart
+*/
+func powerSum(x []float64, inc, n int, scale, p float64) float64 {
+	sum := 0.0
+	for i := 0; i < n; i++ {
+		sum += math.Pow(math.Abs(x[i*inc])/scale, p)
+	}
+	return math.Pow(sum, 1/p)
+}
+
+/*
+ Returns the ord norm of the rows by cols matrix a, row major
+*/
+func matrixNorm(rows, cols int, a []float64, ord NormOrd) (float64, error) {
+	p := ord.p
+	switch {
+	case ord == Fro:
+		return engine.Dnrm2(rows*cols, a, 1), nil
+	case ord == Spectral || ord == Ord(-2) || ord == Nuclear:
+		s, err := singularValues(rows, cols, a)
+		if err != nil {
+			return 0, err
+		}
+		if len(s) == 0 {
+			return 0, nil
+		}
+		if ord == Spectral {
+			return s[0], nil
+		}
+		if ord == Ord(-2) {
+			return s[len(s)-1], nil
+		}
+		sum := 0.0
+		for _, val := range s {
+			sum += val
+		}
+		return sum, nil
+	case ord == Ord(1) || ord == Ord(-1):
+		sums := make([]float64, cols)
+		for j := range sums {
+			sums[j] = engine.Dasum(rows, a[j:], max(cols, 1))
+		}
+		return extremum(sums, p > 0), nil
+	case ord.kind == pNorm && math.IsInf(p, 0):
+		sums := make([]float64, rows)
+		for i := range sums {
+			sums[i] = engine.Dasum(cols, a[i*cols:], 1)
+		}
+		return extremum(sums, p > 0), nil
+	}
+	return 0, ErrInvalidNorm
+}
+
+/*
+ Returns the largest of vals if largest is set and the smallest otherwise,
+ or zero if vals is empty
+*/
+func extremum(vals []float64, largest bool) float64 {
+	if len(vals) == 0 {
+		return 0
+	}
+	ext := vals[0]
+	for _, val := range vals[1:] {
+		if largest {
+			ext = math.Max(ext, val)
+		} else {
+			ext = math.Min(ext, val)
+		}
+	}
+	return ext
+}
+
+/*
+ Returns the singular values of the rows by cols matrix a in decreasing order,
+ or ErrNoConvergence
+*/
+func singularValues(rows, cols int, a []float64) ([]float64, error) {
+	if rows < cols {
+		t := make([]float64, rows*cols)
+		for i := 0; i < rows; i++ {
+			engine.Daxpy(cols, 1, a[i*cols:], 1, t[i:], rows)
+		}
+		rows, cols, a = cols, rows, t
+	}
+	_, s, _, ok := jacobiSVD(rows, cols, a, max(cols, 1))
+	if !ok {
+		return nil, ErrNoConvergence
+	}
+	return s, nil
+}
@@ -0,0 +1,75 @@
+package goSci
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestVectorNorm(t *testing.T) {
+	x := FromSlice([]float64{3, -4}, 2)
+	cases := []struct {
+		ord  NormOrd
+		want float64
+	}{
+		{Ord(1), 7},
+		{Ord(2), 5},
+		{Ord(3), math.Cbrt(91)},
+		{Ord(0), 2},
+		{Ord(math.Inf(1)), 4},
+		{Ord(math.Inf(-1)), 3},
+		{Ord(-1), 12.0 / 7},
+		{Ord(-2), 2.4},
+	}
+	for _, c := range cases {
+		if got := Norm(x, c.ord); math.Abs(got-c.want) > 1e-14 {
+			t.Errorf("ord %v: got %v, want %v", c.ord, got, c.want)
+		}
+	}
+	if got := Norm(FromSlice([]float64{0, 2}, 2), Ord(-1)); got != 0 {
+		t.Errorf("ord -1 with a zero element: got %v", got)
+	}
+	// the matrix norms are not defined for vectors
+	for _, ord := range []NormOrd{Fro, Nuclear} {
+		if _, err := TryNorm(x, ord); !errors.Is(err, ErrInvalidNorm) {
+			t.Errorf("ord %v of a vector: got %v", ord, err)
+		}
+		if _, err := TryNormAxis(FromSlice([]float64{3, 4, 0, 1}, 2, 2), ord, false, 0); !errors.Is(err, ErrInvalidNorm) {
+			t.Errorf("ord %v along an axis: got %v", ord, err)
+		}
+	}
+	// Fro and Nuclear are not orders p, however large
+	if got := Norm(x, Ord(math.MaxFloat64)); got != 4 {
+		t.Errorf("ord MaxFloat64: got %v, want 4", got)
+	}
+}
+
+func TestMatrixNorm(t *testing.T) {
+	// singular values 3 and 2
+	a := FromSlice([]float64{0, 3, -2, 0}, 2, 2)
+	b := FromSlice([]float64{1, -2, 3, 4}, 2, 2)
+	cases := []struct {
+		x    *GsArray
+		ord  NormOrd
+		want float64
+	}{
+		{b, Ord(1), 6},
+		{b, Ord(-1), 4},
+		{b, Ord(math.Inf(1)), 7},
+		{b, Ord(math.Inf(-1)), 3},
+		{b, Fro, math.Sqrt(30)},
+		{a, Spectral, 3},
+		{a, Ord(-2), 2},
+		{a, Nuclear, 5},
+	}
+	for _, c := range cases {
+		if got := Norm(c.x, c.ord); math.Abs(got-c.want) > 1e-12 {
+			t.Errorf("ord %v: got %v, want %v", c.ord, got, c.want)
+		}
+	}
+	if _, err := TryNorm(b, Ord(3)); !errors.Is(err, ErrInvalidNorm) {
+		t.Errorf("ord 3 of a matrix: got %v", err)
+	}
+	// along an axis the orders are vector orders
+	assertArray(t, "NormAxis ord -1", NormAxis(b, Ord(-1), false, 1), []int{2}, []float64{2.0 / 3, 12.0 / 7})
+}
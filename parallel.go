@@ -0,0 +1,218 @@
+package goSci
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+/*
+ Elementwise operations and reductions on arrays of at least
+ parallelThreshold elements are split into chunks run on a pool of worker
+ goroutines. The calling goroutine always works on the chunks too, and hands
+ them to idle workers only, so a function run on the pool may itself run
+ work on the pool.
+
+ Whole array reductions fold fixed blocks of reduceBlock elements and then
+ fold the partial results in order, and reductions over some of the axes
+ fold each output element in a single goroutine, so results do not depend
+ on the number of threads.
+*/
+const (
+	parallelThreshold = 1 << 15
+	reduceBlock       = 1 << 12
+)
+
+type workerPool struct {
+	size  int
+	tasks chan func()
+}
+
+var (
+	poolMu sync.RWMutex
+	pool   = newWorkerPool(runtime.GOMAXPROCS(0))
+)
+
+func newWorkerPool(size int) *workerPool {
+	p := &workerPool{size: size, tasks: make(chan func())}
+	// the goroutine that runs the work is one of the threads
+	for i := 1; i < size; i++ {
+		go func() {
+			for task := range p.tasks {
+				task()
+			}
+		}()
+	}
+	return p
+}
+
+/*
+ Sets the number of threads used by parallel operations and returns the
+ previous number. n <= 0 uses runtime.GOMAXPROCS(0) and n = 1 runs
+ everything on the calling goroutine.
+*/
+func SetNumThreads(n int) int {
+	if n <= 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	poolMu.Lock()
+	defer poolMu.Unlock()
+	old := pool
+	pool = newWorkerPool(n)
+	// workers of the old pool finish the work they hold and exit
+	close(old.tasks)
+	return old.size
+}
+
+/*
+ Returns the number of threads used by parallel operations
+*/
+func NumThreads() int {
+	poolMu.RLock()
+	defer poolMu.RUnlock()
+	return pool.size
+}
+
+/*
+ Calls body over the range 0 to n - 1 split into contiguous ranges run in
+ parallel on the worker pool, so body must be safe to call concurrently on
+ disjoint ranges. No further ranges are started once ctx is done, in which
+ case ctx.Err() is returned; ranges already started run to completion.
+ e.g.
+ err := ParallelFor(ctx, len(images), func(start, end int) {
+ 	for i := start; i < end; i++ {
+ 		process(images[i])
+ 	}
+ })
+*/
+func ParallelFor(ctx context.Context, n int, body func(start, end int)) error {
+	return runChunks(ctx, n, body)
+}
+
+/*
+ Calls body over the range 0 to n - 1 as ParallelFor does when work, the
+ number of elements touched, is at least parallelThreshold, and over the
+ whole range on the calling goroutine otherwise
+*/
+func parallelFor(n, work int, body func(start, end int)) {
+	if work < parallelThreshold || n < 2 {
+		body(0, n)
+		return
+	}
+	runChunks(context.Background(), n, body)
+}
+
+/*
+ Splits the range 0 to n - 1 into a few chunks per thread and runs them on
+ the pool. A panic in body is raised again on the calling goroutine.
+*/
+func runChunks(ctx context.Context, n int, body func(start, end int)) error {
+	poolMu.RLock()
+	p := pool
+	poolMu.RUnlock()
+	chunks := min(n, 4*p.size)
+	if chunks <= 0 {
+		return ctx.Err()
+	}
+	var next, done atomic.Int64
+	var wg sync.WaitGroup
+	var failure atomic.Pointer[any]
+	claim := func() {
+		defer func() {
+			if r := recover(); r != nil {
+				failure.CompareAndSwap(nil, &r)
+			}
+		}()
+		for ctx.Err() == nil && failure.Load() == nil {
+			c := int(next.Add(1)) - 1
+			if c >= chunks {
+				return
+			}
+			body(c*n/chunks, (c+1)*n/chunks)
+			done.Add(1)
+		}
+	}
+	poolMu.RLock()
+	if p == pool {
+	submit:
+		for i := 1; i < min(p.size, chunks); i++ {
+			wg.Add(1)
+			select {
+			case p.tasks <- func() { defer wg.Done(); claim() }:
+			default:
+				// no idle worker, the chunks left run on the calling goroutine
+				wg.Done()
+				break submit
+			}
+		}
+	}
+	poolMu.RUnlock()
+	claim()
+	wg.Wait()
+	if r := failure.Load(); r != nil {
+		panic(*r)
+	}
+	if int(done.Load()) < chunks {
+		return ctx.Err()
+	}
+	return nil
+}
+
+/*
+ Walks shape as walkStrided does, with the positions along axis split across
+ the worker pool when shape holds at least parallelThreshold elements. visit
+ may then be called concurrently and must not depend on the order of the
+ positions. An axis of -1 walks on the calling goroutine.
+*/
+func parallelWalk(shape []int, axis int, offsets []int, strides [][]int, visit func(pos []int)) {
+	if axis < 0 || shapeSize(shape) < parallelThreshold {
+		walkStrided(shape, offsets, strides, visit)
+		return
+	}
+	parallelFor(shape[axis], shapeSize(shape), func(start, end int) {
+		sub := append([]int(nil), shape...)
+		sub[axis] = end - start
+		subOffsets := make([]int, len(offsets))
+		for k, offset := range offsets {
+			subOffsets[k] = offset + start*strides[k][axis]
+		}
+		walkStrided(sub, subOffsets, strides, visit)
+	})
+}
+
+/*
+ Returns the first axis of shape longer than one, the axis to split a walk
+ along, or -1 if there is none
+*/
+func splitAxis(shape []int) int {
+	for axis, dim := range shape {
+		if dim > 1 {
+			return axis
+		}
+	}
+	return -1
+}
+
+/*
+ Returns the fold of op over vals from init, folding blocks of reduceBlock
+ elements in parallel and then their results in order
+*/
+func foldBlocks(vals []float64, init float64, op func(acc, val float64) float64) float64 {
+	blocks := (len(vals) + reduceBlock - 1) / reduceBlock
+	partials := make([]float64, blocks)
+	parallelFor(blocks, len(vals), func(start, end int) {
+		for b := start; b < end; b++ {
+			acc := init
+			for _, val := range vals[b*reduceBlock : min((b+1)*reduceBlock, len(vals))] {
+				acc = op(acc, val)
+			}
+			partials[b] = acc
+		}
+	})
+	acc := init
+	for _, partial := range partials {
+		acc = op(acc, partial)
+	}
+	return acc
+}
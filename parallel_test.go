@@ -0,0 +1,121 @@
+package goSci
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSumThreadIndependent(t *testing.T) {
+	defer SetNumThreads(SetNumThreads(1))
+	r := rand.New(rand.NewSource(23))
+	n := 1<<20 + 17
+	vals := make([]float64, n)
+	for i := range vals {
+		// magnitudes over many decades, so the order of the additions matters
+		vals[i] = r.NormFloat64() * math.Pow(10, float64(r.Intn(12)))
+	}
+	x := FromSlice(vals, n)
+	m := FromSlice(vals[:1000*1024], 1000, 1024)
+
+	var sum float64
+	var rows, cols []float64
+	for k, threads := range []int{1, 2, 3, 8, 0} {
+		SetNumThreads(threads)
+		s := Sum(x, ALL).data[0]
+		rs := SumAxis(m, false, 1).Data()
+		cs := SumAxis(m.T(), false, 0).Data()
+		if k == 0 {
+			sum, rows, cols = s, rs, cs
+			continue
+		}
+		if math.Float64bits(s) != math.Float64bits(sum) {
+			t.Errorf("Sum with %d threads: %v, with one thread %v", threads, s, sum)
+		}
+		for i := range rows {
+			if math.Float64bits(rs[i]) != math.Float64bits(rows[i]) || math.Float64bits(cs[i]) != math.Float64bits(cols[i]) {
+				t.Fatalf("SumAxis with %d threads differs at %d", threads, i)
+			}
+		}
+	}
+	// an axis sum folds each output element in order
+	for i := range rows {
+		s := 0.0
+		for _, val := range vals[i*1024 : (i+1)*1024] {
+			s += val
+		}
+		if s != rows[i] || s != cols[i] {
+			t.Fatalf("SumAxis of row %d: %v and %v, want %v", i, rows[i], cols[i], s)
+		}
+	}
+}
+
+func TestParallelElementwise(t *testing.T) {
+	defer SetNumThreads(SetNumThreads(4))
+	x := Arange(300 * 400)
+	x.Reshape(300, 400)
+
+	assertClose(t, "Plus", Plus(x, Ones(400)), reshaped(Plus(Arange(300*400), Ones(1)), []int{300, 400}), 0)
+	xt := x.T()
+	if got := Times(xt, 2).Get(5, 7); got != 2*x.Get(7, 5) {
+		t.Errorf("Times of a transpose: got %v", got)
+	}
+	if got := Sqrt(xt).Get(3, 2); got != math.Sqrt(x.Get(2, 3)) {
+		t.Errorf("Sqrt of a transpose: got %v", got)
+	}
+	xt.SqrtInPlace()
+	if got := x.Get(2, 3); got != math.Sqrt(803) {
+		t.Errorf("SqrtInPlace of a transpose: got %v", got)
+	}
+
+	var calls atomic.Int64
+	ArrayFun(x, func(val float64) float64 {
+		calls.Add(1)
+		return val
+	})
+	if calls.Load() != int64(x.Size()) {
+		t.Errorf("ArrayFun called f %d times for %d elements", calls.Load(), x.Size())
+	}
+}
+
+func TestParallelFor(t *testing.T) {
+	defer SetNumThreads(SetNumThreads(4))
+
+	var covered atomic.Int64
+	if err := ParallelFor(context.Background(), 1000, func(start, end int) {
+		covered.Add(int64(end - start))
+	}); err != nil || covered.Load() != 1000 {
+		t.Errorf("ParallelFor covered %d of 1000: %v", covered.Load(), err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ArrayFunContext(ctx, Ones(1<<16), math.Sin); !errors.Is(err, context.Canceled) {
+		t.Errorf("ArrayFunContext with a cancelled context: got %v", err)
+	}
+	ctx, cancel = context.WithCancel(context.Background())
+	var started atomic.Int64
+	err := ParallelFor(ctx, 1000, func(start, end int) {
+		if started.Add(1) == 1 {
+			cancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ParallelFor cancelled in its first range: got %v", err)
+	}
+
+	// work run on the pool may itself use the pool
+	ParallelFor(context.Background(), 8, func(start, end int) {
+		Sum(Ones(1<<16), ALL)
+	})
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("panic in ParallelFor: recovered %v", r)
+		}
+	}()
+	ParallelFor(context.Background(), 8, func(start, end int) { panic("boom") })
+}
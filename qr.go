@@ -0,0 +1,221 @@
+package goSci
+
+import "math"
+
+/*
+ The QR decomposition of an m by n matrix computed with Householder
+ reflections, A = Q*R, or A*P = Q*R when columns are pivoted, where Q is
+ orthogonal, R upper triangular and P a permutation.
+*/
+type QR struct {
+	qr   *GsArray  // R on and above the diagonal, the Householder vectors below it
+	tau  []float64 // scale of each reflection, H = I - tau*v*v^T
+	perm []int     // column j of A*P is column perm[j] of A, nil without pivoting
+}
+
+/*
+ Returns the QR decomposition of the matrix a
+*/
+func NewQR(a *GsArray) (*QR, error) {
+	return newQR(a, false)
+}
+
+/*
+ Returns the QR decomposition of the matrix a with column pivoting, so that
+ the diagonal of R decreases in magnitude and reveals the rank of a
+*/
+func NewQRPivot(a *GsArray) (*QR, error) {
+	return newQR(a, true)
+}
+
+func newQR(a *GsArray, pivot bool) (*QR, error) {
+	if len(a.shape) != 2 {
+		return nil, shapeError("NewQR", a.shape, nil)
+	}
+	m, n := a.shape[0], a.shape[1]
+	f := &QR{qr: a.Copy(), tau: make([]float64, min(m, n))}
+	if pivot {
+		f.perm = make([]int, n)
+		for j := range f.perm {
+			f.perm[j] = j
+		}
+	}
+	data, lda := f.qr.data, max(n, 1)
+	for k := 0; k < min(m, n); k++ {
+		if pivot {
+			best, bestNorm := k, -1.0
+			for j := k; j < n; j++ {
+				if norm := engine.Dnrm2(m-k, data[k*lda+j:], lda); norm > bestNorm {
+					best, bestNorm = j, norm
+				}
+			}
+			if best != k {
+				swapCols(data, lda, m, k, best)
+				f.perm[k], f.perm[best] = f.perm[best], f.perm[k]
+			}
+		}
+		f.tau[k] = householder(m-k, data[k*lda+k:], lda)
+		if k+1 < n {
+			applyReflector(m-k, n-k-1, f.tau[k], data[k*lda+k:], lda, data[k*lda+k+1:], lda)
+		}
+	}
+	return f, nil
+}
+
+/*
+ Returns the orthogonal factor Q, m by m, or m by min(m, n) for the economy
+ decomposition
+*/
+func (f *QR) Q(economy bool) *GsArray {
+	m, n := f.qr.shape[0], f.qr.shape[1]
+	k := min(m, n)
+	cols := m
+	if economy {
+		cols = k
+	}
+	q := Zeros(m, cols)
+	for i := 0; i < min(m, cols); i++ {
+		q.data[i*cols+i] = 1
+	}
+	lda := max(n, 1)
+	for i := k - 1; i >= 0; i-- {
+		applyReflector(m-i, cols-i, f.tau[i], f.qr.data[i*lda+i:], lda, q.data[i*cols+i:], max(cols, 1))
+	}
+	return q
+}
+
+/*
+ Returns the upper triangular factor R, m by n, or min(m, n) by n for the
+ economy decomposition
+*/
+func (f *QR) R(economy bool) *GsArray {
+	m, n := f.qr.shape[0], f.qr.shape[1]
+	rows := m
+	if economy {
+		rows = min(m, n)
+	}
+	r := Zeros(rows, n)
+	for i := 0; i < min(rows, n); i++ {
+		copy(r.data[i*n+i:(i+1)*n], f.qr.data[i*n+i:(i+1)*n])
+	}
+	return r
+}
+
+/*
+ Returns the permutation matrix P, the identity without column pivoting
+*/
+func (f *QR) P() *GsArray {
+	n := f.qr.shape[1]
+	if f.perm == nil {
+		return Eye(n)
+	}
+	p := Zeros(n, n)
+	for j, col := range f.perm {
+		p.data[col*n+j] = 1
+	}
+	return p
+}
+
+/*
+ Returns x minimizing the 2-norm of A*x - b for a matrix A with at least as
+ many rows as columns. b may be a vector or a matrix whose columns are
+ separate right hand sides. Returns ErrSingular if A does not have full column
+ rank, see Lstsq for rank deficient problems.
+*/
+func (f *QR) Solve(b *GsArray) (*GsArray, error) {
+	m, n := f.qr.shape[0], f.qr.shape[1]
+	if m < n || len(b.shape) == 0 || len(b.shape) > 2 || b.shape[0] != m {
+		return nil, shapeError("Solve", f.qr.shape, b.shape)
+	}
+	for i := 0; i < n; i++ {
+		if f.qr.data[i*n+i] == 0 {
+			return nil, ErrSingular
+		}
+	}
+	c := f.applyQT(b)
+	nrhs := c.shape[1]
+	engine.Dtrsm(Left, Upper, false, NonUnit, n, nrhs, 1, f.qr.data, max(n, 1), c.data, max(nrhs, 1))
+	x := Zeros(n, nrhs)
+	f.unpermute(c.data[:n*nrhs], x.data, nrhs)
+	if len(b.shape) == 1 {
+		x.shape = []int{n}
+	}
+	return x, nil
+}
+
+/*
+ Returns Q^T*b as an m by nrhs matrix
+*/
+func (f *QR) applyQT(b *GsArray) *GsArray {
+	m, n := f.qr.shape[0], f.qr.shape[1]
+	c := b.Copy()
+	if len(c.shape) == 1 {
+		c.shape = []int{m, 1}
+	}
+	nrhs := c.shape[1]
+	for i := 0; i < len(f.tau); i++ {
+		applyReflector(m-i, nrhs, f.tau[i], f.qr.data[i*max(n, 1)+i:], max(n, 1), c.data[i*nrhs:], max(nrhs, 1))
+	}
+	return c
+}
+
+/*
+ Copies the rows of z, the solution for A*P, into x, the solution for A
+*/
+func (f *QR) unpermute(z, x []float64, nrhs int) {
+	for j := 0; j*nrhs < len(z); j++ {
+		row := j
+		if f.perm != nil {
+			row = f.perm[j]
+		}
+		copy(x[row*nrhs:(row+1)*nrhs], z[j*nrhs:(j+1)*nrhs])
+	}
+}
+
+/*
+ Replaces the n elements of x, inc apart, by beta*e1 where the Householder
+ reflection H = I - tau*v*v^T maps x to beta*e1. The tail of v is stored in
+ place of x[1:], its first element is implicitly 1, and tau is returned. A tau
+ of zero means H is the identity.
+*/
+func householder(n int, x []float64, inc int) float64 {
+	if n <= 1 {
+		return 0
+	}
+	alpha := x[0]
+	xnorm := engine.Dnrm2(n-1, x[inc:], inc)
+	if xnorm == 0 {
+		return 0
+	}
+	beta := -math.Copysign(math.Hypot(alpha, xnorm), alpha)
+	engine.Dscal(n-1, 1/(alpha-beta), x[inc:], inc)
+	x[0] = beta
+	return (beta - alpha) / beta
+}
+
+/*
+ Applies H = I - tau*v*v^T from the left to the rows by cols matrix C, where
+ v has rows elements, inc apart, with an implicit first element of 1
+*/
+func applyReflector(rows, cols int, tau float64, v []float64, inc int, c []float64, ldc int) {
+	if tau == 0 || rows == 0 || cols == 0 {
+		return
+	}
+	vv := make([]float64, rows)
+	vv[0] = 1
+	for i := 1; i < rows; i++ {
+		vv[i] = v[i*inc]
+	}
+	w := make([]float64, cols)
+	engine.Dgemv(true, rows, cols, 1, c, ldc, vv, 1, 0, w, 1)
+	engine.Dger(rows, cols, -tau, vv, 1, w, 1, c, ldc)
+}
+
+/*
+ Interchanges the first m elements of columns i and j of a
+*/
+func swapCols(a []float64, lda, m, i, j int) {
+	for r := 0; r < m; r++ {
+		a[r*lda+i], a[r*lda+j] = a[r*lda+j], a[r*lda+i]
+	}
+}
@@ -0,0 +1,172 @@
+package goSci
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestQR(t *testing.T) {
+	r := rand.New(rand.NewSource(9))
+	for _, dims := range [][2]int{{6, 4}, {4, 6}, {5, 5}, {1, 3}, {4, 1}} {
+		m, n := dims[0], dims[1]
+		a := randomArray(r, m, n)
+		for _, pivot := range []bool{false, true} {
+			f, err := newQR(a, pivot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ap := MatMult(a, f.P())
+			for _, economy := range []bool{false, true} {
+				q, rf := f.Q(economy), f.R(economy)
+				k := m
+				if economy {
+					k = min(m, n)
+				}
+				if !sameShape(q.shape, []int{m, k}) || !sameShape(rf.shape, []int{k, n}) {
+					t.Fatalf("%v economy %v: Q is %v and R %v", dims, economy, q.shape, rf.shape)
+				}
+				assertClose(t, "Q*R", MatMult(q, rf), ap, 1e-12)
+				assertClose(t, "Q^T*Q", MatMult(q.T(), q), Eye(k), 1e-12)
+				for i := 0; i < k; i++ {
+					for j := 0; j < min(i, n); j++ {
+						if rf.Get(i, j) != 0 {
+							t.Fatalf("R is not upper triangular at %d, %d", i, j)
+						}
+					}
+				}
+			}
+			if !pivot {
+				assertClose(t, "P without pivoting", f.P(), Eye(n), 0)
+				continue
+			}
+			rf := f.R(true)
+			for i := 1; i < min(m, n); i++ {
+				if math.Abs(rf.Get(i, i)) > math.Abs(rf.Get(i-1, i-1)) {
+					t.Errorf("%v: the diagonal of R increases at %d", dims, i)
+				}
+			}
+		}
+	}
+
+	if _, err := NewQR(Zeros(3)); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("NewQR of a vector: got %v", err)
+	}
+}
+
+func TestQRSolve(t *testing.T) {
+	r := rand.New(rand.NewSource(10))
+	a := randomArray(r, 7, 4)
+	b := randomArray(r, 7)
+	for _, newF := range []func(*GsArray) (*QR, error){NewQR, NewQRPivot} {
+		f, _ := newF(a)
+		x, err := f.Solve(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sameShape(x.shape, []int{4}) {
+			t.Fatalf("Solve of a vector: got shape %v", x.shape)
+		}
+		// the residual of a least squares solution is orthogonal to the columns of a
+		resid := Minus(b, reshaped(MatMult(a, reshaped(x, []int{4, 1})), []int{7}))
+		assertClose(t, "A^T*(b - A*x)", reshaped(MatMult(a.T(), reshaped(resid, []int{7, 1})), []int{4}), Zeros(4), 1e-12)
+
+		bs := randomArray(r, 7, 3)
+		xs, err := f.Solve(bs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertClose(t, "A^T*(B - A*X)", MatMult(a.T(), Minus(bs, MatMult(a, xs))), Zeros(4, 3), 1e-12)
+	}
+
+	// a square system is solved exactly
+	sq := randomArray(r, 5, 5)
+	f, _ := NewQRPivot(sq)
+	x, _ := f.Solve(Ones(5, 1))
+	assertClose(t, "A*x", MatMult(sq, x), Ones(5, 1), 1e-12)
+
+	// a zero column leaves an exact zero on the diagonal of R
+	deficient := randomArray(r, 5, 3)
+	deficient.Slice(All, At(1)).TimesEquals(0)
+	f, _ = NewQR(deficient)
+	if _, err := f.Solve(Ones(5)); !errors.Is(err, ErrSingular) {
+		t.Errorf("Solve with a zero column: got %v, want ErrSingular", err)
+	}
+	f, _ = NewQR(randomArray(r, 3, 5))
+	if _, err := f.Solve(Ones(3)); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("Solve with more columns than rows: got %v", err)
+	}
+	f, _ = NewQR(a)
+	if _, err := f.Solve(Ones(6)); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("Solve with the wrong length of b: got %v", err)
+	}
+}
+
+func TestLstsq(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+
+	// overdetermined with full column rank
+	a := randomArray(r, 8, 3)
+	b := randomArray(r, 8, 2)
+	res, err := Lstsq(a, b, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Rank != 3 {
+		t.Errorf("Rank: got %d, want 3", res.Rank)
+	}
+	resid := Minus(b, MatMult(a, res.X))
+	assertClose(t, "A^T*(B - A*X)", MatMult(a.T(), resid), Zeros(3, 2), 1e-12)
+	assertClose(t, "Residuals", res.Residuals, SumAxis(ElemTimes(resid, resid), false, 0), 1e-12)
+	_, s, _, _ := SVD(a, false)
+	assertClose(t, "S", res.S, s, 1e-12)
+
+	// a vector b gives a vector x and a single residual
+	res, _ = Lstsq(a, b.Slice(All, At(1)), 0)
+	if !sameShape(res.X.shape, []int{3}) || !sameShape(res.Residuals.shape, []int{1}) {
+		t.Errorf("Lstsq of a vector: X is %v and Residuals %v", res.X.shape, res.Residuals.shape)
+	}
+
+	// the third column is the sum of the first two, and the solution is the
+	// one of minimum norm
+	deficient := randomArray(r, 6, 4)
+	deficient.Slice(All, At(2)).setValues(Plus(deficient.Slice(All, At(0)), deficient.Slice(All, At(1))).data)
+	b = randomArray(r, 6, 1)
+	res, err = Lstsq(deficient, b, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Rank != 3 {
+		t.Errorf("Rank of a deficient matrix: got %d, want 3", res.Rank)
+	}
+	if res.Residuals.Size() != 0 {
+		t.Errorf("Residuals of a deficient matrix: got %v, want none", res.Residuals)
+	}
+	pinv, _ := Pinv(deficient, 0)
+	assertClose(t, "minimum norm X", res.X, MatMult(pinv, b), 1e-10)
+
+	// underdetermined, solved exactly by the solution of minimum norm
+	wide := randomArray(r, 3, 6)
+	b = randomArray(r, 3, 2)
+	res, err = Lstsq(wide, b, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Rank != 3 || res.Residuals.Size() != 0 {
+		t.Errorf("underdetermined: Rank %d and Residuals %v", res.Rank, res.Residuals)
+	}
+	assertClose(t, "A*X", MatMult(wide, res.X), b, 1e-12)
+	// the minimum norm solution is in the row space of a, X = A^T*(A*A^T)^-1*B
+	y, _ := Solve(MatMult(wide, wide.T()), b)
+	assertClose(t, "A^T*(A*A^T)^-1*B", res.X, MatMult(wide.T(), y), 1e-10)
+
+	// singular values no larger than rcond times the largest are dropped
+	res, _ = Lstsq(a, Ones(8), s.Get(1)/s.Get(0))
+	if res.Rank != 1 {
+		t.Errorf("Rank with a large rcond: got %d, want 1", res.Rank)
+	}
+	if _, err := Lstsq(a, Ones(7), 0); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("Lstsq with the wrong length of b: got %v", err)
+	}
+}
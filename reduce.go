@@ -0,0 +1,419 @@
+package goSci
+
+import "math"
+
+/*
+ Reductions over any set of axes of an array of any rank. Every function
+ takes the axes to reduce over, all of them when none are given, and a
+ keepDims flag which keeps the reduced axes in the result with length one
+ so that it broadcasts against x.
+*/
+
+/*
+ Returns the sum of x over axes
+*/
+func SumAxis(x *GsArray, keepDims bool, axes ...int) *GsArray {
+	return must(TrySumAxis(x, keepDims, axes...))
+}
+
+/*
+ Same as SumAxis but returns an InvalidAxisError instead of panicking
+*/
+func TrySumAxis(x *GsArray, keepDims bool, axes ...int) (*GsArray, error) {
+	return reduce(x, keepDims, axes, 0, add)
+}
+
+/*
+ Returns the product of x over axes
+*/
+func ProdAxis(x *GsArray, keepDims bool, axes ...int) *GsArray {
+	return must(TryProdAxis(x, keepDims, axes...))
+}
+
+/*
+ Same as ProdAxis but returns an InvalidAxisError instead of panicking
+*/
+func TryProdAxis(x *GsArray, keepDims bool, axes ...int) (*GsArray, error) {
+	return reduce(x, keepDims, axes, 1, mul)
+}
+
+/*
+ Returns the minimum of x over axes. NaNs are propagated.
+ Panics if an axis being reduced has length zero.
+*/
+func MinAxis(x *GsArray, keepDims bool, axes ...int) *GsArray {
+	return must(TryMinAxis(x, keepDims, axes...))
+}
+
+/*
+ Same as MinAxis but returns an error instead of panicking
+*/
+func TryMinAxis(x *GsArray, keepDims bool, axes ...int) (*GsArray, error) {
+	return reduceNonEmpty("MinAxis", x, keepDims, axes, math.Inf(1), func(acc, val float64) float64 {
+		if val < acc || math.IsNaN(val) {
+			return val
+		}
+		return acc
+	})
+}
+
+/*
+ Returns the maximum of x over axes. NaNs are propagated.
+ Panics if an axis being reduced has length zero.
+*/
+func MaxAxis(x *GsArray, keepDims bool, axes ...int) *GsArray {
+	return must(TryMaxAxis(x, keepDims, axes...))
+}
+
+/*
+ Same as MaxAxis but returns an error instead of panicking
+*/
+func TryMaxAxis(x *GsArray, keepDims bool, axes ...int) (*GsArray, error) {
+	return reduceNonEmpty("MaxAxis", x, keepDims, axes, math.Inf(-1), func(acc, val float64) float64 {
+		if val > acc || math.IsNaN(val) {
+			return val
+		}
+		return acc
+	})
+}
+
+/*
+ Returns 1 where any element of x over axes is non-zero and 0 elsewhere
+*/
+func AnyAxis(x *GsArray, keepDims bool, axes ...int) *GsArray {
+	return must(TryAnyAxis(x, keepDims, axes...))
+}
+
+/*
+ Same as AnyAxis but returns an InvalidAxisError instead of panicking
+*/
+func TryAnyAxis(x *GsArray, keepDims bool, axes ...int) (*GsArray, error) {
+	return reduce(x, keepDims, axes, 0, func(acc, val float64) float64 {
+		if val != 0 {
+			return 1
+		}
+		return acc
+	})
+}
+
+/*
+ Returns 1 where every element of x over axes is non-zero and 0 elsewhere
+*/
+func AllAxis(x *GsArray, keepDims bool, axes ...int) *GsArray {
+	return must(TryAllAxis(x, keepDims, axes...))
+}
+
+/*
+ Same as AllAxis but returns an InvalidAxisError instead of panicking
+*/
+func TryAllAxis(x *GsArray, keepDims bool, axes ...int) (*GsArray, error) {
+	return reduce(x, keepDims, axes, 1, func(acc, val float64) float64 {
+		if val == 0 {
+			return 0
+		}
+		return acc
+	})
+}
+
+/*
+ Returns the mean of x over axes
+*/
+func MeanAxis(x *GsArray, keepDims bool, axes ...int) *GsArray {
+	return must(TryMeanAxis(x, keepDims, axes...))
+}
+
+/*
+ Same as MeanAxis but returns an InvalidAxisError instead of panicking
+*/
+func TryMeanAxis(x *GsArray, keepDims bool, axes ...int) (*GsArray, error) {
+	sum, err := TrySumAxis(x, keepDims, axes...)
+	if err != nil {
+		return nil, err
+	}
+	sum.TimesEquals(float64(sum.Size()) / float64(x.Size()))
+	return sum, nil
+}
+
+/*
+ Returns the variance of x over axes, dividing the sum of squared deviations
+ by the number of elements minus ddof. A ddof of 0 gives the population
+ variance and 1 the sample variance.
+*/
+func VarAxis(x *GsArray, ddof int, keepDims bool, axes ...int) *GsArray {
+	return must(TryVarAxis(x, ddof, keepDims, axes...))
+}
+
+/*
+ Same as VarAxis but returns an InvalidAxisError instead of panicking
+*/
+func TryVarAxis(x *GsArray, ddof int, keepDims bool, axes ...int) (*GsArray, error) {
+	mean, err := TryMeanAxis(x, true, axes...)
+	if err != nil {
+		return nil, err
+	}
+	diff := Minus(x, mean)
+	diff.ElemTimesEquals(diff)
+	sum, err := TrySumAxis(diff, keepDims, axes...)
+	if err != nil {
+		return nil, err
+	}
+	count := x.Size() / max(mean.Size(), 1)
+	sum.TimesEquals(1 / float64(count-ddof))
+	return sum, nil
+}
+
+/*
+ Returns the standard deviation of x over axes, see VarAxis for ddof
+*/
+func StdAxis(x *GsArray, ddof int, keepDims bool, axes ...int) *GsArray {
+	return must(TryStdAxis(x, ddof, keepDims, axes...))
+}
+
+/*
+ Same as StdAxis but returns an InvalidAxisError instead of panicking
+*/
+func TryStdAxis(x *GsArray, ddof int, keepDims bool, axes ...int) (*GsArray, error) {
+	variance, err := TryVarAxis(x, ddof, keepDims, axes...)
+	if err != nil {
+		return nil, err
+	}
+	return ArrayFun(variance, math.Sqrt), nil
+}
+
+/*
+ Returns the index of the first minimum of x along axis. With no axis the
+ index is into x flattened in row major order. At most one axis may be given.
+*/
+func ArgMinAxis(x *GsArray, keepDims bool, axis ...int) *GsArray {
+	return must(TryArgMinAxis(x, keepDims, axis...))
+}
+
+/*
+ Same as ArgMinAxis but returns an error instead of panicking
+*/
+func TryArgMinAxis(x *GsArray, keepDims bool, axis ...int) (*GsArray, error) {
+	return argReduce("ArgMinAxis", x, keepDims, axis, func(val, best float64) bool { return val < best })
+}
+
+/*
+ Returns the index of the first maximum of x along axis. With no axis the
+ index is into x flattened in row major order. At most one axis may be given.
+*/
+func ArgMaxAxis(x *GsArray, keepDims bool, axis ...int) *GsArray {
+	return must(TryArgMaxAxis(x, keepDims, axis...))
+}
+
+/*
+ Same as ArgMaxAxis but returns an error instead of panicking
+*/
+func TryArgMaxAxis(x *GsArray, keepDims bool, axis ...int) (*GsArray, error) {
+	return argReduce("ArgMaxAxis", x, keepDims, axis, func(val, best float64) bool { return val > best })
+}
+
+/*
+ Returns the cumulative sum of x along axis, with the same shape as x
+*/
+func CumSumAxis(x *GsArray, axis int) *GsArray {
+	return must(TryCumSumAxis(x, axis))
+}
+
+/*
+ Same as CumSumAxis but returns an InvalidAxisError instead of panicking
+*/
+func TryCumSumAxis(x *GsArray, axis int) (*GsArray, error) {
+	return accumulate(x, axis, add)
+}
+
+/*
+ Returns the cumulative product of x along axis, with the same shape as x
+*/
+func CumProdAxis(x *GsArray, axis int) *GsArray {
+	return must(TryCumProdAxis(x, axis))
+}
+
+/*
+ Same as CumProdAxis but returns an InvalidAxisError instead of panicking
+*/
+func TryCumProdAxis(x *GsArray, axis int) (*GsArray, error) {
+	return accumulate(x, axis, mul)
+}
+
+/*
+ Returns which axes of x are reduced, all of them when axes is empty
+*/
+func reducedAxes(x *GsArray, axes []int) ([]bool, error) {
+	reduced := make([]bool, len(x.shape))
+	if len(axes) == 0 {
+		for i := range reduced {
+			reduced[i] = true
+		}
+		return reduced, nil
+	}
+	for _, axis := range axes {
+		i, err := checkAxis(axis, len(x.shape))
+		if err != nil {
+			return nil, err
+		}
+		if reduced[i] {
+			return nil, &InvalidAxisError{Axis: axis, NDim: len(x.shape)}
+		}
+		reduced[i] = true
+	}
+	return reduced, nil
+}
+
+/*
+ Returns the shape of a reduction of an array of shape shape, with the
+ reduced axes set to one when keepDims is set and dropped otherwise
+*/
+func reducedShape(shape []int, reduced []bool, keepDims bool) []int {
+	result := make([]int, 0, len(shape))
+	for i, dim := range shape {
+		if !reduced[i] {
+			result = append(result, dim)
+		} else if keepDims {
+			result = append(result, 1)
+		}
+	}
+	return result
+}
+
+/*
+ Folds op over the elements of x along the reduced axes, starting from init.
+ The result is read as broadcast to the shape of x, giving it a zero stride
+ along the reduced axes, so that every element of x is visited once in row
+ major order and added into its place in the result. Large arrays are split
+ along a kept axis so each element of the result is folded by one goroutine,
+ and reductions to a single element fold blocks, see foldBlocks.
+*/
+func reduce(x *GsArray, keepDims bool, axes []int, init float64, op func(acc, val float64) float64) (*GsArray, error) {
+	reduced, err := reducedAxes(x, axes)
+	if err != nil {
+		return nil, err
+	}
+	result := Zeros(reducedShape(x.shape, reduced, true)...)
+	for i := range result.data {
+		result.data[i] = init
+	}
+	if len(result.data) == 1 {
+		result.data[0] = foldBlocks(x.values(), init, op)
+	} else {
+		split := -1
+		for axis, dim := range x.shape {
+			if !reduced[axis] && dim > 1 {
+				split = axis
+				break
+			}
+		}
+		strides := [][]int{x.getStrides(), broadcastStrides(result, x.shape)}
+		parallelWalk(x.shape, split, []int{x.offset, 0}, strides, func(pos []int) {
+			result.data[pos[1]] = op(result.data[pos[1]], x.data[pos[0]])
+		})
+	}
+	if !keepDims {
+		result.shape = reducedShape(x.shape, reduced, false)
+	}
+	return result, nil
+}
+
+/*
+ Same as reduce for operations without an identity, which are undefined
+ when a reduced axis has length zero
+*/
+func reduceNonEmpty(name string, x *GsArray, keepDims bool, axes []int, init float64, op func(acc, val float64) float64) (*GsArray, error) {
+	reduced, err := reducedAxes(x, axes)
+	if err != nil {
+		return nil, err
+	}
+	for i, dim := range x.shape {
+		if reduced[i] && dim == 0 {
+			return nil, shapeError(name, x.shape, nil)
+		}
+	}
+	return reduce(x, keepDims, axes, init, op)
+}
+
+/*
+ Calls visit once for every one dimensional lane of the arrays along axis.
+ The arrays must share a shape. pos holds the data index of the start of the
+ lane in each array, strides their step along axis and n the lane length.
+*/
+func forEachLane(axis int, arrays []*GsArray, visit func(pos, strides []int, n int)) {
+	shape := arrays[0].shape
+	outer := make([]int, 0, len(shape))
+	outer = append(append(outer, shape[:axis]...), shape[axis+1:]...)
+	offsets := make([]int, len(arrays))
+	outerStrides := make([][]int, len(arrays))
+	laneStrides := make([]int, len(arrays))
+	for k, x := range arrays {
+		strides := x.getStrides()
+		offsets[k] = x.offset
+		outerStrides[k] = append(append([]int{}, strides[:axis]...), strides[axis+1:]...)
+		laneStrides[k] = strides[axis]
+	}
+	walkStrided(outer, offsets, outerStrides, func(pos []int) {
+		visit(pos, laneStrides, shape[axis])
+	})
+}
+
+/*
+ Returns the index along a single axis, or into the flattened array, of the
+ first element for which better(val, best) holds against every earlier one.
+ A NaN is always taken as the result, as in numpy.
+*/
+func argReduce(name string, x *GsArray, keepDims bool, axis []int, better func(val, best float64) bool) (*GsArray, error) {
+	if len(axis) > 1 {
+		return nil, &InvalidAxisError{Axis: axis[1], NDim: len(x.shape)}
+	}
+	src, lane := x, 0
+	if len(axis) == 0 {
+		src = x.Copy()
+		src.shape = []int{x.Size()}
+	} else {
+		var err error
+		if lane, err = checkAxis(axis[0], len(x.shape)); err != nil {
+			return nil, err
+		}
+	}
+	if src.shape[lane] == 0 {
+		return nil, shapeError(name, x.shape, nil)
+	}
+	reduced, _ := reducedAxes(x, axis)
+	result := Zeros(reducedShape(x.shape, reduced, keepDims)...)
+	i := 0
+	forEachLane(lane, []*GsArray{src}, func(pos, strides []int, n int) {
+		bestIdx, best := 0, src.data[pos[0]]
+		for j := 1; j < n && !math.IsNaN(best); j++ {
+			val := src.data[pos[0]+j*strides[0]]
+			if better(val, best) || math.IsNaN(val) {
+				bestIdx, best = j, val
+			}
+		}
+		result.data[i] = float64(bestIdx)
+		i++
+	})
+	return result, nil
+}
+
+/*
+ Returns the running fold of op along axis
+*/
+func accumulate(x *GsArray, axis int, op func(acc, val float64) float64) (*GsArray, error) {
+	axis, err := checkAxis(axis, len(x.shape))
+	if err != nil {
+		return nil, err
+	}
+	result := Zeros(x.Shape()...)
+	forEachLane(axis, []*GsArray{x, result}, func(pos, strides []int, n int) {
+		if n == 0 {
+			return
+		}
+		acc := x.data[pos[0]]
+		result.data[pos[1]] = acc
+		for j := 1; j < n; j++ {
+			acc = op(acc, x.data[pos[0]+j*strides[0]])
+			result.data[pos[1]+j*strides[1]] = acc
+		}
+	})
+	return result, nil
+}
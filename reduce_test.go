@@ -0,0 +1,171 @@
+package goSci
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+/*
+ Folds op over the elements of x along axes one element at a time with Get,
+ returning the data of the result in row major order
+*/
+func naiveReduce(x *GsArray, axes []int, init float64, op func(acc, val float64) float64) []float64 {
+	reduced := make([]bool, len(x.shape))
+	for i := range reduced {
+		reduced[i] = len(axes) == 0
+	}
+	for _, axis := range axes {
+		reduced[(axis+len(x.shape))%len(x.shape)] = true
+	}
+	var outShape []int
+	for i, dim := range x.shape {
+		if !reduced[i] {
+			outShape = append(outShape, dim)
+		}
+	}
+	result := make([]float64, shapeSize(outShape))
+	for i := range result {
+		result[i] = init
+	}
+	pos := make([]int, len(x.shape))
+	for n := 0; n < x.Size(); n++ {
+		rem := n
+		for i := len(pos) - 1; i >= 0; i-- {
+			pos[i] = rem % x.shape[i]
+			rem /= x.shape[i]
+		}
+		out := 0
+		for i, p := range pos {
+			if !reduced[i] {
+				out = out*x.shape[i] + p
+			}
+		}
+		result[out] = op(result[out], x.Get(pos...))
+	}
+	return result
+}
+
+func TestReduceAxes(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	x := randomArray(r, 2, 3, 4)
+	// some zeros so AnyAxis is not all ones
+	for i := 0; i < x.Size(); i += 5 {
+		x.data[i] = 0
+	}
+	reducers := []struct {
+		name   string
+		reduce func(*GsArray, bool, ...int) *GsArray
+		init   float64
+		op     func(acc, val float64) float64
+	}{
+		{"SumAxis", SumAxis, 0, add},
+		{"ProdAxis", ProdAxis, 1, mul},
+		{"MinAxis", MinAxis, math.Inf(1), math.Min},
+		{"MaxAxis", MaxAxis, math.Inf(-1), math.Max},
+		{"AnyAxis", AnyAxis, 0, func(acc, val float64) float64 {
+			if val != 0 {
+				return 1
+			}
+			return acc
+		}},
+	}
+	axisSets := []struct {
+		axes             []int
+		shape, keptShape []int
+	}{
+		{nil, []int{}, []int{1, 1, 1}},
+		{[]int{0}, []int{3, 4}, []int{1, 3, 4}},
+		{[]int{1}, []int{2, 4}, []int{2, 1, 4}},
+		{[]int{-1}, []int{2, 3}, []int{2, 3, 1}},
+		{[]int{2, 0}, []int{3}, []int{1, 3, 1}},
+		{[]int{0, -2, 2}, []int{}, []int{1, 1, 1}},
+	}
+	for _, red := range reducers {
+		// a transposed view is reduced as its own axes
+		for _, src := range []*GsArray{x, x.Transpose(2, 0, 1).Copy().Transpose(1, 2, 0)} {
+			for _, s := range axisSets {
+				want := naiveReduce(x, s.axes, red.init, red.op)
+				assertArray(t, red.name, red.reduce(src, false, s.axes...), s.shape, want)
+				assertArray(t, red.name+" keepDims", red.reduce(src, true, s.axes...), s.keptShape, want)
+			}
+		}
+	}
+}
+
+func TestReduceEmptyAxes(t *testing.T) {
+	x := Zeros(0, 3)
+	assertArray(t, "SumAxis", SumAxis(x, false, 0), []int{3}, []float64{0, 0, 0})
+	assertArray(t, "ProdAxis", ProdAxis(x, false, 0), []int{3}, []float64{1, 1, 1})
+	assertArray(t, "AnyAxis", AnyAxis(x, true, 0), []int{1, 3}, []float64{0, 0, 0})
+	assertArray(t, "SumAxis of all", SumAxis(x, false), []int{}, []float64{0})
+	assertArray(t, "ProdAxis of all", ProdAxis(x, false), []int{}, []float64{1})
+	assertArray(t, "AnyAxis of all", AnyAxis(x, false), []int{}, []float64{0})
+	// reducing only the non-empty axis leaves an empty result
+	assertArray(t, "SumAxis of the other axis", SumAxis(x, false, 1), []int{0}, []float64{})
+	assertArray(t, "MaxAxis of the other axis", MaxAxis(x, true, 1), []int{0, 1}, []float64{})
+
+	// the minimum and maximum of no elements are undefined
+	for name, err := range map[string]error{
+		"MinAxis":        second(TryMinAxis(x, false, 0)),
+		"MaxAxis":        second(TryMaxAxis(x, false, 0)),
+		"MinAxis of all": second(TryMinAxis(x, false)),
+		"MaxAxis of all": second(TryMaxAxis(x, true, -2, 1)),
+	} {
+		if !errors.As(err, new(*ShapeMismatchError)) {
+			t.Errorf("%s over an empty axis: got %v", name, err)
+		}
+	}
+}
+
+func TestReduceInvalidAxes(t *testing.T) {
+	x := Zeros(2, 3)
+	reducers := map[string]func(*GsArray, bool, ...int) (*GsArray, error){
+		"SumAxis":  TrySumAxis,
+		"ProdAxis": TryProdAxis,
+		"MinAxis":  TryMinAxis,
+		"MaxAxis":  TryMaxAxis,
+		"AnyAxis":  TryAnyAxis,
+	}
+	cases := []struct {
+		axes []int
+		axis int
+	}{
+		{[]int{2}, 2},
+		{[]int{-3}, -3},
+		{[]int{0, 5}, 5},
+		// the same axis twice, also when one is given from the end
+		{[]int{1, 1}, 1},
+		{[]int{0, -2}, -2},
+	}
+	for name, reduce := range reducers {
+		for _, c := range cases {
+			_, err := reduce(x, false, c.axes...)
+			var ae *InvalidAxisError
+			if !errors.As(err, &ae) || ae.Axis != c.axis || ae.NDim != 2 {
+				t.Errorf("%s over %v: got %v", name, c.axes, err)
+			}
+		}
+	}
+
+	defer func() {
+		if _, ok := recover().(*InvalidAxisError); !ok {
+			t.Error("SumAxis over an invalid axis did not panic with an InvalidAxisError")
+		}
+	}()
+	SumAxis(x, false, 2)
+}
+
+func TestReduceNaN(t *testing.T) {
+	x := FromSlice([]float64{1, math.NaN(), 3, 4, 5, 6}, 2, 3)
+	for name, got := range map[string]*GsArray{"MinAxis": MinAxis(x, false, 1), "MaxAxis": MaxAxis(x, false, 1)} {
+		if d := got.Data(); !math.IsNaN(d[0]) || math.IsNaN(d[1]) {
+			t.Errorf("%s: got %v, want NaN only in the first row", name, d)
+		}
+	}
+	got := SumAxis(x, false, 0).Data()
+	if got[0] != 5 || !math.IsNaN(got[1]) || got[2] != 9 {
+		t.Errorf("SumAxis: got %v, want [5 NaN 9]", got)
+	}
+}
@@ -0,0 +1,144 @@
+package sparse
+
+import "github.com/lineback/goSci"
+
+/*
+ A sparse matrix in coordinate format, a list of (row, column, value)
+ triplets. It is cheap to add elements to in any order, which makes it the
+ format for assembling a matrix before converting it to CSR or CSC.
+ Duplicate triplets are summed when converting.
+*/
+type COO struct {
+	rows, cols int
+	row, col   []int
+	val        []float64
+}
+
+/*
+ Creates an empty rows by cols matrix. Panics if rows or cols is negative.
+*/
+func NewCOO(rows, cols int) *COO {
+	m, err := TryNewCOO(rows, cols)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+/*
+ Same as NewCOO but returns a ShapeMismatchError instead of panicking
+*/
+func TryNewCOO(rows, cols int) (*COO, error) {
+	if rows < 0 || cols < 0 {
+		return nil, &goSci.ShapeMismatchError{Op: "NewCOO", Shape1: []int{rows, cols}}
+	}
+	return &COO{rows: rows, cols: cols}, nil
+}
+
+/*
+ Adds val to the element in row i and column j
+*/
+func (m *COO) Add(i, j int, val float64) {
+	if err := m.TryAdd(i, j, val); err != nil {
+		panic(err)
+	}
+}
+
+/*
+ Same as Add but returns an IndexOutOfRangeError instead of panicking
+*/
+func (m *COO) TryAdd(i, j int, val float64) error {
+	if i < 0 || i >= m.rows {
+		return &goSci.IndexOutOfRangeError{Axis: 0, Index: i, Size: m.rows}
+	}
+	if j < 0 || j >= m.cols {
+		return &goSci.IndexOutOfRangeError{Axis: 1, Index: j, Size: m.cols}
+	}
+	m.row = append(m.row, i)
+	m.col = append(m.col, j)
+	m.val = append(m.val, val)
+	return nil
+}
+
+func (m *COO) Dims() (rows, cols int) {
+	return m.rows, m.cols
+}
+
+/*
+ Returns the number of triplets, counting duplicates separately
+*/
+func (m *COO) NNZ() int {
+	return len(m.val)
+}
+
+/*
+ Returns the element in row i and column j, the sum of its triplets
+*/
+func (m *COO) At(i, j int) float64 {
+	checkIndex(i, j, m.rows, m.cols)
+	sum := 0.0
+	for k := range m.val {
+		if m.row[k] == i && m.col[k] == j {
+			sum += m.val[k]
+		}
+	}
+	return sum
+}
+
+func (m *COO) Diagonal() *goSci.GsArray {
+	diag := make([]float64, min(m.rows, m.cols))
+	for k, val := range m.val {
+		if m.row[k] == m.col[k] {
+			diag[m.row[k]] += val
+		}
+	}
+	return goSci.FromSlice(diag, len(diag))
+}
+
+func (m *COO) ToDense() *goSci.GsArray {
+	vals := make([]float64, m.rows*m.cols)
+	for k, val := range m.val {
+		vals[m.row[k]*m.cols+m.col[k]] += val
+	}
+	return goSci.FromSlice(vals, m.rows, m.cols)
+}
+
+func (m *COO) ToCOO() *COO {
+	return m
+}
+
+func (m *COO) ToCSR() *CSR {
+	return &CSR{fromTriplets(m.rows, m.cols, m.row, m.col, m.val)}
+}
+
+func (m *COO) ToCSC() *CSC {
+	return &CSC{fromTriplets(m.cols, m.rows, m.col, m.row, m.val)}
+}
+
+/*
+ Returns the product of m with the vector x, so that m is a LinearOperator
+*/
+func (m *COO) Apply(x *goSci.GsArray) (*goSci.GsArray, error) {
+	shape := x.Shape()
+	if len(shape) != 1 || shape[0] != m.cols {
+		return nil, &goSci.ShapeMismatchError{Op: "Apply", Shape1: []int{m.rows, m.cols}, Shape2: shape}
+	}
+	vals := x.Data()
+	y := make([]float64, m.rows)
+	for k, val := range m.val {
+		y[m.row[k]] += val * vals[m.col[k]]
+	}
+	return goSci.FromSlice(y, m.rows), nil
+}
+
+/*
+ Panics with an IndexOutOfRangeError if i, j is not inside a rows by cols matrix
+*/
+func checkIndex(i, j, rows, cols int) {
+	if i < 0 || i >= rows {
+		panic(&goSci.IndexOutOfRangeError{Axis: 0, Index: i, Size: rows})
+	}
+	if j < 0 || j >= cols {
+		panic(&goSci.IndexOutOfRangeError{Axis: 1, Index: j, Size: cols})
+	}
+}
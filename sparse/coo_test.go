@@ -0,0 +1,50 @@
+package sparse
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lineback/goSci"
+)
+
+func TestNewCOODims(t *testing.T) {
+	for _, dims := range [][2]int{{-1, 3}, {3, -1}} {
+		_, err := TryNewCOO(dims[0], dims[1])
+		var sm *goSci.ShapeMismatchError
+		if !errors.As(err, &sm) || sm.Op != "NewCOO" {
+			t.Errorf("TryNewCOO(%d, %d): got %v, want a ShapeMismatchError", dims[0], dims[1], err)
+		}
+	}
+	if m, err := TryNewCOO(0, 0); err != nil || m.ToDense().Size() != 0 {
+		t.Errorf("TryNewCOO(0, 0): got %v", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("NewCOO with negative rows did not panic")
+		}
+	}()
+	NewCOO(-2, 2)
+}
+
+func TestCOOConversions(t *testing.T) {
+	m := NewCOO(2, 3)
+	m.Add(0, 2, 1)
+	m.Add(1, 0, 2)
+	m.Add(0, 2, 3)
+	want := []float64{0, 0, 4, 2, 0, 0}
+	for name, dense := range map[string]*goSci.GsArray{
+		"COO": m.ToDense(),
+		"CSR": m.ToCSR().ToDense(),
+		"CSC": m.ToCSC().ToDense(),
+	} {
+		for i, val := range dense.Data() {
+			if val != want[i] {
+				t.Errorf("%s: got %v, want %v", name, dense.Data(), want)
+				break
+			}
+		}
+	}
+	if err := m.TryAdd(2, 0, 1); !errors.As(err, new(*goSci.IndexOutOfRangeError)) {
+		t.Errorf("TryAdd out of range: got %v", err)
+	}
+}
@@ -0,0 +1,251 @@
+package sparse
+
+import "github.com/lineback/goSci"
+
+/*
+ A sparse matrix in compressed sparse column format, the format for slicing
+ columns. The nonzeros of column j are in rows indices[indptr[j]:indptr[j+1]],
+ in increasing order, with values data[indptr[j]:indptr[j+1]].
+*/
+type CSC struct {
+	compressed
+}
+
+/*
+ Creates a rows by cols matrix from its compressed column storage, which it
+ shares. Returns an error if the storage is not valid.
+*/
+func NewCSC(rows, cols int, indptr, indices []int, data []float64) (*CSC, error) {
+	m := &CSC{compressed{major: cols, minor: rows, indptr: indptr, indices: indices, data: data}}
+	if err := m.validate("NewCSC"); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+/*
+ Returns the nonzero elements of the dense matrix a in CSC format
+*/
+func CSCFromDense(a *goSci.GsArray) (*CSC, error) {
+	c, err := fromDense("CSCFromDense", a, true)
+	if err != nil {
+		return nil, err
+	}
+	return &CSC{c}, nil
+}
+
+func (m *CSC) Dims() (rows, cols int) {
+	return m.minor, m.major
+}
+
+func (m *CSC) NNZ() int {
+	return len(m.data)
+}
+
+func (m *CSC) At(i, j int) float64 {
+	checkIndex(i, j, m.minor, m.major)
+	return m.at(j, i)
+}
+
+func (m *CSC) Diagonal() *goSci.GsArray {
+	return m.diagonal()
+}
+
+func (m *CSC) ToDense() *goSci.GsArray {
+	return m.toDense(true)
+}
+
+func (m *CSC) ToCOO() *COO {
+	return m.toCOO(true)
+}
+
+func (m *CSC) ToCSR() *CSR {
+	return &CSR{m.transpose()}
+}
+
+func (m *CSC) ToCSC() *CSC {
+	return m
+}
+
+/*
+ Returns the transpose, which shares the storage of m
+*/
+func (m *CSC) T() *CSR {
+	return &CSR{m.compressed}
+}
+
+/*
+ Returns a copy of m with every element multiplied by alpha
+*/
+func (m *CSC) Scale(alpha float64) *CSC {
+	s := &CSC{m.copy()}
+	for k := range s.data {
+		s.data[k] *= alpha
+	}
+	return s
+}
+
+/*
+ Returns the element by element sum m + b
+*/
+func (m *CSC) Plus(b *CSC) *CSC {
+	s, err := m.TryPlus(b)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+/*
+ Same as Plus but returns a ShapeMismatchError instead of panicking
+*/
+func (m *CSC) TryPlus(b *CSC) (*CSC, error) {
+	return m.elementwise("Plus", b, func(x, y float64) float64 { return x + y }, false)
+}
+
+/*
+ Returns the element by element difference m - b
+*/
+func (m *CSC) Minus(b *CSC) *CSC {
+	s, err := m.TryMinus(b)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+/*
+ Same as Minus but returns a ShapeMismatchError instead of panicking
+*/
+func (m *CSC) TryMinus(b *CSC) (*CSC, error) {
+	return m.elementwise("Minus", b, func(x, y float64) float64 { return x - y }, false)
+}
+
+/*
+ Returns the element by element product of m and b
+*/
+func (m *CSC) ElemTimes(b *CSC) *CSC {
+	s, err := m.TryElemTimes(b)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+/*
+ Same as ElemTimes but returns a ShapeMismatchError instead of panicking
+*/
+func (m *CSC) TryElemTimes(b *CSC) (*CSC, error) {
+	return m.elementwise("ElemTimes", b, func(x, y float64) float64 { return x * y }, true)
+}
+
+func (m *CSC) elementwise(op string, b *CSC, f func(x, y float64) float64, intersect bool) (*CSC, error) {
+	if m.major != b.major || m.minor != b.minor {
+		return nil, &goSci.ShapeMismatchError{Op: op, Shape1: []int{m.minor, m.major}, Shape2: []int{b.minor, b.major}}
+	}
+	return &CSC{merge(&m.compressed, &b.compressed, f, intersect)}, nil
+}
+
+/*
+ Returns the matrix product m*b
+*/
+func (m *CSC) Mul(b *CSC) *CSC {
+	p, err := m.TryMul(b)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+/*
+ Same as Mul but returns a ShapeMismatchError instead of panicking
+*/
+func (m *CSC) TryMul(b *CSC) (*CSC, error) {
+	if m.major != b.minor {
+		return nil, &goSci.ShapeMismatchError{Op: "Mul", Shape1: []int{m.minor, m.major}, Shape2: []int{b.minor, b.major}}
+	}
+	// the storage of m*b read by rows is the product b^T*m^T
+	return &CSC{multiply(&b.compressed, &m.compressed)}, nil
+}
+
+/*
+ Returns the product of m with the dense b, a vector or a matrix. The result
+ is dense and has the shape of b with m's number of rows.
+*/
+func (m *CSC) MulDense(b *goSci.GsArray) *goSci.GsArray {
+	p, err := m.TryMulDense(b)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+/*
+ Same as MulDense but returns a ShapeMismatchError instead of panicking
+*/
+func (m *CSC) TryMulDense(b *goSci.GsArray) (*goSci.GsArray, error) {
+	vals, n, vector, err := denseOperand("MulDense", []int{m.minor, m.major}, b, m.major)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]float64, m.minor*n)
+	for j := 0; j < m.major; j++ {
+		bRow := vals[j*n : (j+1)*n]
+		for k := m.indptr[j]; k < m.indptr[j+1]; k++ {
+			val, row := m.data[k], out[m.indices[k]*n:(m.indices[k]+1)*n]
+			for l, bVal := range bRow {
+				row[l] += val * bVal
+			}
+		}
+	}
+	return denseResult(out, m.minor, n, vector), nil
+}
+
+/*
+ Returns rows start to stop-1 of m
+*/
+func (m *CSC) RowSlice(start, stop int) *CSC {
+	s, err := m.TryRowSlice(start, stop)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+/*
+ Same as RowSlice but returns an error wrapping ErrInvalidSlice instead of panicking
+*/
+func (m *CSC) TryRowSlice(start, stop int) (*CSC, error) {
+	if err := checkRange(start, stop, m.minor); err != nil {
+		return nil, err
+	}
+	return &CSC{m.minorSlice(start, stop)}, nil
+}
+
+/*
+ Returns columns start to stop-1 of m
+*/
+func (m *CSC) ColSlice(start, stop int) *CSC {
+	s, err := m.TryColSlice(start, stop)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+/*
+ Same as ColSlice but returns an error wrapping ErrInvalidSlice instead of panicking
+*/
+func (m *CSC) TryColSlice(start, stop int) (*CSC, error) {
+	if err := checkRange(start, stop, m.major); err != nil {
+		return nil, err
+	}
+	return &CSC{m.majorSlice(start, stop)}, nil
+}
+
+/*
+ Returns the product of m with the vector x, so that m is a LinearOperator
+*/
+func (m *CSC) Apply(x *goSci.GsArray) (*goSci.GsArray, error) {
+	return m.TryMulDense(x)
+}
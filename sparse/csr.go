@@ -0,0 +1,251 @@
+package sparse
+
+import "github.com/lineback/goSci"
+
+/*
+ A sparse matrix in compressed sparse row format, the format for products
+ with vectors and for slicing rows. The nonzeros of row i are in columns
+ indices[indptr[i]:indptr[i+1]], in increasing order, with values
+ data[indptr[i]:indptr[i+1]].
+*/
+type CSR struct {
+	compressed
+}
+
+/*
+ Creates a rows by cols matrix from its compressed row storage, which it
+ shares. Returns an error if the storage is not valid.
+*/
+func NewCSR(rows, cols int, indptr, indices []int, data []float64) (*CSR, error) {
+	m := &CSR{compressed{major: rows, minor: cols, indptr: indptr, indices: indices, data: data}}
+	if err := m.validate("NewCSR"); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+/*
+ Returns the nonzero elements of the dense matrix a in CSR format
+*/
+func CSRFromDense(a *goSci.GsArray) (*CSR, error) {
+	c, err := fromDense("CSRFromDense", a, false)
+	if err != nil {
+		return nil, err
+	}
+	return &CSR{c}, nil
+}
+
+func (m *CSR) Dims() (rows, cols int) {
+	return m.major, m.minor
+}
+
+func (m *CSR) NNZ() int {
+	return len(m.data)
+}
+
+func (m *CSR) At(i, j int) float64 {
+	checkIndex(i, j, m.major, m.minor)
+	return m.at(i, j)
+}
+
+func (m *CSR) Diagonal() *goSci.GsArray {
+	return m.diagonal()
+}
+
+func (m *CSR) ToDense() *goSci.GsArray {
+	return m.toDense(false)
+}
+
+func (m *CSR) ToCOO() *COO {
+	return m.toCOO(false)
+}
+
+func (m *CSR) ToCSR() *CSR {
+	return m
+}
+
+func (m *CSR) ToCSC() *CSC {
+	return &CSC{m.transpose()}
+}
+
+/*
+ Returns the transpose, which shares the storage of m
+*/
+func (m *CSR) T() *CSC {
+	return &CSC{m.compressed}
+}
+
+/*
+ Returns a copy of m with every element multiplied by alpha
+*/
+func (m *CSR) Scale(alpha float64) *CSR {
+	s := &CSR{m.copy()}
+	for k := range s.data {
+		s.data[k] *= alpha
+	}
+	return s
+}
+
+/*
+ Returns the element by element sum m + b
+*/
+func (m *CSR) Plus(b *CSR) *CSR {
+	s, err := m.TryPlus(b)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+/*
+ Same as Plus but returns a ShapeMismatchError instead of panicking
+*/
+func (m *CSR) TryPlus(b *CSR) (*CSR, error) {
+	return m.elementwise("Plus", b, func(x, y float64) float64 { return x + y }, false)
+}
+
+/*
+ Returns the element by element difference m - b
+*/
+func (m *CSR) Minus(b *CSR) *CSR {
+	s, err := m.TryMinus(b)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+/*
+ Same as Minus but returns a ShapeMismatchError instead of panicking
+*/
+func (m *CSR) TryMinus(b *CSR) (*CSR, error) {
+	return m.elementwise("Minus", b, func(x, y float64) float64 { return x - y }, false)
+}
+
+/*
+ Returns the element by element product of m and b
+*/
+func (m *CSR) ElemTimes(b *CSR) *CSR {
+	s, err := m.TryElemTimes(b)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+/*
+ Same as ElemTimes but returns a ShapeMismatchError instead of panicking
+*/
+func (m *CSR) TryElemTimes(b *CSR) (*CSR, error) {
+	return m.elementwise("ElemTimes", b, func(x, y float64) float64 { return x * y }, true)
+}
+
+func (m *CSR) elementwise(op string, b *CSR, f func(x, y float64) float64, intersect bool) (*CSR, error) {
+	if m.major != b.major || m.minor != b.minor {
+		return nil, &goSci.ShapeMismatchError{Op: op, Shape1: []int{m.major, m.minor}, Shape2: []int{b.major, b.minor}}
+	}
+	return &CSR{merge(&m.compressed, &b.compressed, f, intersect)}, nil
+}
+
+/*
+ Returns the matrix product m*b
+*/
+func (m *CSR) Mul(b *CSR) *CSR {
+	p, err := m.TryMul(b)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+/*
+ Same as Mul but returns a ShapeMismatchError instead of panicking
+*/
+func (m *CSR) TryMul(b *CSR) (*CSR, error) {
+	if m.minor != b.major {
+		return nil, &goSci.ShapeMismatchError{Op: "Mul", Shape1: []int{m.major, m.minor}, Shape2: []int{b.major, b.minor}}
+	}
+	return &CSR{multiply(&m.compressed, &b.compressed)}, nil
+}
+
+/*
+ Returns the product of m with the dense b, a vector or a matrix. The result
+ is dense and has the shape of b with m's number of rows.
+*/
+func (m *CSR) MulDense(b *goSci.GsArray) *goSci.GsArray {
+	p, err := m.TryMulDense(b)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+/*
+ Same as MulDense but returns a ShapeMismatchError instead of panicking
+*/
+func (m *CSR) TryMulDense(b *goSci.GsArray) (*goSci.GsArray, error) {
+	vals, n, vector, err := denseOperand("MulDense", []int{m.major, m.minor}, b, m.minor)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]float64, m.major*n)
+	for i := 0; i < m.major; i++ {
+		row := out[i*n : (i+1)*n]
+		for k := m.indptr[i]; k < m.indptr[i+1]; k++ {
+			val, bRow := m.data[k], vals[m.indices[k]*n:(m.indices[k]+1)*n]
+			for j, bVal := range bRow {
+				row[j] += val * bVal
+			}
+		}
+	}
+	return denseResult(out, m.major, n, vector), nil
+}
+
+/*
+ Returns rows start to stop-1 of m
+*/
+func (m *CSR) RowSlice(start, stop int) *CSR {
+	s, err := m.TryRowSlice(start, stop)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+/*
+ Same as RowSlice but returns an error wrapping ErrInvalidSlice instead of panicking
+*/
+func (m *CSR) TryRowSlice(start, stop int) (*CSR, error) {
+	if err := checkRange(start, stop, m.major); err != nil {
+		return nil, err
+	}
+	return &CSR{m.majorSlice(start, stop)}, nil
+}
+
+/*
+ Returns columns start to stop-1 of m
+*/
+func (m *CSR) ColSlice(start, stop int) *CSR {
+	s, err := m.TryColSlice(start, stop)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+/*
+ Same as ColSlice but returns an error wrapping ErrInvalidSlice instead of panicking
+*/
+func (m *CSR) TryColSlice(start, stop int) (*CSR, error) {
+	if err := checkRange(start, stop, m.minor); err != nil {
+		return nil, err
+	}
+	return &CSR{m.minorSlice(start, stop)}, nil
+}
+
+/*
+ Returns the product of m with the vector x, so that m is a LinearOperator
+*/
+func (m *CSR) Apply(x *goSci.GsArray) (*goSci.GsArray, error) {
+	return m.TryMulDense(x)
+}
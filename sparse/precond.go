@@ -0,0 +1,205 @@
+package sparse
+
+import (
+	"math"
+
+	"github.com/lineback/goSci"
+)
+
+/*
+ The zero fill-in incomplete LU factorization of a square matrix, L*U ~ A with
+ L unit lower triangular, U upper triangular and both keeping the sparsity
+ pattern of A. Its Apply solves L*U*z = r, making it a preconditioner for the
+ Krylov solvers of goSci.
+*/
+type ILU0 struct {
+	lu   compressed // L below and U on and above the diagonal, rows compressed
+	diag []int      // position of the diagonal element of each row in lu
+}
+
+/*
+ Returns the ILU(0) factorization of the square matrix a. Returns
+ goSci.ErrSingular if a diagonal element is missing or becomes zero.
+*/
+func NewILU0(a *CSR) (*ILU0, error) {
+	if a.major != a.minor {
+		return nil, &goSci.ShapeMismatchError{Op: "NewILU0", Shape1: []int{a.major, a.minor}}
+	}
+	f := &ILU0{lu: a.copy()}
+	lu := &f.lu
+	n := lu.major
+	var err error
+	if f.diag, err = diagonalPositions(lu); err != nil {
+		return nil, err
+	}
+	// pos maps a column to its position in the current row, -1 if absent
+	pos := make([]int, n)
+	for j := range pos {
+		pos[j] = -1
+	}
+	for i := 0; i < n; i++ {
+		for k := lu.indptr[i]; k < lu.indptr[i+1]; k++ {
+			pos[lu.indices[k]] = k
+		}
+		for k := lu.indptr[i]; k < f.diag[i]; k++ {
+			// eliminate column c of row i using row c of U
+			c := lu.indices[k]
+			lu.data[k] /= lu.data[f.diag[c]]
+			for kc := f.diag[c] + 1; kc < lu.indptr[c+1]; kc++ {
+				if p := pos[lu.indices[kc]]; p >= 0 {
+					lu.data[p] -= lu.data[k] * lu.data[kc]
+				}
+			}
+		}
+		if lu.data[f.diag[i]] == 0 {
+			return nil, goSci.ErrSingular
+		}
+		for k := lu.indptr[i]; k < lu.indptr[i+1]; k++ {
+			pos[lu.indices[k]] = -1
+		}
+	}
+	return f, nil
+}
+
+/*
+ Returns z solving L*U*z = r
+*/
+func (f *ILU0) Apply(r *goSci.GsArray) (*goSci.GsArray, error) {
+	lu := &f.lu
+	n := lu.major
+	shape := r.Shape()
+	if len(shape) != 1 || shape[0] != n {
+		return nil, &goSci.ShapeMismatchError{Op: "Apply", Shape1: []int{n, n}, Shape2: shape}
+	}
+	z := r.Data()
+	for i := 0; i < n; i++ {
+		for k := lu.indptr[i]; k < f.diag[i]; k++ {
+			z[i] -= lu.data[k] * z[lu.indices[k]]
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		for k := f.diag[i] + 1; k < lu.indptr[i+1]; k++ {
+			z[i] -= lu.data[k] * z[lu.indices[k]]
+		}
+		z[i] /= lu.data[f.diag[i]]
+	}
+	return goSci.FromSlice(z, n), nil
+}
+
+/*
+ The zero fill-in incomplete Cholesky factorization of a symmetric positive
+ definite matrix, L*L^T ~ A with L lower triangular and keeping the sparsity
+ pattern of the lower triangle of A. Its Apply solves L*L^T*z = r, making it
+ a preconditioner for CG.
+*/
+type IC0 struct {
+	l compressed // rows of L, the diagonal last in each row
+}
+
+/*
+ Returns the IC(0) factorization of the symmetric positive definite matrix a,
+ using only its lower triangle. Returns goSci.ErrNotPositiveDefinite if a
+ pivot is not positive, which can happen for some positive definite matrices
+ since elements outside the pattern are dropped.
+*/
+func NewIC0(a *CSR) (*IC0, error) {
+	if a.major != a.minor {
+		return nil, &goSci.ShapeMismatchError{Op: "NewIC0", Shape1: []int{a.major, a.minor}}
+	}
+	n := a.major
+	// keep the lower triangle
+	l := newCompressed(n, n, 0)
+	for i := 0; i < n; i++ {
+		for k := a.indptr[i]; k < a.indptr[i+1] && a.indices[k] <= i; k++ {
+			l.indices = append(l.indices, a.indices[k])
+			l.data = append(l.data, a.data[k])
+		}
+		l.indptr[i+1] = len(l.data)
+	}
+	for i := 0; i < n; i++ {
+		start, end := l.indptr[i], l.indptr[i+1]
+		if end == start || l.indices[end-1] != i {
+			return nil, goSci.ErrNotPositiveDefinite
+		}
+		for k := start; k < end; k++ {
+			// L[i][j] -= sum over c < j of L[i][c]*L[j][c], merging rows i and j
+			j := l.indices[k]
+			sum := 0.0
+			ki, kj := start, l.indptr[j]
+			for ki < k && kj < l.indptr[j+1] {
+				ci, cj := l.indices[ki], l.indices[kj]
+				switch {
+				case ci < cj:
+					ki++
+				case cj < ci:
+					kj++
+				default:
+					if ci < j {
+						sum += l.data[ki] * l.data[kj]
+					}
+					ki++
+					kj++
+				}
+			}
+			if j < i {
+				l.data[k] = (l.data[k] - sum) / l.data[l.indptr[j+1]-1]
+				continue
+			}
+			d := l.data[k] - sum
+			if !(d > 0) {
+				return nil, goSci.ErrNotPositiveDefinite
+			}
+			l.data[k] = math.Sqrt(d)
+		}
+	}
+	return &IC0{l: l}, nil
+}
+
+/*
+ Returns z solving L*L^T*z = r
+*/
+func (f *IC0) Apply(r *goSci.GsArray) (*goSci.GsArray, error) {
+	l := &f.l
+	n := l.major
+	shape := r.Shape()
+	if len(shape) != 1 || shape[0] != n {
+		return nil, &goSci.ShapeMismatchError{Op: "Apply", Shape1: []int{n, n}, Shape2: shape}
+	}
+	z := r.Data()
+	for i := 0; i < n; i++ {
+		last := l.indptr[i+1] - 1
+		for k := l.indptr[i]; k < last; k++ {
+			z[i] -= l.data[k] * z[l.indices[k]]
+		}
+		z[i] /= l.data[last]
+	}
+	// L^T is solved by rows of L, each solved element updating those before it
+	for i := n - 1; i >= 0; i-- {
+		last := l.indptr[i+1] - 1
+		z[i] /= l.data[last]
+		for k := l.indptr[i]; k < last; k++ {
+			z[l.indices[k]] -= l.data[k] * z[i]
+		}
+	}
+	return goSci.FromSlice(z, n), nil
+}
+
+/*
+ Returns the position of the diagonal element of each row, or
+ goSci.ErrSingular if one is missing
+*/
+func diagonalPositions(c *compressed) ([]int, error) {
+	diag := make([]int, c.major)
+	for i := range diag {
+		line := c.indices[c.indptr[i]:c.indptr[i+1]]
+		k := 0
+		for k < len(line) && line[k] < i {
+			k++
+		}
+		if k == len(line) || line[k] != i {
+			return nil, goSci.ErrSingular
+		}
+		diag[i] = c.indptr[i] + k
+	}
+	return diag, nil
+}
@@ -0,0 +1,108 @@
+package sparse
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lineback/goSci"
+)
+
+/*
+ Returns the matrix of the five point Laplacian on a k by k grid, plus shift
+ times a skew term that makes it nonsymmetric
+*/
+func laplacian(k int, shift float64) *CSR {
+	m := NewCOO(k*k, k*k)
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			p := i*k + j
+			m.Add(p, p, 4)
+			if i > 0 {
+				m.Add(p, p-k, -1-shift)
+			}
+			if i < k-1 {
+				m.Add(p, p+k, -1+shift)
+			}
+			if j > 0 {
+				m.Add(p, p-1, -1)
+			}
+			if j < k-1 {
+				m.Add(p, p+1, -1)
+			}
+		}
+	}
+	return m.ToCSR()
+}
+
+func residual(a *CSR, x, b *goSci.GsArray) float64 {
+	ax, _ := a.Apply(x)
+	r := goSci.Minus(ax, b)
+	return math.Sqrt(goSci.Dot(r, r) / goSci.Dot(b, b))
+}
+
+func TestPreconditionedSolves(t *testing.T) {
+	k := 12
+	b := goSci.Ones(k * k)
+
+	spd := laplacian(k, 0)
+	ic, err := NewIC0(spd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := goSci.CG(spd, b, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pre, err := goSci.CG(spd, b, &goSci.KrylovOptions{Precond: ic})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if residual(spd, pre.X, b) > 2e-8 {
+		t.Errorf("CG with IC0: residual %g", residual(spd, pre.X, b))
+	}
+	if pre.Iterations >= plain.Iterations {
+		t.Errorf("IC0 did not reduce the CG iterations: %d against %d", pre.Iterations, plain.Iterations)
+	}
+
+	nonsym := laplacian(k, 0.3)
+	ilu, err := NewILU0(nonsym)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, solve := range map[string]func(goSci.LinearOperator, *goSci.GsArray, *goSci.KrylovOptions) (*goSci.KrylovResult, error){
+		"GMRES":    goSci.GMRES,
+		"BiCGSTAB": goSci.BiCGSTAB,
+	} {
+		res, err := solve(nonsym, b, &goSci.KrylovOptions{Precond: ilu})
+		if err != nil {
+			t.Fatalf("%s with ILU0: %v", name, err)
+		}
+		if residual(nonsym, res.X, b) > 2e-8 {
+			t.Errorf("%s with ILU0: residual %g", name, residual(nonsym, res.X, b))
+		}
+	}
+
+	// on a tridiagonal matrix ILU0 has no fill to drop, so it is the exact LU
+	// factorization and solves in one step
+	line := NewCOO(6, 6)
+	for i := 0; i < 6; i++ {
+		line.Add(i, i, 3)
+		if i > 0 {
+			line.Add(i, i-1, -1)
+			line.Add(i-1, i, -2)
+		}
+	}
+	tri := line.ToCSR()
+	exact, err := NewILU0(tri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rhs := goSci.Arange(6)
+	x, err := exact.Apply(rhs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if residual(tri, x, rhs) > 1e-14 {
+		t.Errorf("ILU0 of a tridiagonal matrix: residual %g", residual(tri, x, rhs))
+	}
+}
@@ -0,0 +1,406 @@
+/*
+ Sparse matrices for goSci. Matrices are assembled in the COO format and
+ converted to CSR or CSC for arithmetic, which store only the nonzero
+ elements and convert to and from dense GsArrays.
+*/
+package sparse
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/lineback/goSci"
+)
+
+/*
+ The operations shared by the sparse formats
+*/
+type Matrix interface {
+	goSci.LinearOperator
+	// Returns the number of rows and columns
+	Dims() (rows, cols int)
+	// Returns the number of stored elements
+	NNZ() int
+	// Returns the element in row i and column j
+	At(i, j int) float64
+	// Returns the main diagonal as a vector
+	Diagonal() *goSci.GsArray
+	ToDense() *goSci.GsArray
+	ToCOO() *COO
+	ToCSR() *CSR
+	ToCSC() *CSC
+}
+
+/*
+ Compressed storage shared by CSR and CSC. Along the major axis, rows for CSR
+ and columns for CSC, the entries of line i are at indptr[i]:indptr[i+1], with
+ their position along the minor axis in indices, in increasing order and
+ without duplicates, and their values in data. The transpose of a matrix in
+ one format is the same storage read as the other.
+*/
+type compressed struct {
+	major, minor int
+	indptr       []int
+	indices      []int
+	data         []float64
+}
+
+func newCompressed(major, minor, nnz int) compressed {
+	return compressed{
+		major:   major,
+		minor:   minor,
+		indptr:  make([]int, major+1),
+		indices: make([]int, 0, nnz),
+		data:    make([]float64, 0, nnz),
+	}
+}
+
+/*
+ Checks that indptr, indices and data describe a valid compressed matrix
+*/
+func (c *compressed) validate(op string) error {
+	if c.major < 0 || c.minor < 0 || len(c.indptr) != c.major+1 || c.indptr[0] != 0 ||
+		len(c.indices) != len(c.data) || c.indptr[c.major] != len(c.data) {
+		return &goSci.ShapeMismatchError{Op: op, Shape1: []int{len(c.indptr), len(c.indices), len(c.data)}}
+	}
+	for i := 0; i < c.major; i++ {
+		if c.indptr[i] > c.indptr[i+1] {
+			return fmt.Errorf("sparse: %s: indptr decreases at %d", op, i)
+		}
+		for k := c.indptr[i]; k < c.indptr[i+1]; k++ {
+			if j := c.indices[k]; j < 0 || j >= c.minor {
+				return &goSci.IndexOutOfRangeError{Axis: 1, Index: j, Size: c.minor}
+			}
+			if k > c.indptr[i] && c.indices[k] <= c.indices[k-1] {
+				return fmt.Errorf("sparse: %s: indices of line %d are not increasing", op, i)
+			}
+		}
+	}
+	return nil
+}
+
+/*
+ Returns the element at position i along the major axis and j along the minor
+*/
+func (c *compressed) at(i, j int) float64 {
+	line := c.indices[c.indptr[i]:c.indptr[i+1]]
+	k := sort.SearchInts(line, j)
+	if k < len(line) && line[k] == j {
+		return c.data[c.indptr[i]+k]
+	}
+	return 0
+}
+
+/*
+ Returns the elements with equal positions along both axes
+*/
+func (c *compressed) diagonal() *goSci.GsArray {
+	diag := make([]float64, min(c.major, c.minor))
+	for i := range diag {
+		diag[i] = c.at(i, i)
+	}
+	return goSci.FromSlice(diag, len(diag))
+}
+
+func (c *compressed) copy() compressed {
+	return compressed{
+		major:   c.major,
+		minor:   c.minor,
+		indptr:  append([]int(nil), c.indptr...),
+		indices: append([]int(nil), c.indices...),
+		data:    append([]float64(nil), c.data...),
+	}
+}
+
+/*
+ Returns the same matrix compressed along the other axis, by counting the
+ entries in each line of the minor axis and then placing them in order
+*/
+func (c *compressed) transpose() compressed {
+	t := compressed{
+		major:   c.minor,
+		minor:   c.major,
+		indptr:  make([]int, c.minor+1),
+		indices: make([]int, len(c.indices)),
+		data:    make([]float64, len(c.data)),
+	}
+	for _, j := range c.indices {
+		t.indptr[j+1]++
+	}
+	for j := 0; j < t.major; j++ {
+		t.indptr[j+1] += t.indptr[j]
+	}
+	next := append([]int(nil), t.indptr[:t.major]...)
+	for i := 0; i < c.major; i++ {
+		for k := c.indptr[i]; k < c.indptr[i+1]; k++ {
+			j := c.indices[k]
+			t.indices[next[j]] = i
+			t.data[next[j]] = c.data[k]
+			next[j]++
+		}
+	}
+	return t
+}
+
+/*
+ Builds compressed storage from triplets, summing duplicates and dropping the
+ elements that are zero
+*/
+func fromTriplets(major, minor int, maj, mnr []int, val []float64) compressed {
+	// bucket the triplets by line of the major axis
+	start := make([]int, major+1)
+	for _, i := range maj {
+		start[i+1]++
+	}
+	for i := 0; i < major; i++ {
+		start[i+1] += start[i]
+	}
+	order := make([]int, len(maj))
+	next := append([]int(nil), start[:major]...)
+	for k, i := range maj {
+		order[next[i]] = k
+		next[i]++
+	}
+	c := newCompressed(major, minor, len(val))
+	for i := 0; i < major; i++ {
+		line := order[start[i]:start[i+1]]
+		sort.SliceStable(line, func(a, b int) bool { return mnr[line[a]] < mnr[line[b]] })
+		for n := 0; n < len(line); {
+			j, sum := mnr[line[n]], 0.0
+			for ; n < len(line) && mnr[line[n]] == j; n++ {
+				sum += val[line[n]]
+			}
+			if sum != 0 {
+				c.indices = append(c.indices, j)
+				c.data = append(c.data, sum)
+			}
+		}
+		c.indptr[i+1] = len(c.data)
+	}
+	return c
+}
+
+/*
+ Builds compressed storage from the dense rows by cols matrix a, compressing
+ rows, or columns when byCols is set
+*/
+func fromDense(op string, a *goSci.GsArray, byCols bool) (compressed, error) {
+	shape := a.Shape()
+	if len(shape) != 2 {
+		return compressed{}, &goSci.ShapeMismatchError{Op: op, Shape1: shape}
+	}
+	rows, cols := shape[0], shape[1]
+	vals := a.Data()
+	if byCols {
+		c := newCompressed(cols, rows, 0)
+		for j := 0; j < cols; j++ {
+			for i := 0; i < rows; i++ {
+				if val := vals[i*cols+j]; val != 0 {
+					c.indices = append(c.indices, i)
+					c.data = append(c.data, val)
+				}
+			}
+			c.indptr[j+1] = len(c.data)
+		}
+		return c, nil
+	}
+	c := newCompressed(rows, cols, 0)
+	for i := 0; i < rows; i++ {
+		for j, val := range vals[i*cols : (i+1)*cols] {
+			if val != 0 {
+				c.indices = append(c.indices, j)
+				c.data = append(c.data, val)
+			}
+		}
+		c.indptr[i+1] = len(c.data)
+	}
+	return c, nil
+}
+
+/*
+ Returns the dense matrix, transposed when the major axis is columns
+*/
+func (c *compressed) toDense(byCols bool) *goSci.GsArray {
+	rows, cols := c.major, c.minor
+	if byCols {
+		rows, cols = cols, rows
+	}
+	vals := make([]float64, rows*cols)
+	for i := 0; i < c.major; i++ {
+		for k := c.indptr[i]; k < c.indptr[i+1]; k++ {
+			if byCols {
+				vals[c.indices[k]*cols+i] = c.data[k]
+			} else {
+				vals[i*cols+c.indices[k]] = c.data[k]
+			}
+		}
+	}
+	return goSci.FromSlice(vals, rows, cols)
+}
+
+/*
+ Returns the triplets of the matrix, swapping rows and columns when the major
+ axis is columns
+*/
+func (c *compressed) toCOO(byCols bool) *COO {
+	m := &COO{rows: c.major, cols: c.minor}
+	m.row = make([]int, 0, len(c.data))
+	m.col = append([]int(nil), c.indices...)
+	m.val = append([]float64(nil), c.data...)
+	for i := 0; i < c.major; i++ {
+		for k := c.indptr[i]; k < c.indptr[i+1]; k++ {
+			m.row = append(m.row, i)
+		}
+	}
+	if byCols {
+		m.rows, m.cols = m.cols, m.rows
+		m.row, m.col = m.col, m.row
+	}
+	return m
+}
+
+/*
+ Combines a and b element by element, merging each pair of lines. For a
+ product only the positions present in both matter, for a sum or difference
+ those in either. Zero results are dropped.
+*/
+func merge(a, b *compressed, op func(x, y float64) float64, intersect bool) compressed {
+	c := newCompressed(a.major, a.minor, 0)
+	for i := 0; i < a.major; i++ {
+		ka, kb := a.indptr[i], b.indptr[i]
+		endA, endB := a.indptr[i+1], b.indptr[i+1]
+		for ka < endA || kb < endB {
+			var j int
+			var x, y float64
+			switch {
+			case kb == endB || (ka < endA && a.indices[ka] < b.indices[kb]):
+				j, x = a.indices[ka], a.data[ka]
+				ka++
+			case ka == endA || b.indices[kb] < a.indices[ka]:
+				j, y = b.indices[kb], b.data[kb]
+				kb++
+			default:
+				j, x, y = a.indices[ka], a.data[ka], b.data[kb]
+				ka++
+				kb++
+			}
+			if intersect && (x == 0 || y == 0) {
+				continue
+			}
+			if val := op(x, y); val != 0 {
+				c.indices = append(c.indices, j)
+				c.data = append(c.data, val)
+			}
+		}
+		c.indptr[i+1] = len(c.data)
+	}
+	return c
+}
+
+/*
+ Returns the product of a, major by n, with b, n by minor, lines along the
+ major axis of the result accumulated one at a time in a dense work row
+ (Gustavson's algorithm)
+*/
+func multiply(a, b *compressed) compressed {
+	c := newCompressed(a.major, b.minor, 0)
+	work := make([]float64, b.minor)
+	marker := make([]int, b.minor)
+	for j := range marker {
+		marker[j] = -1
+	}
+	var touched []int
+	for i := 0; i < a.major; i++ {
+		touched = touched[:0]
+		for ka := a.indptr[i]; ka < a.indptr[i+1]; ka++ {
+			l, aVal := a.indices[ka], a.data[ka]
+			for kb := b.indptr[l]; kb < b.indptr[l+1]; kb++ {
+				j := b.indices[kb]
+				if marker[j] != i {
+					marker[j] = i
+					work[j] = 0
+					touched = append(touched, j)
+				}
+				work[j] += aVal * b.data[kb]
+			}
+		}
+		sort.Ints(touched)
+		for _, j := range touched {
+			if work[j] != 0 {
+				c.indices = append(c.indices, j)
+				c.data = append(c.data, work[j])
+			}
+		}
+		c.indptr[i+1] = len(c.data)
+	}
+	return c
+}
+
+/*
+ Returns the lines start to stop along the major axis
+*/
+func (c *compressed) majorSlice(start, stop int) compressed {
+	lo, hi := c.indptr[start], c.indptr[stop]
+	s := compressed{
+		major:   stop - start,
+		minor:   c.minor,
+		indptr:  make([]int, stop-start+1),
+		indices: append([]int(nil), c.indices[lo:hi]...),
+		data:    append([]float64(nil), c.data[lo:hi]...),
+	}
+	for i := range s.indptr {
+		s.indptr[i] = c.indptr[start+i] - lo
+	}
+	return s
+}
+
+/*
+ Returns positions start to stop along the minor axis of every line
+*/
+func (c *compressed) minorSlice(start, stop int) compressed {
+	s := newCompressed(c.major, stop-start, 0)
+	for i := 0; i < c.major; i++ {
+		line := c.indices[c.indptr[i]:c.indptr[i+1]]
+		for k := sort.SearchInts(line, start); k < len(line) && line[k] < stop; k++ {
+			s.indices = append(s.indices, line[k]-start)
+			s.data = append(s.data, c.data[c.indptr[i]+k])
+		}
+		s.indptr[i+1] = len(s.data)
+	}
+	return s
+}
+
+/*
+ Checks that start:stop is a valid range of an axis with n positions
+*/
+func checkRange(start, stop, n int) error {
+	if start < 0 || stop > n || start > stop {
+		return fmt.Errorf("%w: %d:%d of an axis with size %d", goSci.ErrInvalidSlice, start, stop, n)
+	}
+	return nil
+}
+
+/*
+ Returns the dense operand of a product as its rows, its number of columns and
+ whether it was a vector. k is the number of rows it must have.
+*/
+func denseOperand(op string, shape []int, b *goSci.GsArray, k int) ([]float64, int, bool, error) {
+	bShape := b.Shape()
+	if len(bShape) == 0 || len(bShape) > 2 || bShape[0] != k {
+		return nil, 0, false, &goSci.ShapeMismatchError{Op: op, Shape1: shape, Shape2: bShape}
+	}
+	if len(bShape) == 1 {
+		return b.Data(), 1, true, nil
+	}
+	return b.Data(), bShape[1], false, nil
+}
+
+/*
+ Returns the dense result of a product, a vector when the dense operand was one
+*/
+func denseResult(vals []float64, rows, n int, vector bool) *goSci.GsArray {
+	if vector {
+		return goSci.FromSlice(vals, rows)
+	}
+	return goSci.FromSlice(vals, rows, n)
+}
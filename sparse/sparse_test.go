@@ -0,0 +1,163 @@
+package sparse
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/lineback/goSci"
+)
+
+/*
+ Returns a random dense rows by cols matrix with about half of its elements zero
+*/
+func randomSparse(r *rand.Rand, rows, cols int) *goSci.GsArray {
+	data := make([]float64, rows*cols)
+	for i := range data {
+		if r.Intn(2) == 0 {
+			data[i] = float64(r.Intn(9) - 4)
+		}
+	}
+	return goSci.FromSlice(data, rows, cols)
+}
+
+func assertDense(t *testing.T, name string, got, want *goSci.GsArray) {
+	t.Helper()
+	gs, ws := got.Shape(), want.Shape()
+	if len(gs) != len(ws) {
+		t.Errorf("%s: got shape %v, want %v", name, gs, ws)
+		return
+	}
+	for i := range gs {
+		if gs[i] != ws[i] {
+			t.Errorf("%s: got shape %v, want %v", name, gs, ws)
+			return
+		}
+	}
+	gd, wd := got.Data(), want.Data()
+	for i := range gd {
+		if math.Abs(gd[i]-wd[i]) > 1e-12 {
+			t.Errorf("%s: got %v, want %v", name, gd, wd)
+			return
+		}
+	}
+}
+
+func TestCompressedOps(t *testing.T) {
+	r := rand.New(rand.NewSource(12))
+	a, b := randomSparse(r, 5, 4), randomSparse(r, 5, 4)
+	c := randomSparse(r, 4, 6)
+	x, xs := randomSparse(r, 4, 1).Copy(), randomSparse(r, 4, 3)
+	ax := goSci.MatMult(a, x)
+	x.Reshape(4)
+	ax.Reshape(5)
+
+	ra, _ := CSRFromDense(a)
+	rb, _ := CSRFromDense(b)
+	rc, _ := CSRFromDense(c)
+	ca, _ := CSCFromDense(a)
+	cb, _ := CSCFromDense(b)
+	cc, _ := CSCFromDense(c)
+
+	cases := []struct {
+		name      string
+		got, want *goSci.GsArray
+	}{
+		{"CSR Plus", ra.Plus(rb).ToDense(), goSci.Plus(a, b)},
+		{"CSR Minus", ra.Minus(rb).ToDense(), goSci.Minus(a, b)},
+		{"CSR ElemTimes", ra.ElemTimes(rb).ToDense(), goSci.ElemTimes(a, b)},
+		{"CSR Scale", ra.Scale(-2).ToDense(), goSci.Times(a, -2)},
+		{"CSR Mul", ra.Mul(rc).ToDense(), goSci.MatMult(a, c)},
+		{"CSR MulDense", ra.MulDense(xs), goSci.MatMult(a, xs)},
+		{"CSR MulDense of a vector", ra.MulDense(x), ax},
+		{"CSR T", ra.T().ToDense(), a.T().Copy()},
+		{"CSR RowSlice", ra.RowSlice(1, 4).ToDense(), a.Slice(goSci.Span(1, 4, 1)).Copy()},
+		{"CSR ColSlice", ra.ColSlice(2, 4).ToDense(), a.Slice(goSci.All, goSci.Span(2, 4, 1)).Copy()},
+		{"CSR Diagonal", ra.Diagonal(), goSci.FromSlice([]float64{a.Get(0, 0), a.Get(1, 1), a.Get(2, 2), a.Get(3, 3)}, 4)},
+		{"CSC Plus", ca.Plus(cb).ToDense(), goSci.Plus(a, b)},
+		{"CSC Minus", ca.Minus(cb).ToDense(), goSci.Minus(a, b)},
+		{"CSC ElemTimes", ca.ElemTimes(cb).ToDense(), goSci.ElemTimes(a, b)},
+		{"CSC Scale", ca.Scale(0.5).ToDense(), goSci.Times(a, 0.5)},
+		{"CSC Mul", ca.Mul(cc).ToDense(), goSci.MatMult(a, c)},
+		{"CSC MulDense", ca.MulDense(xs), goSci.MatMult(a, xs)},
+		{"CSC MulDense of a vector", ca.MulDense(x), ax},
+		{"CSC T", ca.T().ToDense(), a.T().Copy()},
+		{"CSC RowSlice", ca.RowSlice(0, 2).ToDense(), a.Slice(goSci.Span(0, 2, 1)).Copy()},
+		{"CSC ColSlice", ca.ColSlice(1, 3).ToDense(), a.Slice(goSci.All, goSci.Span(1, 3, 1)).Copy()},
+		{"CSR to CSC", ra.ToCSC().ToDense(), a},
+		{"CSC to CSR", ca.ToCSR().ToDense(), a},
+	}
+	for _, c := range cases {
+		assertDense(t, c.name, c.got, c.want)
+	}
+
+	// the results are valid storage with the zeros dropped
+	for name, m := range map[string]*compressed{
+		"CSR Plus":     &ra.Plus(rb).compressed,
+		"CSR Mul":      &ra.Mul(rc).compressed,
+		"CSC ColSlice": &ca.ColSlice(1, 3).compressed,
+		"CSR T":        &ra.T().compressed,
+	} {
+		if err := m.validate(name); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		for _, val := range m.data {
+			if val == 0 {
+				t.Errorf("%s stores a zero", name)
+				break
+			}
+		}
+	}
+	if nnz := ra.Minus(ra).NNZ(); nnz != 0 {
+		t.Errorf("CSR Minus of itself has %d nonzeros", nnz)
+	}
+	// T shares the storage of the matrix
+	if ra.T().At(2, 1) != ra.At(1, 2) || &ra.T().data[0] != &ra.data[0] {
+		t.Error("T does not share the storage of the matrix")
+	}
+}
+
+func TestCompressedErrors(t *testing.T) {
+	r4, _ := CSRFromDense(goSci.Zeros(4, 4))
+	r3, _ := CSRFromDense(goSci.Zeros(3, 4))
+	c4, _ := CSCFromDense(goSci.Zeros(4, 4))
+	c3, _ := CSCFromDense(goSci.Zeros(4, 3))
+	for name, err := range map[string]error{
+		"CSR Plus":      second(r4.TryPlus(r3)),
+		"CSR Minus":     second(r4.TryMinus(r3)),
+		"CSR ElemTimes": second(r4.TryElemTimes(r3)),
+		"CSR Mul":       second(r3.TryMul(r3)),
+		"CSR MulDense":  second(r4.TryMulDense(goSci.Zeros(3))),
+		"CSC Plus":      second(c4.TryPlus(c3)),
+		"CSC Minus":     second(c4.TryMinus(c3)),
+		"CSC ElemTimes": second(c4.TryElemTimes(c3)),
+		"CSC Mul":       second(c3.TryMul(c3)),
+		"CSC MulDense":  second(c4.TryMulDense(goSci.Zeros(3, 2))),
+	} {
+		if !errors.As(err, new(*goSci.ShapeMismatchError)) {
+			t.Errorf("%s with mismatched shapes: got %v", name, err)
+		}
+	}
+	for name, err := range map[string]error{
+		"CSR RowSlice": second(r4.TryRowSlice(2, 5)),
+		"CSR ColSlice": second(r4.TryColSlice(3, 2)),
+		"CSC RowSlice": second(c4.TryRowSlice(-1, 2)),
+		"CSC ColSlice": second(c3.TryColSlice(0, 4)),
+	} {
+		if !errors.Is(err, goSci.ErrInvalidSlice) {
+			t.Errorf("%s out of range: got %v", name, err)
+		}
+	}
+
+	defer func() {
+		if _, ok := recover().(*goSci.ShapeMismatchError); !ok {
+			t.Error("Plus with mismatched shapes did not panic with a ShapeMismatchError")
+		}
+	}()
+	r4.Plus(r3)
+}
+
+func second[T any](_ T, err error) error {
+	return err
+}
@@ -6,37 +6,36 @@ import "math"
  Returns the mean of the array
 */
 func Mean(x *GsArray, meanType uint) *GsArray {
-	sum := Sum(x, meanType)
-	switch meanType {
-	case ALL: 
-		return Times(sum, 1.0 / float64(len(x.data)))
-	case COLS: 
-		return Times(sum, 1.0 / float64(x.shape[0]))
-	case ROWS:
-		return Times(sum, 1.0 / float64(x.shape[1]))
-	default:
-		panic("Invalid mean type.")
+	return must(TryMean(x, meanType))
+}
+
+/*
+ Same as Mean but returns an InvalidAxisError instead of panicking
+*/
+func TryMean(x *GsArray, meanType uint) (*GsArray, error) {
+	sum, err := TrySum(x, meanType)
+	if err != nil {
+		return nil, err
 	}
-	return new(GsArray)
+	return Times(sum, float64(sum.Size()) / float64(x.Size())), nil
 }
 /*
  Returns the standard deviation of the array
 */
 func Stdev(x *GsArray, stdevType uint) *GsArray {
-	mean := Mean(x, stdevType)
-	var meanArray *GsArray
-	switch stdevType {
-	case ALL:
-		meanArray = Repmat(mean, x.shape[0], x.shape[1])
-	case COLS:
-		meanArray = Repmat(mean, x.shape[0], 1)
-	case ROWS:
-		meanArray = Repmat(mean, 1, x.shape[1])
-	default:
-		panic("Invalid stdev type.")
+	return must(TryStdev(x, stdevType))
+}
+
+/*
+ Same as Stdev but returns an InvalidAxisError instead of panicking
+*/
+func TryStdev(x *GsArray, stdevType uint) (*GsArray, error) {
+	mean, err := TryMean(x, stdevType)
+	if err != nil {
+		return nil, err
 	}
-	diff := Minus(x, meanArray)
-	diffSquared := ElemTimes(diff, diff)
+	diff := Lazy(x).Minus(Lazy(mean))
+	diffSquared := diff.ElemTimes(diff).Eval()
 	meanDiff := Mean(diffSquared, stdevType)
-	return ArrayFun(meanDiff, math.Sqrt)
-}
\ No newline at end of file
+	return ArrayFun(meanDiff, math.Sqrt), nil
+}
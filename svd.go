@@ -0,0 +1,257 @@
+package goSci
+
+import (
+	"math"
+	"sort"
+)
+
+/*
+ Largest number of sweeps jacobiSVD makes before giving up on convergence,
+ which SVD and the functions built on it report as ErrNoConvergence
+*/
+const jacobiSweeps = 60
+
+/*
+ One-sided Jacobi SVD of the m by n matrix a with m >= n, a = U*diag(s)*V^T.
+ Pairs of columns are rotated until every pair is orthogonal, at which point
+ the column norms are the singular values. Returns U (m by n), s in decreasing
+ order and V (n by n), row major. Columns of U for zero singular values are zero.
+ ok is false if some pair of columns was still rotated in the last sweep.
+*/
+func jacobiSVD(m, n int, a []float64, lda int) (u, s, v []float64, ok bool) {
+	// w holds the columns of a as rows, vt the rows of V^T
+	w := make([]float64, n*m)
+	vt := make([]float64, n*n)
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			w[j*m+i] = a[i*lda+j]
+		}
+	}
+	for j := 0; j < n; j++ {
+		vt[j*n+j] = 1
+	}
+	const eps = 2.220446049250313e-16
+	for sweep := 0; sweep < jacobiSweeps; sweep++ {
+		rotated := false
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				wp, wq := w[p*m:(p+1)*m], w[q*m:(q+1)*m]
+				alpha := engine.Ddot(m, wp, 1, wp, 1)
+				beta := engine.Ddot(m, wq, 1, wq, 1)
+				gamma := engine.Ddot(m, wp, 1, wq, 1)
+				if gamma == 0 || math.Abs(gamma) <= eps*math.Sqrt(alpha*beta) {
+					continue
+				}
+				rotated = true
+				zeta := (beta - alpha) / (2 * gamma)
+				t := math.Copysign(1, zeta) / (math.Abs(zeta) + math.Sqrt(1+zeta*zeta))
+				c := 1 / math.Sqrt(1+t*t)
+				sn := c * t
+				rotate(wp, wq, c, sn)
+				rotate(vt[p*n:(p+1)*n], vt[q*n:(q+1)*n], c, sn)
+			}
+		}
+		if !rotated {
+			ok = true
+			break
+		}
+	}
+	s = make([]float64, n)
+	order := make([]int, n)
+	for j := range s {
+		s[j] = engine.Dnrm2(m, w[j*m:], 1)
+		order[j] = j
+	}
+	sort.SliceStable(order, func(i, j int) bool { return s[order[i]] > s[order[j]] })
+	u = make([]float64, m*n)
+	v = make([]float64, n*n)
+	sorted := make([]float64, n)
+	for k, j := range order {
+		sorted[k] = s[j]
+		for i := 0; i < m; i++ {
+			if s[j] != 0 {
+				u[i*n+k] = w[j*m+i] / s[j]
+			}
+		}
+		for i := 0; i < n; i++ {
+			v[i*n+k] = vt[j*n+i]
+		}
+	}
+	return u, sorted, v, ok
+}
+
+/*
+ Applies the plane rotation [c -s; s c] to the pairs of elements of x and y
+*/
+func rotate(x, y []float64, c, s float64) {
+	for i := range x {
+		xi, yi := x[i], y[i]
+		x[i] = c*xi - s*yi
+		y[i] = s*xi + c*yi
+	}
+}
+
+/*
+ Returns the singular value decomposition a = u*diag(s)*vt of the m by n
+ matrix a, with the singular values s in decreasing order. For the thin
+ decomposition u is m by k and vt is k by n with k = min(m, n); the full
+ decomposition completes u to m by m and vt to n by n. The columns of u and
+ the rows of vt are orthonormal. Computed with the one-sided Jacobi method,
+ which finds small singular values to high relative accuracy, and returns
+ ErrNoConvergence if it does not converge, as it cannot when a holds NaN.
+*/
+func SVD(a *GsArray, full bool) (u, s, vt *GsArray, err error) {
+	if len(a.shape) != 2 {
+		return nil, nil, nil, shapeError("SVD", a.shape, nil)
+	}
+	m, n := a.shape[0], a.shape[1]
+	// a wide matrix is decomposed through its transpose, a^T = V*S*U^T
+	wide := m < n
+	rows, cols := m, n
+	var data []float64
+	if wide {
+		rows, cols = n, m
+		data = a.T().Copy().data
+	} else {
+		data = a.Copy().data
+	}
+	uu, ss, vv, ok := jacobiSVD(rows, cols, data, max(cols, 1))
+	if !ok {
+		return nil, nil, nil, ErrNoConvergence
+	}
+	ucols := cols
+	if full {
+		ucols = rows
+	}
+	left := completeBasis(rows, cols, uu, ss, ucols)
+	s = &GsArray{data: ss, shape: []int{len(ss)}}
+	if !wide {
+		return &GsArray{data: left, shape: []int{rows, ucols}}, s, (&GsArray{data: vv, shape: []int{cols, cols}}).T().Copy(), nil
+	}
+	u = &GsArray{data: vv, shape: []int{cols, cols}}
+	vt = (&GsArray{data: left, shape: []int{rows, ucols}}).T().Copy()
+	return u, s, vt, nil
+}
+
+/*
+ Returns the m by k matrix u, m by n on entry, with the columns for zero
+ singular values replaced and new columns appended so that all k columns are
+ orthonormal. Each new column is the unit vector farthest from the span of the
+ columns so far, orthogonalized twice against them.
+*/
+func completeBasis(m, n int, u, s []float64, k int) []float64 {
+	out := make([]float64, m*k)
+	for i := 0; i < m; i++ {
+		copy(out[i*k:i*k+n], u[i*n:(i+1)*n])
+	}
+	col := make([]float64, m)
+	best := make([]float64, m)
+	for j := 0; j < k; j++ {
+		if j < n && s[j] != 0 {
+			continue
+		}
+		bestNorm := -1.0
+		for e := 0; e < m; e++ {
+			for i := range col {
+				col[i] = 0
+			}
+			col[e] = 1
+			for pass := 0; pass < 2; pass++ {
+				for c := 0; c < k; c++ {
+					if c == j || (c > j && (c >= n || s[c] == 0)) {
+						continue
+					}
+					dot := engine.Ddot(m, out[c:], k, col, 1)
+					engine.Daxpy(m, -dot, out[c:], k, col, 1)
+				}
+			}
+			if norm := engine.Dnrm2(m, col, 1); norm > bestNorm {
+				bestNorm = norm
+				copy(best, col)
+			}
+			if bestNorm > 0.5 {
+				break
+			}
+		}
+		for i := 0; i < m; i++ {
+			out[i*k+j] = best[i] / bestNorm
+		}
+	}
+	return out
+}
+
+/*
+ Returns the Moore-Penrose pseudo-inverse of the matrix a, computed from its
+ singular value decomposition. Singular values below rcond times the largest
+ are treated as zero; rcond <= 0 uses machine epsilon times max(m, n).
+*/
+func Pinv(a *GsArray, rcond float64) (*GsArray, error) {
+	if len(a.shape) != 2 {
+		return nil, shapeError("Pinv", a.shape, nil)
+	}
+	m, n := a.shape[0], a.shape[1]
+	u, s, vt, err := SVD(a, false)
+	if err != nil {
+		return nil, err
+	}
+	if rcond <= 0 {
+		rcond = 2.220446049250313e-16 * float64(max(m, n))
+	}
+	rank := svdRank(s.data, rcond)
+	// a^+ = V*S^+*U^T built from V (n by k) and U^T scaled row by row
+	ut := u.T().Copy()
+	for i := 0; i < rank; i++ {
+		engine.Dscal(m, 1/s.data[i], ut.data[i*m:], 1)
+	}
+	x := Zeros(n, m)
+	engine.Dgemm(true, false, n, m, rank, 1, vt.data, max(n, 1), ut.data, max(m, 1), 0, x.data, max(m, 1))
+	return x, nil
+}
+
+/*
+ Returns the rank of the matrix a, the number of its singular values greater
+ than tol. tol <= 0 uses the largest singular value times machine epsilon
+ times max(m, n).
+*/
+func Rank(a *GsArray, tol float64) (int, error) {
+	if len(a.shape) != 2 {
+		return 0, shapeError("Rank", a.shape, nil)
+	}
+	_, sv, _, err := SVD(a, false)
+	if err != nil {
+		return 0, err
+	}
+	if tol <= 0 {
+		return svdRank(sv.data, 2.220446049250313e-16*float64(max(a.shape[0], a.shape[1]))), nil
+	}
+	rank := 0
+	for _, val := range sv.data {
+		if val > tol {
+			rank++
+		}
+	}
+	return rank, nil
+}
+
+/*
+ Returns the 2-norm condition number of the matrix a, the ratio of its
+ largest to its smallest singular value, which is +Inf for a rank deficient
+ matrix
+*/
+func Cond(a *GsArray) (float64, error) {
+	if len(a.shape) != 2 {
+		return 0, shapeError("Cond", a.shape, nil)
+	}
+	_, sv, _, err := SVD(a, false)
+	if err != nil {
+		return 0, err
+	}
+	s := sv.data
+	if len(s) == 0 {
+		return 0, nil
+	}
+	if s[len(s)-1] == 0 {
+		return math.Inf(1), nil
+	}
+	return s[0] / s[len(s)-1], nil
+}
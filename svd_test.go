@@ -0,0 +1,78 @@
+package goSci
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSVDResidual(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for _, dims := range [][2]int{{5, 3}, {3, 5}, {4, 4}, {1, 3}, {6, 1}} {
+		a := randomArray(r, dims[0], dims[1])
+		for _, full := range []bool{false, true} {
+			u, s, vt, err := SVD(a, full)
+			if err != nil {
+				t.Fatalf("%v: %v", dims, err)
+			}
+			sigma := Zeros(u.shape[1], vt.shape[0])
+			for i := range s.data {
+				sigma.Put(s.Get(i), i, i)
+			}
+			assertClose(t, "U*S*V^T", MatMult(MatMult(u, sigma), vt), a, 1e-12)
+			assertClose(t, "U^T*U", MatMult(u.T(), u), Eye(u.shape[1]), 1e-12)
+			assertClose(t, "V^T*V", MatMult(vt, vt.T()), Eye(vt.shape[0]), 1e-12)
+		}
+		p, err := Pinv(a, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertClose(t, "A*A+*A", MatMult(MatMult(a, p), a), a, 1e-12)
+		assertClose(t, "A+*A*A+", MatMult(MatMult(p, a), p), p, 1e-10)
+	}
+}
+
+func TestSVDRankDeficient(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	a := randomArray(r, 6, 3)
+	for i := 0; i < 6; i++ {
+		a.Put(a.Get(i, 0)+a.Get(i, 1), i, 2)
+	}
+	if rank, _ := Rank(a, 0); rank != 2 {
+		t.Errorf("Rank = %d, want 2", rank)
+	}
+	u, _, _, _ := SVD(a, true)
+	assertClose(t, "full U of a deficient matrix", MatMult(u.T(), u), Eye(6), 1e-12)
+
+	if c, _ := Cond(Eye(3)); c != 1 {
+		t.Errorf("Cond(I) = %g", c)
+	}
+	if c, _ := Cond(Zeros(3, 2)); !math.IsInf(c, 1) {
+		t.Errorf("Cond(0) = %g, want +Inf", c)
+	}
+}
+
+func TestSVDNoConvergence(t *testing.T) {
+	// no rotation makes a column holding NaN orthogonal to the others
+	a := FromSlice([]float64{1, 2, math.NaN(), 4, 5, 6}, 3, 2)
+	_, _, _, err := SVD(a, false)
+	_, _, _, wideErr := SVD(a.T(), true)
+	for name, err := range map[string]error{
+		"SVD":        err,
+		"SVD wide":   wideErr,
+		"Pinv":       second(Pinv(a, 0)),
+		"Rank":       second(Rank(a, 0)),
+		"Cond":       second(Cond(a)),
+		"Lstsq":      second(Lstsq(a, Ones(3), 0)),
+		"Lstsq wide": second(Lstsq(a.T(), Ones(2), 0)),
+		"TryNorm":    second(TryNorm(a, Spectral)),
+	} {
+		if !errors.Is(err, ErrNoConvergence) {
+			t.Errorf("%s of a matrix with NaN: got %v, want ErrNoConvergence", name, err)
+		}
+	}
+	if _, _, _, ok := jacobiSVD(3, 2, []float64{1, 2, 3, 4, 5, 6}, 2); !ok {
+		t.Error("jacobiSVD did not converge for a well conditioned matrix")
+	}
+}
@@ -0,0 +1,461 @@
+package goSci
+
+import "math"
+
+/*
+ The elementwise math functions below, the ufuncs, return a new array unless
+ an out array is passed, in which case the result is written to out and out
+ is returned. out must have the shape of the result and may be one of the
+ arguments; an out of the wrong shape panics with a ShapeMismatchError. The
+ InPlace methods overwrite their receiver.
+ e.g.
+ y := Exp(x)      // new array
+ Exp(x, buffer)   // into an existing array
+ x.ExpInPlace()   // overwrites x
+*/
+
+/*
+ Returns e raised to each element of x
+*/
+func Exp(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Exp", math.Exp, x, out))
+}
+
+func (x *GsArray) ExpInPlace() {
+	unaryInPlace(math.Exp, x)
+}
+
+/*
+ Returns the natural logarithm of each element of x
+*/
+func Log(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Log", math.Log, x, out))
+}
+
+func (x *GsArray) LogInPlace() {
+	unaryInPlace(math.Log, x)
+}
+
+/*
+ Returns log(1 + x) elementwise, accurate for x near zero
+*/
+func Log1p(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Log1p", math.Log1p, x, out))
+}
+
+func (x *GsArray) Log1pInPlace() {
+	unaryInPlace(math.Log1p, x)
+}
+
+/*
+ Returns e^x - 1 elementwise, accurate for x near zero
+*/
+func Expm1(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Expm1", math.Expm1, x, out))
+}
+
+func (x *GsArray) Expm1InPlace() {
+	unaryInPlace(math.Expm1, x)
+}
+
+/*
+ Returns the square root of each element of x
+*/
+func Sqrt(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Sqrt", math.Sqrt, x, out))
+}
+
+func (x *GsArray) SqrtInPlace() {
+	unaryInPlace(math.Sqrt, x)
+}
+
+/*
+ Returns the absolute value of each element of x
+*/
+func Abs(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Abs", math.Abs, x, out))
+}
+
+func (x *GsArray) AbsInPlace() {
+	unaryInPlace(math.Abs, x)
+}
+
+/*
+ Returns the sign of each element of x: -1, 0 or 1, and NaN for NaN
+*/
+func Sign(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Sign", sign, x, out))
+}
+
+func (x *GsArray) SignInPlace() {
+	unaryInPlace(sign, x)
+}
+
+/*
+ Returns the sine of each element of x, in radians
+*/
+func Sin(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Sin", math.Sin, x, out))
+}
+
+func (x *GsArray) SinInPlace() {
+	unaryInPlace(math.Sin, x)
+}
+
+/*
+ Returns the cosine of each element of x, in radians
+*/
+func Cos(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Cos", math.Cos, x, out))
+}
+
+func (x *GsArray) CosInPlace() {
+	unaryInPlace(math.Cos, x)
+}
+
+/*
+ Returns the tangent of each element of x, in radians
+*/
+func Tan(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Tan", math.Tan, x, out))
+}
+
+func (x *GsArray) TanInPlace() {
+	unaryInPlace(math.Tan, x)
+}
+
+/*
+ Returns the inverse sine of each element of x
+*/
+func Asin(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Asin", math.Asin, x, out))
+}
+
+func (x *GsArray) AsinInPlace() {
+	unaryInPlace(math.Asin, x)
+}
+
+/*
+ Returns the inverse cosine of each element of x
+*/
+func Acos(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Acos", math.Acos, x, out))
+}
+
+func (x *GsArray) AcosInPlace() {
+	unaryInPlace(math.Acos, x)
+}
+
+/*
+ Returns the inverse tangent of each element of x
+*/
+func Atan(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Atan", math.Atan, x, out))
+}
+
+func (x *GsArray) AtanInPlace() {
+	unaryInPlace(math.Atan, x)
+}
+
+/*
+ Returns the hyperbolic sine of each element of x
+*/
+func Sinh(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Sinh", math.Sinh, x, out))
+}
+
+func (x *GsArray) SinhInPlace() {
+	unaryInPlace(math.Sinh, x)
+}
+
+/*
+ Returns the hyperbolic cosine of each element of x
+*/
+func Cosh(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Cosh", math.Cosh, x, out))
+}
+
+func (x *GsArray) CoshInPlace() {
+	unaryInPlace(math.Cosh, x)
+}
+
+/*
+ Returns the hyperbolic tangent of each element of x
+*/
+func Tanh(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Tanh", math.Tanh, x, out))
+}
+
+func (x *GsArray) TanhInPlace() {
+	unaryInPlace(math.Tanh, x)
+}
+
+/*
+ Returns the inverse hyperbolic sine of each element of x
+*/
+func Asinh(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Asinh", math.Asinh, x, out))
+}
+
+func (x *GsArray) AsinhInPlace() {
+	unaryInPlace(math.Asinh, x)
+}
+
+/*
+ Returns the inverse hyperbolic cosine of each element of x
+*/
+func Acosh(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Acosh", math.Acosh, x, out))
+}
+
+func (x *GsArray) AcoshInPlace() {
+	unaryInPlace(math.Acosh, x)
+}
+
+/*
+ Returns the inverse hyperbolic tangent of each element of x
+*/
+func Atanh(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Atanh", math.Atanh, x, out))
+}
+
+func (x *GsArray) AtanhInPlace() {
+	unaryInPlace(math.Atanh, x)
+}
+
+/*
+ Returns each element of x rounded down to an integer
+*/
+func Floor(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Floor", math.Floor, x, out))
+}
+
+func (x *GsArray) FloorInPlace() {
+	unaryInPlace(math.Floor, x)
+}
+
+/*
+ Returns each element of x rounded up to an integer
+*/
+func Ceil(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Ceil", math.Ceil, x, out))
+}
+
+func (x *GsArray) CeilInPlace() {
+	unaryInPlace(math.Ceil, x)
+}
+
+/*
+ Returns each element of x rounded to the nearest integer, halves away
+ from zero
+*/
+func Round(x *GsArray, out ...*GsArray) *GsArray {
+	return must(unaryOp("Round", math.Round, x, out))
+}
+
+func (x *GsArray) RoundInPlace() {
+	unaryInPlace(math.Round, x)
+}
+
+/*
+ Returns x with each element limited to the range [lo, hi]
+*/
+func Clip(x *GsArray, lo, hi float64, out ...*GsArray) *GsArray {
+	return must(unaryOp("Clip", clipper(lo, hi), x, out))
+}
+
+func (x *GsArray) ClipInPlace(lo, hi float64) {
+	unaryInPlace(clipper(lo, hi), x)
+}
+
+/*
+ Returns the larger of x and y elementwise, NaN where either is NaN. The
+ arrays are broadcast to a common shape.
+*/
+func Maximum(x, y *GsArray, out ...*GsArray) *GsArray {
+	return must(TryMaximum(x, y, out...))
+}
+
+/*
+ Same as Maximum but returns a ShapeMismatchError instead of panicking
+*/
+func TryMaximum(x, y *GsArray, out ...*GsArray) (*GsArray, error) {
+	return binaryOp("Maximum", math.Max, x, y, out)
+}
+
+/*
+ Stores Maximum(x, y) in x, broadcasting y to the shape of x
+*/
+func (x *GsArray) MaximumInPlace(y *GsArray) {
+	mustDo(broadcastOpInPlace("MaximumInPlace", x, y, math.Max))
+}
+
+/*
+ Returns the smaller of x and y elementwise, NaN where either is NaN. The
+ arrays are broadcast to a common shape.
+*/
+func Minimum(x, y *GsArray, out ...*GsArray) *GsArray {
+	return must(TryMinimum(x, y, out...))
+}
+
+/*
+ Same as Minimum but returns a ShapeMismatchError instead of panicking
+*/
+func TryMinimum(x, y *GsArray, out ...*GsArray) (*GsArray, error) {
+	return binaryOp("Minimum", math.Min, x, y, out)
+}
+
+/*
+ Stores Minimum(x, y) in x, broadcasting y to the shape of x
+*/
+func (x *GsArray) MinimumInPlace(y *GsArray) {
+	mustDo(broadcastOpInPlace("MinimumInPlace", x, y, math.Min))
+}
+
+/*
+ Returns sqrt(x*x + y*y) elementwise without undue overflow. The arrays
+ are broadcast to a common shape.
+*/
+func Hypot(x, y *GsArray, out ...*GsArray) *GsArray {
+	return must(TryHypot(x, y, out...))
+}
+
+/*
+ Same as Hypot but returns a ShapeMismatchError instead of panicking
+*/
+func TryHypot(x, y *GsArray, out ...*GsArray) (*GsArray, error) {
+	return binaryOp("Hypot", math.Hypot, x, y, out)
+}
+
+/*
+ Stores Hypot(x, y) in x, broadcasting y to the shape of x
+*/
+func (x *GsArray) HypotInPlace(y *GsArray) {
+	mustDo(broadcastOpInPlace("HypotInPlace", x, y, math.Hypot))
+}
+
+/*
+ Returns the angle of the points (x, y) elementwise, the inverse tangent
+ of y/x in the quadrant of the point. The arrays are broadcast to a common
+ shape.
+*/
+func Atan2(y, x *GsArray, out ...*GsArray) *GsArray {
+	return must(TryAtan2(y, x, out...))
+}
+
+/*
+ Same as Atan2 but returns a ShapeMismatchError instead of panicking
+*/
+func TryAtan2(y, x *GsArray, out ...*GsArray) (*GsArray, error) {
+	return binaryOp("Atan2", math.Atan2, y, x, out)
+}
+
+/*
+ Stores Atan2(y, x) in y, broadcasting x to the shape of y
+*/
+func (y *GsArray) Atan2InPlace(x *GsArray) {
+	mustDo(broadcastOpInPlace("Atan2InPlace", y, x, math.Atan2))
+}
+
+func sign(val float64) float64 {
+	switch {
+	case val > 0:
+		return 1
+	case val < 0:
+		return -1
+	}
+	return val
+}
+
+func clipper(lo, hi float64) func(float64) float64 {
+	return func(val float64) float64 {
+		return math.Min(math.Max(val, lo), hi)
+	}
+}
+
+/*
+ Returns f applied to each element of x, written to out[0] if it is given.
+ name is the operation reported in a ShapeMismatchError.
+*/
+func unaryOp(name string, f func(float64) float64, x *GsArray, out []*GsArray) (*GsArray, error) {
+	if len(out) == 0 {
+		result := Zeros(x.Shape()...)
+		vals := x.values()
+		parallelFor(len(vals), len(vals), func(start, end int) {
+			for i := start; i < end; i++ {
+				result.data[i] = f(vals[i])
+			}
+		})
+		return result, nil
+	}
+	dst := out[0]
+	if !sameShape(dst.shape, x.shape) {
+		return nil, shapeError(name, x.shape, dst.shape)
+	}
+	if dst == x {
+		unaryInPlace(f, dst)
+		return dst, nil
+	}
+	if overlaps(dst, x) {
+		x = x.Copy()
+	}
+	if dst.strides == nil && x.strides == nil {
+		parallelFor(len(x.data), len(x.data), func(start, end int) {
+			for i := start; i < end; i++ {
+				dst.data[i] = f(x.data[i])
+			}
+		})
+		return dst, nil
+	}
+	strides := [][]int{dst.getStrides(), x.getStrides()}
+	parallelWalk(x.shape, splitAxis(x.shape), []int{dst.offset, x.offset}, strides, func(pos []int) {
+		dst.data[pos[0]] = f(x.data[pos[1]])
+	})
+	return dst, nil
+}
+
+func unaryInPlace(f func(float64) float64, x *GsArray) {
+	if x.strides == nil {
+		parallelFor(len(x.data), len(x.data), func(start, end int) {
+			for i := start; i < end; i++ {
+				x.data[i] = f(x.data[i])
+			}
+		})
+		return
+	}
+	parallelWalk(x.shape, splitAxis(x.shape), []int{x.offset}, [][]int{x.strides}, func(pos []int) {
+		x.data[pos[0]] = f(x.data[pos[0]])
+	})
+}
+
+/*
+ Returns op applied elementwise to x and y broadcast to a common shape,
+ written to out[0] if it is given. name is the operation reported in a
+ ShapeMismatchError.
+*/
+func binaryOp(name string, op func(a, b float64) float64, x, y *GsArray, out []*GsArray) (*GsArray, error) {
+	if len(out) == 0 {
+		return broadcastOp(name, x, y, op)
+	}
+	dst := out[0]
+	shape, ok := broadcastShapes(x.shape, y.shape)
+	if !ok {
+		return nil, shapeError(name, x.shape, y.shape)
+	}
+	if !sameShape(shape, dst.shape) {
+		return nil, shapeError(name, shape, dst.shape)
+	}
+	// an argument that is out itself is read at each position before it is
+	// written, any other sharing of data needs a copy
+	if dst != x && overlaps(dst, x) {
+		x = x.Copy()
+	}
+	if dst != y && overlaps(dst, y) {
+		y = y.Copy()
+	}
+	strides := [][]int{dst.getStrides(), broadcastStrides(x, shape), broadcastStrides(y, shape)}
+	parallelWalk(shape, splitAxis(shape), []int{dst.offset, x.offset, y.offset}, strides, func(pos []int) {
+		dst.data[pos[0]] = op(x.data[pos[1]], y.data[pos[2]])
+	})
+	return dst, nil
+}
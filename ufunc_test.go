@@ -0,0 +1,182 @@
+package goSci
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+/*
+ Reports whether got and want are the same float, counting NaN as equal to NaN
+*/
+func sameFloat(got, want float64) bool {
+	return got == want || math.IsNaN(got) && math.IsNaN(want)
+}
+
+func assertSameFloats(t *testing.T, name string, got *GsArray, want []float64) {
+	t.Helper()
+	vals := got.Data()
+	if len(vals) != len(want) {
+		t.Errorf("%s: got %v, want %v", name, vals, want)
+		return
+	}
+	for i := range vals {
+		if !sameFloat(vals[i], want[i]) {
+			t.Errorf("%s: got %v, want %v", name, vals, want)
+			return
+		}
+	}
+}
+
+func TestUnaryUfuncs(t *testing.T) {
+	inputs := []float64{-2.5, -1, -0.5, 0, 0.3, 0.5, 1, 2.5, 10, math.Inf(1), math.Inf(-1), math.NaN()}
+	x := FromSlice(inputs, 3, 4)
+	cases := []struct {
+		name    string
+		ufunc   func(*GsArray, ...*GsArray) *GsArray
+		inPlace func(*GsArray)
+		ref     func(float64) float64
+	}{
+		{"Exp", Exp, (*GsArray).ExpInPlace, math.Exp},
+		{"Log", Log, (*GsArray).LogInPlace, math.Log},
+		{"Log1p", Log1p, (*GsArray).Log1pInPlace, math.Log1p},
+		{"Expm1", Expm1, (*GsArray).Expm1InPlace, math.Expm1},
+		{"Sqrt", Sqrt, (*GsArray).SqrtInPlace, math.Sqrt},
+		{"Abs", Abs, (*GsArray).AbsInPlace, math.Abs},
+		{"Sign", Sign, (*GsArray).SignInPlace, sign},
+		{"Sin", Sin, (*GsArray).SinInPlace, math.Sin},
+		{"Cos", Cos, (*GsArray).CosInPlace, math.Cos},
+		{"Tan", Tan, (*GsArray).TanInPlace, math.Tan},
+		{"Asin", Asin, (*GsArray).AsinInPlace, math.Asin},
+		{"Acos", Acos, (*GsArray).AcosInPlace, math.Acos},
+		{"Atan", Atan, (*GsArray).AtanInPlace, math.Atan},
+		{"Sinh", Sinh, (*GsArray).SinhInPlace, math.Sinh},
+		{"Cosh", Cosh, (*GsArray).CoshInPlace, math.Cosh},
+		{"Tanh", Tanh, (*GsArray).TanhInPlace, math.Tanh},
+		{"Asinh", Asinh, (*GsArray).AsinhInPlace, math.Asinh},
+		{"Acosh", Acosh, (*GsArray).AcoshInPlace, math.Acosh},
+		{"Atanh", Atanh, (*GsArray).AtanhInPlace, math.Atanh},
+		{"Floor", Floor, (*GsArray).FloorInPlace, math.Floor},
+		{"Ceil", Ceil, (*GsArray).CeilInPlace, math.Ceil},
+		{"Round", Round, (*GsArray).RoundInPlace, math.Round},
+		{"Clip", func(x *GsArray, out ...*GsArray) *GsArray { return Clip(x, -1, 2, out...) },
+			func(x *GsArray) { x.ClipInPlace(-1, 2) }, clipper(-1, 2)},
+	}
+	for _, c := range cases {
+		want := make([]float64, len(inputs))
+		for i, val := range inputs {
+			want[i] = c.ref(val)
+		}
+		assertSameFloats(t, c.name, c.ufunc(x), want)
+
+		// the same values read through a transposed view
+		wantT := make([]float64, len(inputs))
+		for i := 0; i < 4; i++ {
+			for j := 0; j < 3; j++ {
+				wantT[i*3+j] = want[j*4+i]
+			}
+		}
+		assertSameFloats(t, c.name+" of a transpose", c.ufunc(x.T()), wantT)
+
+		y := x.Copy()
+		if got := c.ufunc(y, y); got != y {
+			t.Errorf("%s with out the same as x did not return out", c.name)
+		}
+		assertSameFloats(t, c.name+" into x", y, want)
+
+		y = x.Copy()
+		c.inPlace(y)
+		assertSameFloats(t, c.name+"InPlace", y, want)
+		y = x.Copy().T()
+		c.inPlace(y)
+		assertSameFloats(t, c.name+"InPlace of a transpose", y, wantT)
+	}
+}
+
+func TestUfuncOut(t *testing.T) {
+	x := FromSlice([]float64{1, 4, 9, 16, 25, 36}, 2, 3)
+
+	// out a strided view of a larger array, the rest of which is untouched
+	big := Full(-1, 2, 6)
+	out := big.Slice(All, Span(1, 6, 2))
+	if got := Sqrt(x, out); got != out {
+		t.Error("Sqrt did not return out")
+	}
+	assertArray(t, "into a strided view", big, []int{2, 6}, []float64{-1, 1, -1, 2, -1, 3, -1, 4, -1, 5, -1, 6})
+
+	// out overlapping x shifted by one element is written from a copy of x
+	v := FromSlice([]float64{1, 4, 9, 16, 25}, 5)
+	Sqrt(v.Slice(Span(0, 4, 1)), v.Slice(Span(1, 5, 1)))
+	assertArray(t, "into a shifted overlap", v, []int{5}, []float64{1, 1, 2, 3, 4})
+	// and reversed
+	v = FromSlice([]float64{1, 4, 9, 16}, 4)
+	Sqrt(v, v.Slice(Span(Omit, Omit, -1)))
+	assertArray(t, "into a reversed overlap", v, []int{4}, []float64{4, 3, 2, 1})
+
+	// a binary ufunc into one of its arguments, or over a view of one
+	a := FromSlice([]float64{3, -1, 4, -1}, 2, 2)
+	b := FromSlice([]float64{0, 2}, 2)
+	Maximum(a, b, a)
+	assertArray(t, "Maximum into x", a, []int{2, 2}, []float64{3, 2, 4, 2})
+	Minimum(a, a.T(), a)
+	assertArray(t, "Minimum into x with its transpose", a, []int{2, 2}, []float64{3, 2, 2, 2})
+	c := FromSlice([]float64{3, 4}, 2)
+	d := FromSlice([]float64{4, 3}, 2)
+	Hypot(c, d, d)
+	assertArray(t, "Hypot into y", d, []int{2}, []float64{5, 5})
+	y := FromSlice([]float64{1, 1, -1}, 3)
+	Atan2(y, y.Slice(Span(Omit, Omit, -1)), y)
+	assertClose(t, "Atan2 into y over a reversed view", y, FromSlice([]float64{math.Atan2(1, -1), math.Pi / 4, math.Atan2(-1, 1)}, 3), 1e-15)
+}
+
+func TestUfuncShapeErrors(t *testing.T) {
+	x := Zeros(2, 3)
+	if _, err := unaryOp("Exp", math.Exp, x, []*GsArray{Zeros(3, 2)}); !errors.As(err, new(*ShapeMismatchError)) {
+		t.Errorf("unary out of the wrong shape: got %v", err)
+	}
+	for name, err := range map[string]error{
+		"Maximum": second(TryMaximum(x, Zeros(3), Zeros(3))),
+		"Minimum": second(TryMinimum(x, Zeros(3, 1), Zeros(3))),
+		"Hypot":   second(TryHypot(x, Zeros(2))),
+		"Atan2":   second(TryAtan2(x, Zeros(1), Zeros(6))),
+	} {
+		var sm *ShapeMismatchError
+		if !errors.As(err, &sm) || sm.Op != name {
+			t.Errorf("%s with mismatched shapes: got %v", name, err)
+		}
+	}
+
+	defer func() {
+		if _, ok := recover().(*ShapeMismatchError); !ok {
+			t.Error("Exp into an out of the wrong shape did not panic with a ShapeMismatchError")
+		}
+	}()
+	Exp(x, Zeros(6))
+}
+
+func TestMaximumMinimum(t *testing.T) {
+	nan := math.NaN()
+	x := FromSlice([]float64{1, nan, 3, math.Inf(-1), 0, 2}, 2, 3)
+	y := FromSlice([]float64{2, 0, nan}, 3)
+	// NaN in either argument gives NaN, y is broadcast over the rows of x
+	assertSameFloats(t, "Maximum", Maximum(x, y), []float64{2, nan, nan, 2, 0, nan})
+	assertSameFloats(t, "Minimum", Minimum(x, y), []float64{1, nan, nan, math.Inf(-1), 0, nan})
+	assertSameFloats(t, "Maximum reversed", Maximum(y, x), []float64{2, nan, nan, 2, 0, nan})
+
+	in := x.Copy()
+	in.MaximumInPlace(y)
+	assertSameFloats(t, "MaximumInPlace", in, []float64{2, nan, nan, 2, 0, nan})
+	in = x.Copy()
+	in.MinimumInPlace(FromSlice([]float64{0}, 1))
+	assertSameFloats(t, "MinimumInPlace", in, []float64{0, nan, 0, math.Inf(-1), 0, 0})
+	if got := Minimum(FromSlice([]float64{0}, 1), FromSlice([]float64{math.Copysign(0, -1)}, 1)).Get(0); !math.Signbit(got) {
+		t.Errorf("Minimum of 0 and -0: got %v, want -0", got)
+	}
+
+	h := FromSlice([]float64{3, 5}, 2)
+	h.HypotInPlace(FromSlice([]float64{4, 12}, 2))
+	assertArray(t, "HypotInPlace", h, []int{2}, []float64{5, 13})
+	a := FromSlice([]float64{1, -1}, 2)
+	a.Atan2InPlace(FromSlice([]float64{0}, 1))
+	assertClose(t, "Atan2InPlace", a, FromSlice([]float64{math.Pi / 2, -math.Pi / 2}, 2), 1e-15)
+}
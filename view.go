@@ -0,0 +1,470 @@
+package goSci
+
+import (
+	"fmt"
+	"math"
+)
+
+/*
+ Marks an omitted start or stop in Span, like a blank bound in a numpy
+ slice: x.Slice(Span(Omit, Omit, -1)) reverses the first axis.
+*/
+const Omit = math.MinInt
+
+const (
+	spanIndex = iota
+	atIndex
+	newAxisIndex
+	ellipsisIndex
+)
+
+/*
+ One entry of a Slice call. Build them with Span and At, or use All,
+ NewAxis and Ellipsis.
+*/
+type Index struct {
+	kind  int
+	start int
+	stop  int
+	step  int
+}
+
+var (
+	// Selects every element of an axis, like ':'
+	All = Index{kind: spanIndex, start: Omit, stop: Omit, step: 1}
+	// Inserts a new axis of length one
+	NewAxis = Index{kind: newAxisIndex}
+	// Stands for as many All entries as are needed to cover the remaining axes
+	Ellipsis = Index{kind: ellipsisIndex}
+)
+
+/*
+ Selects start:stop:step along an axis. Negative bounds count back from the
+ end of the axis and Omit leaves a bound open.
+*/
+func Span(start, stop, step int) Index {
+	return Index{kind: spanIndex, start: start, stop: stop, step: step}
+}
+
+/*
+ Selects the single position i along an axis and drops the axis from the
+ result. Negative values count back from the end of the axis.
+*/
+func At(i int) Index {
+	return Index{kind: atIndex, start: i}
+}
+
+/*
+ Returns a view of x selected by idx, one entry per axis. The view shares
+ its data with x, so Put on the view or any of the accumulating operations
+ write through to x. Axes without an entry are selected whole. e.g.
+ x.Slice(Span(1, 3, 1), At(0))
+ returns rows 1 and 2 of column 0 as a one dimensional array.
+*/
+func (x *GsArray) Slice(idx ...Index) *GsArray {
+	return must(x.TrySlice(idx...))
+}
+
+/*
+ Same as Slice but returns an error instead of panicking
+*/
+func (x *GsArray) TrySlice(idx ...Index) (*GsArray, error) {
+	strides := x.getStrides()
+	consumed, ellipses := 0, 0
+	for _, index := range idx {
+		switch index.kind {
+		case ellipsisIndex:
+			ellipses++
+		case spanIndex, atIndex:
+			consumed++
+		}
+	}
+	if ellipses > 1 {
+		return nil, fmt.Errorf("%w: more than one Ellipsis", ErrInvalidSlice)
+	}
+	if consumed > len(x.shape) {
+		return nil, &IndexOutOfRangeError{Axis: -1, Index: consumed, Size: len(x.shape)}
+	}
+	view := &GsArray{data: x.data, offset: x.offset}
+	view.shape = make([]int, 0, len(x.shape))
+	view.strides = make([]int, 0, len(x.shape))
+	axis := 0
+	for _, index := range idx {
+		switch index.kind {
+		case newAxisIndex:
+			view.shape = append(view.shape, 1)
+			view.strides = append(view.strides, 0)
+		case ellipsisIndex:
+			for n := len(x.shape) - consumed; n > 0; n-- {
+				view.shape = append(view.shape, x.shape[axis])
+				view.strides = append(view.strides, strides[axis])
+				axis++
+			}
+		case atIndex:
+			i := index.start
+			if i < 0 {
+				i += x.shape[axis]
+			}
+			if i < 0 || i >= x.shape[axis] {
+				return nil, &IndexOutOfRangeError{Axis: axis, Index: index.start, Size: x.shape[axis]}
+			}
+			view.offset += i * strides[axis]
+			axis++
+		case spanIndex:
+			if index.step == 0 {
+				return nil, fmt.Errorf("%w: zero step", ErrInvalidSlice)
+			}
+			start, length, step := index.resolve(x.shape[axis])
+			if length > 0 {
+				view.offset += start * strides[axis]
+			}
+			view.shape = append(view.shape, length)
+			view.strides = append(view.strides, strides[axis]*step)
+			axis++
+		}
+	}
+	for ; axis < len(x.shape); axis++ {
+		view.shape = append(view.shape, x.shape[axis])
+		view.strides = append(view.strides, strides[axis])
+	}
+	view.pack()
+	return view, nil
+}
+
+/*
+ Returns the first position, number of elements and step selected by a span
+ on an axis of length n, following numpy's rules for out of range bounds.
+*/
+func (index Index) resolve(n int) (start, length, step int) {
+	step = index.step
+	start, stop := index.start, index.stop
+	lower, upper := 0, n
+	if step < 0 {
+		lower, upper = -1, n-1
+	}
+	if start == Omit {
+		start = lower
+		if step < 0 {
+			start = upper
+		}
+	} else {
+		start = clampBound(start, n, lower, upper)
+	}
+	if stop == Omit {
+		stop = upper
+		if step < 0 {
+			stop = lower
+		}
+	} else {
+		stop = clampBound(stop, n, lower, upper)
+	}
+	if step > 0 && stop > start {
+		length = (stop - start + step - 1) / step
+	} else if step < 0 && start > stop {
+		length = (start - stop - step - 1) / -step
+	}
+	return start, length, step
+}
+
+func clampBound(bound, n, lower, upper int) int {
+	if bound < 0 {
+		bound += n
+	}
+	if bound < lower {
+		return lower
+	}
+	if bound > upper {
+		return upper
+	}
+	return bound
+}
+
+/*
+ Returns a copy of x that does not share data with any other array
+*/
+func (x *GsArray) Copy() *GsArray {
+	result := Zeros(x.Shape()...)
+	if x.strides == nil {
+		copy(result.data, x.data)
+		return result
+	}
+	i := 0
+	x.walk(func(j int) {
+		result.data[i] = x.data[j]
+		i++
+	})
+	return result
+}
+
+/*
+ Returns the shape of the array
+*/
+func (x *GsArray) Shape() []int {
+	shape := make([]int, len(x.shape))
+	copy(shape, x.shape)
+	return shape
+}
+
+/*
+ Returns the number of elements in the array
+*/
+func (x *GsArray) Size() int {
+	size := 1
+	for _, dim := range x.shape {
+		size *= dim
+	}
+	return size
+}
+
+/*
+ Returns the stride of each axis in elements of the data slice
+*/
+func (x *GsArray) getStrides() []int {
+	if x.strides == nil {
+		return contiguousStrides(x.shape)
+	}
+	return x.strides
+}
+
+/*
+ Returns the elements of x in row major order. The slice is shared with x
+ when x is packed and is a fresh copy otherwise, so it must not be written to.
+*/
+func (x *GsArray) values() []float64 {
+	if x.strides == nil {
+		return x.data
+	}
+	return x.Copy().data
+}
+
+/*
+ Calls visit with the index into data of every element of x in row major order
+*/
+func (x *GsArray) walk(visit func(i int)) {
+	if x.strides == nil {
+		for i := range x.data {
+			visit(i)
+		}
+		return
+	}
+	walkStrided(x.shape, []int{x.offset}, [][]int{x.strides}, func(pos []int) {
+		visit(pos[0])
+	})
+}
+
+/*
+ Drops the strides of a view whose elements are laid out in row major order,
+ narrowing data to just those elements so the packed fast paths apply.
+*/
+func (x *GsArray) pack() {
+	size := x.Size()
+	if size == 0 {
+		x.data, x.strides, x.offset = x.data[:0], nil, 0
+		return
+	}
+	step := 1
+	for i := len(x.shape) - 1; i >= 0; i-- {
+		if x.shape[i] != 1 && x.strides[i] != step {
+			return
+		}
+		step *= x.shape[i]
+	}
+	x.data = x.data[x.offset : x.offset+size]
+	x.strides, x.offset = nil, 0
+}
+
+/*
+ Returns the transpose of x as a view sharing its data. For a matrix this
+ swaps rows and columns, for higher dimensions the order of the axes is
+ reversed.
+*/
+func (x *GsArray) T() *GsArray {
+	return x.Transpose()
+}
+
+/*
+ Returns a view of x with its axes permuted so that axis i of the result is
+ axis axes[i] of x. With no arguments the axes are reversed. Negative axes
+ count back from the last axis.
+*/
+func (x *GsArray) Transpose(axes ...int) *GsArray {
+	return must(x.TryTranspose(axes...))
+}
+
+/*
+ Same as Transpose but returns an error instead of panicking
+*/
+func (x *GsArray) TryTranspose(axes ...int) (*GsArray, error) {
+	n := len(x.shape)
+	if len(axes) == 0 {
+		axes = make([]int, n)
+		for i := range axes {
+			axes[i] = n - 1 - i
+		}
+	}
+	if len(axes) != n {
+		return nil, shapeError("Transpose", x.shape, axes)
+	}
+	strides := x.getStrides()
+	view := &GsArray{data: x.data, offset: x.offset}
+	view.shape = make([]int, n)
+	view.strides = make([]int, n)
+	seen := make([]bool, n)
+	for i, axis := range axes {
+		axis, err := checkAxis(axis, n)
+		if err != nil {
+			return nil, err
+		}
+		if seen[axis] {
+			return nil, shapeError("Transpose", x.shape, axes)
+		}
+		seen[axis] = true
+		view.shape[i] = x.shape[axis]
+		view.strides[i] = strides[axis]
+	}
+	view.pack()
+	return view, nil
+}
+
+/*
+ Returns a view of x with axes a and b interchanged
+*/
+func (x *GsArray) SwapAxes(a, b int) *GsArray {
+	return must(x.TrySwapAxes(a, b))
+}
+
+/*
+ Same as SwapAxes but returns an InvalidAxisError instead of panicking
+*/
+func (x *GsArray) TrySwapAxes(a, b int) (*GsArray, error) {
+	n := len(x.shape)
+	a, err := checkAxis(a, n)
+	if err != nil {
+		return nil, err
+	}
+	b, err = checkAxis(b, n)
+	if err != nil {
+		return nil, err
+	}
+	axes := make([]int, n)
+	for i := range axes {
+		axes[i] = i
+	}
+	axes[a], axes[b] = b, a
+	return x.TryTranspose(axes...)
+}
+
+/*
+ Returns a view of x with axis source moved to position destination and the
+ other axes kept in their original order
+*/
+func (x *GsArray) MoveAxis(source, destination int) *GsArray {
+	return must(x.TryMoveAxis(source, destination))
+}
+
+/*
+ Same as MoveAxis but returns an InvalidAxisError instead of panicking
+*/
+func (x *GsArray) TryMoveAxis(source, destination int) (*GsArray, error) {
+	n := len(x.shape)
+	source, err := checkAxis(source, n)
+	if err != nil {
+		return nil, err
+	}
+	destination, err = checkAxis(destination, n)
+	if err != nil {
+		return nil, err
+	}
+	axes := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		if i != source {
+			axes = append(axes, i)
+		}
+	}
+	axes = append(axes[:destination], append([]int{source}, axes[destination:]...)...)
+	return x.TryTranspose(axes...)
+}
+
+/*
+ Returns how the matrix x can be handed to BLAS without copying: the data
+ starting at its first element, whether that data holds x transposed and the
+ leading dimension. Layouts BLAS can not describe are copied first.
+*/
+func blasMatrix(x *GsArray) (data []float64, trans bool, ld int) {
+	rows, cols := x.shape[0], x.shape[1]
+	strides := x.getStrides()
+	if ld, ok := leadingDim(rows, cols, strides[0], strides[1]); ok {
+		return x.data[x.offset:], false, ld
+	}
+	if ld, ok := leadingDim(cols, rows, strides[1], strides[0]); ok {
+		return x.data[x.offset:], true, ld
+	}
+	return x.Copy().data, false, cols
+}
+
+/*
+ Returns the leading dimension of a rows by cols matrix stored row major with
+ the given strides, and false if the strides are not a row major layout
+*/
+func leadingDim(rows, cols, rowStride, colStride int) (int, bool) {
+	if cols != 1 && colStride != 1 {
+		return 0, false
+	}
+	ld := rowStride
+	if rows == 1 {
+		ld = max(cols, 1)
+	}
+	return ld, ld >= cols && ld >= 1
+}
+
+/*
+ Reports whether x is a matrix BLAS can write its result into directly
+*/
+func (x *GsArray) blasWritable() bool {
+	strides := x.getStrides()
+	_, ok := leadingDim(x.shape[0], x.shape[1], strides[0], strides[1])
+	return ok
+}
+
+/*
+ Reports whether x is a vector: one dimensional or a matrix with a single row or column
+*/
+func isVector(x *GsArray) bool {
+	return len(x.shape) == 1 || len(x.shape) == 2 && (x.shape[0] == 1 || x.shape[1] == 1)
+}
+
+/*
+ Returns the increment between the elements of the vector x in its data
+*/
+func vectorInc(x *GsArray) int {
+	strides := x.getStrides()
+	inc := 1
+	for i, dim := range x.shape {
+		if dim != 1 {
+			inc = strides[i]
+		}
+	}
+	return inc
+}
+
+/*
+ Returns the data of the vector x starting at its first element and the
+ increment between elements, copying x if BLAS can not describe its layout
+*/
+func blasVector(x *GsArray) (data []float64, inc int) {
+	if inc = vectorInc(x); inc >= 1 {
+		return x.data[x.offset:], inc
+	}
+	return x.Copy().data, 1
+}
+
+/*
+ Writes vals, given in row major order, into the elements of x
+*/
+func (x *GsArray) setValues(vals []float64) {
+	i := 0
+	x.walk(func(j int) {
+		x.data[j] = vals[i]
+		i++
+	})
+}
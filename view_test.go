@@ -0,0 +1,198 @@
+package goSci
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestSliceSharesData(t *testing.T) {
+	x := Arange(12)
+	x.Reshape(3, 4)
+
+	v := x.Slice(Span(0, 3, 2), Span(1, Omit, 2))
+	assertArray(t, "strided view", v, []int{2, 2}, []float64{1, 3, 9, 11})
+	v.Put(-1, 1, 0)
+	if x.Get(2, 1) != -1 {
+		t.Errorf("Put on a view did not write through, x[2, 1] = %v", x.Get(2, 1))
+	}
+	v.PlusEquals(Ones(1))
+	assertArray(t, "PlusEquals through a view", x, []int{3, 4},
+		[]float64{0, 2, 2, 4, 4, 5, 6, 7, 8, 0, 10, 12})
+
+	col := x.Slice(All, At(-1))
+	col.TimesEquals(0)
+	for i := 0; i < 3; i++ {
+		if x.Get(i, 3) != 0 {
+			t.Errorf("TimesEquals on a column view left x[%d, 3] = %v", i, x.Get(i, 3))
+		}
+	}
+
+	// Copy breaks the sharing
+	c := v.Copy()
+	c.Put(100, 0, 0)
+	if x.Get(0, 1) == 100 {
+		t.Error("Copy of a view shares data with x")
+	}
+	if c.strides != nil || len(c.data) != 4 {
+		t.Errorf("Copy of a view is not packed: strides %v, %d elements", c.strides, len(c.data))
+	}
+}
+
+func TestSliceIndices(t *testing.T) {
+	x := Arange(12)
+	x.Reshape(3, 4)
+
+	assertArray(t, "reversed rows", x.Slice(Span(Omit, Omit, -1)).Copy(), []int{3, 4},
+		[]float64{8, 9, 10, 11, 4, 5, 6, 7, 0, 1, 2, 3})
+	assertArray(t, "At drops the axis", x.Slice(At(1)), []int{4}, []float64{4, 5, 6, 7})
+	assertArray(t, "NewAxis and Ellipsis", x.Slice(Ellipsis, NewAxis).Copy(), []int{3, 4, 1},
+		[]float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11})
+	if got := x.Slice(Span(5, 10, 1)).Shape(); got[0] != 0 {
+		t.Errorf("out of range Span: shape %v", got)
+	}
+	if _, err := x.TrySlice(Span(0, 1, 0)); !errors.Is(err, ErrInvalidSlice) {
+		t.Errorf("zero step: got %v", err)
+	}
+	if _, err := x.TrySlice(Ellipsis, Ellipsis); !errors.Is(err, ErrInvalidSlice) {
+		t.Errorf("two ellipses: got %v", err)
+	}
+	var ie *IndexOutOfRangeError
+	if _, err := x.TrySlice(At(3)); !errors.As(err, &ie) {
+		t.Errorf("At out of range: got %v", err)
+	}
+}
+
+func TestSlicePack(t *testing.T) {
+	x := Arange(12)
+	x.Reshape(3, 4)
+
+	// whole rows are contiguous, so the view drops its strides and the
+	// packed fast paths apply, while still sharing data with x
+	rows := x.Slice(Span(1, 3, 1))
+	if rows.strides != nil || len(rows.data) != 8 {
+		t.Fatalf("row view is not packed: strides %v, %d elements", rows.strides, len(rows.data))
+	}
+	rows.Put(-5, 0, 0)
+	if x.Get(1, 0) != -5 {
+		t.Error("packed view does not share data with x")
+	}
+	if err := rows.TryReshape(8); err != nil {
+		t.Errorf("Reshape of a packed view: %v", err)
+	}
+	if cols := x.Slice(All, Span(0, 2, 1)); cols.strides == nil {
+		t.Error("a view of part of each row was packed")
+	}
+}
+
+func TestOverlappingViews(t *testing.T) {
+	x := Arange(6)
+	x.Reshape(3, 2)
+	// adding rows 0:2 into rows 1:3 must read the values from before the update
+	x.Slice(Span(1, 3, 1)).PlusEquals(x.Slice(Span(0, 2, 1)))
+	assertArray(t, "overlapping PlusEquals", x, []int{3, 2}, []float64{0, 1, 2, 4, 6, 8})
+
+	y := Arange(4)
+	y.ElemTimesEquals(y)
+	assertArray(t, "ElemTimesEquals with itself", y, []int{4}, []float64{0, 1, 4, 9})
+}
+
+func TestTransposeViews(t *testing.T) {
+	x := Arange(24)
+	x.Reshape(2, 3, 4)
+
+	cases := []struct {
+		name    string
+		view    *GsArray
+		shape   []int
+		strides []int
+	}{
+		{"T", x.T(), []int{4, 3, 2}, []int{1, 4, 12}},
+		{"Transpose", x.Transpose(1, 2, 0), []int{3, 4, 2}, []int{4, 1, 12}},
+		{"negative axes", x.Transpose(-1, 0, 1), []int{4, 2, 3}, []int{1, 12, 4}},
+		{"SwapAxes", x.SwapAxes(0, -1), []int{4, 3, 2}, []int{1, 4, 12}},
+		{"MoveAxis", x.MoveAxis(2, 0), []int{4, 2, 3}, []int{1, 12, 4}},
+	}
+	for _, c := range cases {
+		if !sameShape(c.view.shape, c.shape) || !sameShape(c.view.getStrides(), c.strides) {
+			t.Errorf("%s: shape %v strides %v, want %v and %v", c.name, c.view.shape, c.view.getStrides(), c.shape, c.strides)
+		}
+	}
+	v := x.Transpose(1, 2, 0)
+	if v.Get(2, 3, 1) != x.Get(1, 2, 3) {
+		t.Errorf("Transpose(1, 2, 0)[2, 3, 1] = %v, want x[1, 2, 3] = %v", v.Get(2, 3, 1), x.Get(1, 2, 3))
+	}
+	// the identity permutation and a transpose of a transpose are packed again
+	if x.Transpose(0, 1, 2).strides != nil || x.T().T().strides != nil {
+		t.Error("transpose back to row major order is not packed")
+	}
+
+	// views share data with x
+	x.T().Put(-1, 3, 2, 1)
+	if x.Get(1, 2, 3) != -1 {
+		t.Error("Put on a transpose did not write through")
+	}
+	x.MoveAxis(0, -1).TimesEquals(0)
+	if CountNonzero(x) != 0 {
+		t.Error("TimesEquals on a MoveAxis view did not write through")
+	}
+
+	for name, err := range map[string]error{
+		"Transpose with a repeated axis": second(x.TryTranspose(0, 0, 1)),
+		"Transpose with too few axes":    second(x.TryTranspose(0, 1)),
+	} {
+		if !errors.As(err, new(*ShapeMismatchError)) {
+			t.Errorf("%s: got %v", name, err)
+		}
+	}
+	if _, err := x.TrySwapAxes(0, 3); !errors.As(err, new(*InvalidAxisError)) {
+		t.Errorf("SwapAxes with axis 3: got %v", err)
+	}
+	if _, err := x.TryMoveAxis(-4, 0); !errors.As(err, new(*InvalidAxisError)) {
+		t.Errorf("MoveAxis from axis -4: got %v", err)
+	}
+}
+
+func TestMatMultViews(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	a, b := randomArray(r, 6, 5), randomArray(r, 6, 7)
+
+	cases := []struct {
+		name string
+		x, y *GsArray
+	}{
+		{"transposed left", a.T(), b},
+		{"transposed right", b.T(), a},
+		{"both transposed", a.Slice(Span(0, 4, 1)).T(), b.Slice(Span(1, 5, 1)).T().T()},
+		{"sub-blocks", a.Slice(Span(1, 4, 1), Span(1, 4, 1)), b.Slice(Span(2, 5, 1), Span(0, 7, 2))},
+		{"rows with a step", a.Slice(Span(0, 6, 2)), b.Slice(Span(Omit, Omit, -1), Span(0, 5, 1)).Slice(Span(0, 5, 1))},
+		{"column vector", a, b.Slice(Span(0, 5, 1), Span(2, 3, 1))},
+	}
+	for _, c := range cases {
+		assertClose(t, c.name, MatMult(c.x, c.y), MatMult(c.x.Copy(), c.y.Copy()), 1e-12)
+		assertClose(t, c.name+" against loops", MatMult(c.x, c.y), naiveProduct(c.x, c.y), 1e-12)
+	}
+
+	// Gemm writes through a strided view of a larger matrix
+	c := Zeros(5, 8)
+	Gemm(1, a.T(), a.Slice(All, Span(0, 4, 1)), 0, c.Slice(All, Span(0, 8, 2)))
+	assertClose(t, "Gemm into a strided view", c.Slice(All, Span(0, 8, 2)), naiveProduct(a.T(), a.Slice(All, Span(0, 4, 1))), 1e-12)
+	assertClose(t, "untouched columns", c.Slice(All, Span(1, 8, 2)), Zeros(5, 4), 0)
+}
+
+/*
+ Returns the matrix product of x and y by the textbook triple loop
+*/
+func naiveProduct(x, y *GsArray) *GsArray {
+	z := Zeros(x.shape[0], y.shape[1])
+	for i := 0; i < x.shape[0]; i++ {
+		for j := 0; j < y.shape[1]; j++ {
+			sum := 0.0
+			for k := 0; k < x.shape[1]; k++ {
+				sum += x.Get(i, k) * y.Get(k, j)
+			}
+			z.Put(sum, i, j)
+		}
+	}
+	return z
+}